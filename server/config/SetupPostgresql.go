@@ -33,9 +33,43 @@ func SetupDatabase() (*gorm.DB, error) {
 		log.Fatalf("Gagal terhubung ke database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&entity.Appliance{}, &entity.Users{}); err != nil {
+	if err := dedupeAppliances(db); err != nil {
+		log.Fatalf("Error saat membersihkan appliance duplikat: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.Appliance{}, &entity.Users{}, &entity.Reading{}, &entity.MappingProfile{}, &entity.ImportJob{}, &entity.Tariff{}, &entity.TokenPurchase{}, &entity.ReferenceAppliance{}); err != nil {
 		log.Fatalf("Error saat melakukan migrasi: %v", err)
 	}
 
 	return db, nil
 }
+
+// dedupeAppliances menyiapkan database lama sebelum index unik idx_appliances_user_name dibuat. Untuk setiap
+// (user, nama) yang aktif lebih dari sekali, appliance terbaru dipertahankan: reading appliance lain dipindahkan
+// ke appliance tersebut lalu appliance lain di-soft-delete.
+func dedupeAppliances(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.Appliance{}) || migrator.HasIndex(&entity.Appliance{}, "idx_appliances_user_name") {
+		return nil
+	}
+
+	const duplicates = `SELECT id, FIRST_VALUE(id) OVER (PARTITION BY user_id, name ORDER BY id DESC) AS keep_id
+		FROM appliances WHERE deleted_at IS NULL`
+	return db.Transaction(func(tx *gorm.DB) error {
+		if migrator.HasTable(&entity.Reading{}) {
+			err := tx.Exec(`UPDATE readings SET appliance_id = d.keep_id FROM (`+duplicates+`) d
+				WHERE readings.appliance_id = d.id AND d.id <> d.keep_id`).Error
+			if err != nil {
+				return err
+			}
+		}
+		result := tx.Exec(`UPDATE appliances SET deleted_at = NOW() FROM (`+duplicates+`) d
+			WHERE appliances.id = d.id AND d.id <> d.keep_id`)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("removed %d duplicate appliances before creating idx_appliances_user_name", result.RowsAffected)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"smart-home-energy-management-server/internal/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID mengambil ID user dari claims yang disimpan AuthMiddleware.
+// Jika tidak tersedia, response 401 langsung dikirim dan ok bernilai false.
+func currentUserID(c *gin.Context) (uint, bool) {
+	userData, _ := c.Get("user_data")
+	userID, err := helper.GetUserID(userData)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":     false,
+			"statusCode": 401,
+			"message":    err.Error(),
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// queryTimeRange membaca parameter query from & to; nilai kosong berarti tanpa batas
+func queryTimeRange(c *gin.Context) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if raw := c.Query("from"); raw != "" {
+		if from, err = helper.ParseTimestamp(raw); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = helper.ParseTimestamp(raw); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+
+	return from, to, nil
+}
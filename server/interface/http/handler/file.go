@@ -7,8 +7,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"smart-home-energy-management-server/internal/entity"
 	"smart-home-energy-management-server/internal/helper"
@@ -21,89 +22,74 @@ type fileHandler struct {
 	applianceService      service.ApplianceService
 	fileService           service.FileService
 	recommendationService service.RecommendationService
+	readingService        service.ReadingService
+	mappingProfileService service.MappingProfileService
+	importService         service.ImportService
+	importJobService      service.ImportJobService
+	tariffService         service.TariffService
+	fetcher               *helper.Fetcher
 }
 
-func NewFileHandler(applianceService service.ApplianceService, fileService service.FileService, recommendationService service.RecommendationService) fileHandler {
+func NewFileHandler(applianceService service.ApplianceService, fileService service.FileService, recommendationService service.RecommendationService, readingService service.ReadingService, mappingProfileService service.MappingProfileService, importService service.ImportService, importJobService service.ImportJobService, tariffService service.TariffService, fetcher *helper.Fetcher) fileHandler {
 	return fileHandler{
 		applianceService:      applianceService,
 		fileService:           fileService,
 		recommendationService: recommendationService,
+		readingService:        readingService,
+		mappingProfileService: mappingProfileService,
+		importService:         importService,
+		importJobService:      importJobService,
+		tariffService:         tariffService,
+		fetcher:               fetcher,
 	}
 }
 
 func (h *fileHandler) UploadFileCSV(c *gin.Context) {
-	var inputs struct {
-		URL string `json:"url"`
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
 	}
 
-	// Bind request body ke struct inputs
-	err := c.ShouldBindJSON(&inputs)
+	// Membuka dan mem-parsing file satu kali menggunakan profil mapping kolom
+	file, opts, statusCode, err := readUploadRequest(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(statusCode, gin.H{
 			"status":     false,
-			"statusCode": 400,
+			"statusCode": statusCode,
 			"message":    err.Error(),
 		})
 		return
 	}
 
-	// Membaca file CSV
-	result, err := helper.ReadCSV(inputs.URL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":     false,
-			"statusCode": 500,
-			"message":    err.Error(),
-		})
+	// File besar dapat diproses di background sebagai import job
+	if opts.Async {
+		h.submitImportJob(c, userID, file, opts)
 		return
 	}
 
-	// Parsing CSV ke struct
-	appliances, err := helper.ParseCSVtoSliceOfStruct(inputs.URL)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":     false,
-			"statusCode": 500,
-			"message":    err.Error(),
-		})
+	parsed, profile, ok := h.parseUpload(c, userID, file, opts)
+	if !ok {
 		return
 	}
+	result, appliances, readings := parsed.Table, parsed.Appliances, parsed.Readings
 
-	// Simpan data ke database
-	var wg sync.WaitGroup
-	var errors []string
-	var mu sync.Mutex
-
-	// Truncate table appliances
-	if err := h.applianceService.TruncateAppliances(); err != nil {
+	report, err := h.importReport(userID, parsed, profile, opts.DryRun)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
 			"statusCode": 500,
-			"message":    "failed truncate appliances: " + err.Error(),
+			"message":    err.Error(),
 		})
 		return
 	}
-	for i := 0; i < len(appliances); i++ {
-		// Goroutine untuk insert data ke database
-		wg.Add(1)
-		go func(appliance entity.ApplianceRequest) {
-			defer wg.Done()
-			_, err := h.applianceService.CreateAppliance(&appliance)
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, err.Error())
-				mu.Unlock()
-			}
-		}(appliances[i])
-	}
-
-	wg.Wait()
 
-	if len(errors) > 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":     false,
-			"statusCode": 500,
-			"message":    errors,
+	// Dry run hanya mengembalikan laporan tanpa menyentuh database maupun redis
+	if opts.DryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"status":     true,
+			"statusCode": 200,
+			"message":    "Dry run upload success",
+			"data":       report,
 		})
 		return
 	}
@@ -113,6 +99,7 @@ func (h *fileHandler) UploadFileCSV(c *gin.Context) {
 			"status":     false,
 			"statusCode": 400,
 			"message":    "no appliances parsed from csv",
+			"report":     report,
 		})
 		return
 	}
@@ -128,12 +115,17 @@ func (h *fileHandler) UploadFileCSV(c *gin.Context) {
 		return
 	}
 
-	// Simpan table ke redis
-	if err = h.fileService.SaveTable(string(jsonResult)); err != nil {
+	// Simpan appliance, reading, dan tabel redis secara atomik; jika gagal data sebelumnya tetap utuh
+	importFile := h.importService.ReplaceImport
+	if opts.Mode == entity.ImportModeMerge {
+		importFile = h.importService.MergeImport
+	}
+	importResult, err := importFile(c.Request.Context(), userID, string(jsonResult), appliances, readings)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
 			"statusCode": 500,
-			"message":    err.Error(),
+			"message":    "failed import: " + err.Error(),
 		})
 		return
 	}
@@ -143,12 +135,19 @@ func (h *fileHandler) UploadFileCSV(c *gin.Context) {
 		"statusCode": 200,
 		"message":    "Upload table success",
 		"data":       result,
+		"report":     report,
+		"result":     importResult,
 	})
 }
 
 func (h *fileHandler) GetTable(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	// Mendapatkan table dari redis cache
-	table, err := h.fileService.GetTable()
+	table, err := h.fileService.GetTable(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -167,7 +166,12 @@ func (h *fileHandler) GetTable(c *gin.Context) {
 }
 
 func (h *fileHandler) GetRawTable(c *gin.Context) {
-	raw, err := h.fileService.GetRawTable()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	raw, err := h.fileService.GetRawTable(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -279,6 +283,11 @@ func (h *fileHandler) Chat(c *gin.Context) {
 }
 
 func (h *fileHandler) TapasChat(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	// Mendapatkan URL dan Token dari environment variable
 	tapasURL := os.Getenv("HUGGINGFACE_API_TAPAS_URL")
 	marianmtURL := os.Getenv("HUGGINGFACE_API_MARIANMT_URL")
@@ -301,7 +310,7 @@ func (h *fileHandler) TapasChat(c *gin.Context) {
 	}
 
 	// Mendapatkan table dari redis cache
-	inputs.Table, err = h.fileService.GetTable()
+	inputs.Table, err = h.fileService.GetTable(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -429,7 +438,12 @@ func (h *fileHandler) TapasChat(c *gin.Context) {
 }
 
 func (h *fileHandler) GetAppliance(c *gin.Context) {
-	appliances, err := h.applianceService.GetAllAppliances()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	appliances, err := h.applianceService.GetAllAppliances(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -448,7 +462,12 @@ func (h *fileHandler) GetAppliance(c *gin.Context) {
 }
 
 func (h *fileHandler) GetAllAppliance(c *gin.Context) {
-	result, err := h.fileService.GetTable()
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.fileService.GetTable(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -466,9 +485,24 @@ func (h *fileHandler) GetAllAppliance(c *gin.Context) {
 	})
 }
 
+// noTariffInEffect membalas 400 jika tarif yang dipilih tidak berlaku pada bulan at
+func noTariffInEffect(c *gin.Context, at time.Time) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"status":     false,
+		"statusCode": 400,
+		"message":    "no tariff in effect for " + at.Format("2006-01"),
+	})
+}
+
 func (h *fileHandler) GenerateMonthlyRecommendations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var userInputs struct {
-		Golongan   string  `json:"golongan"` // INPUT
+		TariffCode string  `json:"tariff_code"` // INPUT
+		Golongan   string  `json:"golongan"`    // INPUT (lama), dipakai jika tariff_code kosong
 		Tarif      float64 `json:"tarif"`
 		MaksBiaya  float64 `json:"maks_biaya"` // INPUT
 		MaksEnergi float64 `json:"maks_energi"`
@@ -486,10 +520,34 @@ func (h *fileHandler) GenerateMonthlyRecommendations(c *gin.Context) {
 		return
 	}
 
-	userInputs.Tarif = helper.GetTarif(userInputs.Golongan)
-	userInputs.MaksEnergi = userInputs.MaksBiaya / userInputs.Tarif
-	userInputs.Hari, _ = helper.JumlahHariDalamBulan(userInputs.Tanggal)
-	appliances, err := h.applianceService.GetAllAppliances()
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(userInputs.TariffCode, userInputs.Golongan))
+	if !ok {
+		return
+	}
+	// Harga dan jumlah hari yang dipakai adalah milik bulan yang direkomendasikan; tanpa tanggal, bulan ini
+	month := time.Now()
+	if userInputs.Tanggal != "" {
+		tanggal, err := helper.ParseTimestamp(userInputs.Tanggal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    "invalid tanggal: " + err.Error(),
+			})
+			return
+		}
+		month = tanggal
+	}
+	// Anggaran diubah ke energi dengan MonthlyCost yang juga dipakai simulasi; Tarif adalah harga rata-rata
+	// pada anggaran tersebut sehingga tarif block ikut dihitung per blok
+	userInputs.MaksEnergi = tariff.MonthlyEnergy(month, userInputs.MaksBiaya)
+	userInputs.Tarif = tariff.MonthlyRate(month, userInputs.MaksEnergi)
+	if userInputs.Tarif <= 0 {
+		noTariffInEffect(c, month)
+		return
+	}
+	userInputs.Hari = time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, month.Location()).Day()
+	appliances, err := h.applianceService.GetAllAppliances(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -499,9 +557,20 @@ func (h *fileHandler) GenerateMonthlyRecommendations(c *gin.Context) {
 		return
 	}
 
-	result := helper.PrintRecommendationsMonthlyUsage(appliances, userInputs.Tarif, userInputs.Hari, userInputs.MaksEnergi)
+	plan, err := helper.OptimizeMonthlyBudget(appliances, userInputs.Tarif, userInputs.Hari, userInputs.MaksEnergi)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+	helper.ScheduleBudgetPlan(&plan, tariff, month)
+	recommendations := helper.BudgetRecommendations(plan)
+	result := helper.PrintRecommendationsMonthlyUsage(plan)
 
-	if err = h.recommendationService.SaveRecommendation(result); err != nil {
+	if err = h.recommendationService.SaveRecommendation(userID, entity.RecommendationScopeMonthly, recommendations); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
 			"statusCode": 500,
@@ -511,17 +580,25 @@ func (h *fileHandler) GenerateMonthlyRecommendations(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":     true,
-		"statusCode": 200,
-		"message":    "Recommendations generated",
-		"data":       result,
+		"status":          true,
+		"statusCode":      200,
+		"message":         "Recommendations generated",
+		"data":            result,
+		"allocation":      plan,
+		"recommendations": helper.RenderRecommendations(recommendations, c.DefaultQuery("lang", helper.LanguageID)),
 	})
 }
 
 func (h *fileHandler) GenerateDailyRecommendations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var userInputs struct {
-		Golongan string  `json:"golongan"` // INPUT
-		Tarif    float64 `json:"tarif"`
+		TariffCode string  `json:"tariff_code"` // INPUT
+		Golongan   string  `json:"golongan"`    // INPUT (lama), dipakai jika tariff_code kosong
+		Tarif      float64 `json:"tarif"`
 	}
 
 	err := c.ShouldBindJSON(&userInputs)
@@ -534,9 +611,25 @@ func (h *fileHandler) GenerateDailyRecommendations(c *gin.Context) {
 		return
 	}
 
-	userInputs.Tarif = helper.GetTarif(userInputs.Golongan)
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(userInputs.TariffCode, userInputs.Golongan))
+	if !ok {
+		return
+	}
+	// Penggunaan "hari ini" dihitung dari hari reading terakhir, sehingga harganya mengikuti tanggal tersebut
+	usageDay, err := h.readingService.GetLastReadingAt(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+	if usageDay.IsZero() {
+		usageDay = time.Now()
+	}
 
-	appliances, err := h.applianceService.GetAllAppliances()
+	appliances, err := h.applianceService.GetAllAppliances(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":     false,
@@ -546,6 +639,29 @@ func (h *fileHandler) GenerateDailyRecommendations(c *gin.Context) {
 		return
 	}
 
+	// Jam pemakaian tidak diketahui, sehingga Tarif adalah harga rata-rata MonthlyCost untuk konsumsi harian
+	// AverageUsage selama sebulan: tarif block ikut dihitung per blok, tarif tou memakai harga dasar
+	var dailyEnergy float64
+	for _, energy := range helper.AverageConsumptionProfile(appliances) {
+		dailyEnergy += energy
+	}
+	_, monthEnd, _ := helper.BucketBounds(usageDay, helper.BucketMonth)
+	userInputs.Tarif = tariff.MonthlyRate(usageDay, dailyEnergy*float64(monthEnd.AddDate(0, 0, -1).Day()))
+	if userInputs.Tarif <= 0 {
+		noTariffInEffect(c, usageDay)
+		return
+	}
+
+	recommendations := helper.DailyRecommendations(appliances, userInputs.Tarif)
+	if err = h.recommendationService.SaveRecommendation(userID, entity.RecommendationScopeDaily, recommendations); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
 	analysisResult := helper.PrintRecommendationsDailyUsage(appliances, userInputs.Tarif)
 
 	// Rekomendasi penggunaan
@@ -565,19 +681,78 @@ func (h *fileHandler) GenerateDailyRecommendations(c *gin.Context) {
 		"statusCode": 200,
 		"message":    "Recommendations generated",
 		"data": struct {
-			AnalysisResult []helper.DailySummary    `json:"analysis-result"`
-			Recommendation []helper.Recommendations `json:"recommendation"`
+			Tarif           float64                  `json:"tarif"` // harga rata-rata per kWh dari MonthlyRate
+			AnalysisResult  []helper.DailySummary    `json:"analysis-result"`
+			Recommendation  []helper.Recommendations `json:"recommendation"`
+			Recommendations []entity.Recommendation  `json:"recommendations"`
 		}{
-			AnalysisResult: analysisResult,
-			Recommendation: recommendation,
+			Tarif:           userInputs.Tarif,
+			AnalysisResult:  analysisResult,
+			Recommendation:  recommendation,
+			Recommendations: helper.RenderRecommendations(recommendations, c.DefaultQuery("lang", helper.LanguageID)),
 		},
 	})
 }
 
+// GetRecommendations mengembalikan rekomendasi tersimpan, terurut dari severity tertinggi dan penghematan terbesar.
+// Query kind, severity, dan appliance_id menyaring hasil; lang memilih bahasa teks (id/en).
+func (h *fileHandler) GetRecommendations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var applianceID uint64
+	if raw := c.Query("appliance_id"); raw != "" {
+		var err error
+		if applianceID, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    "invalid appliance_id",
+			})
+			return
+		}
+	}
+
+	recommendations, err := h.recommendationService.GetRecommendation(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	kind, severity := c.Query("kind"), c.Query("severity")
+	result := []entity.Recommendation{}
+	for _, recommendation := range recommendations {
+		if (kind != "" && recommendation.Kind != kind) ||
+			(severity != "" && recommendation.Severity != severity) ||
+			(applianceID != 0 && recommendation.ApplianceID != uint(applianceID)) {
+			continue
+		}
+		result = append(result, recommendation)
+	}
+	helper.SortRecommendations(result)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get recommendations success",
+		"data":       helper.RenderRecommendations(result, c.DefaultQuery("lang", helper.LanguageID)),
+	})
+}
+
 func (h *fileHandler) SetDailyTarget(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var dailyTarget struct {
-		Data  []helper.DailyTarget `json:"data"`
-		Email string               `json:"email"`
+		Data []helper.DailyTarget `json:"data"`
 	}
 
 	if err := c.ShouldBindJSON(&dailyTarget); err != nil {
@@ -590,7 +765,7 @@ func (h *fileHandler) SetDailyTarget(c *gin.Context) {
 		return
 	}
 
-	appliances, err := h.applianceService.SetDailyTarget(dailyTarget.Data)
+	appliances, err := h.applianceService.SetDailyTarget(userID, dailyTarget.Data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"statusCode": 500,
@@ -612,7 +787,7 @@ func (h *fileHandler) SetDailyTarget(c *gin.Context) {
 		return
 	}
 
-	err = h.applianceService.SaveDailyTarget(string(dailyTargetJSON), dailyTarget.Email)
+	err = h.applianceService.SaveDailyTarget(userID, string(dailyTargetJSON))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"statusCode": 500,
@@ -633,20 +808,12 @@ func (h *fileHandler) SetDailyTarget(c *gin.Context) {
 }
 
 func (h *fileHandler) GetDailyTarget(c *gin.Context) {
-	var Payload struct {
-		Email string `json:"email"`
-	}
-
-	if err := c.ShouldBindJSON(&Payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"statusCode": 400,
-			"status":     false,
-			"message":    err.Error(),
-		})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	dailyTarget, err := h.applianceService.GetDailyTarget(Payload.Email)
+	dailyTarget, err := h.applianceService.GetDailyTarget(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"statusCode": 500,
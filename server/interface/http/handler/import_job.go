@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type importJobHandler struct {
+	importJobService service.ImportJobService
+}
+
+func NewImportJobHandler(importJobService service.ImportJobService) importJobHandler {
+	return importJobHandler{importJobService: importJobService}
+}
+
+func importJobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrImportJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrImportJobFinished), errors.Is(err, service.ErrImportJobCommitting):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetImport mengembalikan status, progres, jumlah baris, dan error sebuah import job
+func (h *importJobHandler) GetImport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid import job id",
+		})
+		return
+	}
+
+	job, err := h.importJobService.GetJob(userID, uint(id))
+	if err != nil {
+		statusCode := importJobErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get import job success",
+		"data":       job,
+	})
+}
+
+// CancelImport membatalkan import job yang masih antri atau sedang berjalan
+func (h *importJobHandler) CancelImport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid import job id",
+		})
+		return
+	}
+
+	job, err := h.importJobService.CancelJob(userID, uint(id))
+	if err != nil {
+		statusCode := importJobErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+			"data":       job,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Cancel import job success",
+		"data":       job,
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mappingProfileHandler struct {
+	mappingProfileService service.MappingProfileService
+}
+
+func NewMappingProfileHandler(mappingProfileService service.MappingProfileService) mappingProfileHandler {
+	return mappingProfileHandler{mappingProfileService: mappingProfileService}
+}
+
+func mappingProfileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrMappingProfileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrMappingProfileExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (h *mappingProfileHandler) GetProfiles(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	profiles, err := h.mappingProfileService.GetProfiles(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get mapping profiles success",
+		"data":       profiles,
+	})
+}
+
+func (h *mappingProfileHandler) CreateProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.MappingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	profile, err := h.mappingProfileService.CreateProfile(userID, req)
+	if err != nil {
+		statusCode := mappingProfileErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":     true,
+		"statusCode": 201,
+		"message":    "Create mapping profile success",
+		"data":       profile,
+	})
+}
+
+func (h *mappingProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid mapping profile id",
+		})
+		return
+	}
+
+	var req entity.MappingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	profile, err := h.mappingProfileService.UpdateProfile(userID, uint(id), req)
+	if err != nil {
+		statusCode := mappingProfileErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Update mapping profile success",
+		"data":       profile,
+	})
+}
+
+func (h *mappingProfileHandler) DeleteProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid mapping profile id",
+		})
+		return
+	}
+
+	if err := h.mappingProfileService.DeleteProfile(userID, uint(id)); err != nil {
+		statusCode := mappingProfileErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Delete mapping profile success",
+	})
+}
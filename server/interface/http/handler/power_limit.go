@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type powerLimitHandler struct {
+	powerLimitService service.PowerLimitService
+	tariffService     service.TariffService
+}
+
+func NewPowerLimitHandler(powerLimitService service.PowerLimitService, tariffService service.TariffService) powerLimitHandler {
+	return powerLimitHandler{powerLimitService: powerLimitService, tariffService: tariffService}
+}
+
+// AnalyzePowerLimit menandai jam dengan beban serentak di atas daya tersambung beserta saran pemindahan jam
+// dan perbandingan biaya naik daya
+func (h *powerLimitHandler) AnalyzePowerLimit(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PowerLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(req.TariffCode, req.Golongan))
+	if !ok {
+		return
+	}
+
+	analysis, err := h.powerLimitService.Analyze(userID, req, tariff)
+	if err != nil {
+		statusCode := requestErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Analyze power limit success",
+		"data":       analysis,
+	})
+}
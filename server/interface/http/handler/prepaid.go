@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type prepaidHandler struct {
+	prepaidService service.PrepaidService
+}
+
+func NewPrepaidHandler(prepaidService service.PrepaidService) prepaidHandler {
+	return prepaidHandler{prepaidService: prepaidService}
+}
+
+func prepaidErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTokenPurchaseNotFound), errors.Is(err, service.ErrNoTokenPurchases):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (h *prepaidHandler) CreatePurchase(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.TokenPurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	purchase, err := h.prepaidService.CreatePurchase(userID, req)
+	if err != nil {
+		statusCode := prepaidErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":     true,
+		"statusCode": 201,
+		"message":    "Create token purchase success",
+		"data":       purchase,
+	})
+}
+
+func (h *prepaidHandler) GetPurchases(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	purchases, err := h.prepaidService.GetPurchases(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get token purchases success",
+		"data":       purchases,
+	})
+}
+
+func (h *prepaidHandler) DeletePurchase(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid token purchase id",
+		})
+		return
+	}
+
+	if err := h.prepaidService.DeletePurchase(userID, uint(id)); err != nil {
+		statusCode := prepaidErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Delete token purchase success",
+	})
+}
+
+// GetForecast menerima query days (jumlah hari rata-rata pemakaian) dan alert_kwh (batas saldo untuk peringatan)
+func (h *prepaidHandler) GetForecast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	windowDays := helper.DefaultForecastWindowDays
+	if raw := c.Query("days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 1 || days > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    "days must be between 1 and 90",
+			})
+			return
+		}
+		windowDays = days
+	}
+
+	var threshold *float64
+	if raw := c.Query("alert_kwh"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    "alert_kwh must be a non-negative number",
+			})
+			return
+		}
+		threshold = &value
+	}
+
+	forecast, err := h.prepaidService.GetForecast(userID, windowDays, threshold)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNoTokenPurchases) {
+			statusCode = prepaidErrorStatus(err)
+		}
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get prepaid forecast success",
+		"data":       forecast,
+	})
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type readingHandler struct {
+	applianceService service.ApplianceService
+	readingService   service.ReadingService
+	tariffService    service.TariffService
+}
+
+func NewReadingHandler(applianceService service.ApplianceService, readingService service.ReadingService, tariffService service.TariffService) readingHandler {
+	return readingHandler{
+		applianceService: applianceService,
+		readingService:   readingService,
+		tariffService:    tariffService,
+	}
+}
+
+func (h *readingHandler) GetApplianceReadings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid appliance id",
+		})
+		return
+	}
+
+	from, to, err := queryTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	// Pastikan appliance milik user yang sedang login
+	if _, err := h.applianceService.GetApplianceByID(userID, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":     false,
+			"statusCode": 404,
+			"message":    "appliance not found",
+		})
+		return
+	}
+
+	readings, err := h.readingService.GetReadings(userID, uint(id), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	summary, err := h.readingService.GetUsageSummary(userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get appliance readings success",
+		"data": gin.H{
+			"readings": readings,
+			"summary":  summary,
+		},
+	})
+}
+
+// usageQuery membaca parameter from, to, bucket, dan tariff (atau golongan) untuk endpoint agregasi penggunaan
+func (h *readingHandler) usageQuery(c *gin.Context) (from, to time.Time, bucket string, tariff helper.TariffSchedule, ok bool) {
+	from, to, err := queryTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return from, to, "", nil, false
+	}
+
+	bucket = c.DefaultQuery("bucket", helper.BucketDay)
+	if _, _, err := helper.BucketBounds(time.Time{}, bucket); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return from, to, "", nil, false
+	}
+
+	tariff, ok = resolveTariff(c, h.tariffService, tariffCode(c.Query("tariff"), c.Query("golongan")))
+	if !ok {
+		return from, to, "", nil, false
+	}
+
+	return from, to, bucket, tariff, true
+}
+
+func (h *readingHandler) GetApplianceUsage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid appliance id",
+		})
+		return
+	}
+
+	from, to, bucket, tariff, ok := h.usageQuery(c)
+	if !ok {
+		return
+	}
+
+	// Pastikan appliance milik user yang sedang login
+	if _, err := h.applianceService.GetApplianceByID(userID, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":     false,
+			"statusCode": 404,
+			"message":    "appliance not found",
+		})
+		return
+	}
+
+	report, err := h.readingService.GetApplianceUsage(userID, uint(id), from, to, bucket, tariff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get appliance usage success",
+		"data":       report,
+	})
+}
+
+func (h *readingHandler) GetHouseholdUsage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	from, to, bucket, tariff, ok := h.usageQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.readingService.GetHouseholdUsage(userID, from, to, bucket, tariff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get household usage success",
+		"data":       report,
+	})
+}
+
+// GetMonthForecast memperkirakan kWh dan tagihan akhir bulan; as_of (opsional) menggeser hari perkiraan
+func (h *readingHandler) GetMonthForecast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	asOf := time.Now()
+	if raw := c.Query("as_of"); raw != "" {
+		parsed, err := helper.ParseTimestamp(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":     false,
+				"statusCode": 400,
+				"message":    "invalid as_of: " + err.Error(),
+			})
+			return
+		}
+		asOf = parsed
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(c.Query("tariff"), c.Query("golongan")))
+	if !ok {
+		return
+	}
+
+	forecast, err := h.readingService.ForecastMonth(userID, asOf, tariff)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, helper.ErrInsufficientHistory) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get month forecast success",
+		"data":       forecast,
+	})
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type referenceApplianceHandler struct {
+	referenceService service.ReferenceApplianceService
+	tariffService    service.TariffService
+}
+
+func NewReferenceApplianceHandler(referenceService service.ReferenceApplianceService, tariffService service.TariffService) referenceApplianceHandler {
+	return referenceApplianceHandler{referenceService: referenceService, tariffService: tariffService}
+}
+
+func referenceApplianceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReferenceApplianceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, helper.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetReferenceAppliances mengembalikan katalog produk acuan; query type menyaring jenis appliance
+func (h *referenceApplianceHandler) GetReferenceAppliances(c *gin.Context) {
+	references, err := h.referenceService.GetReferenceAppliances(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get reference appliances success",
+		"data":       references,
+	})
+}
+
+func (h *referenceApplianceHandler) CreateReferenceAppliance(c *gin.Context) {
+	var req entity.ReferenceApplianceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	reference, err := h.referenceService.CreateReferenceAppliance(req)
+	if err != nil {
+		statusCode := referenceApplianceErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":     true,
+		"statusCode": 201,
+		"message":    "Create reference appliance success",
+		"data":       reference,
+	})
+}
+
+func (h *referenceApplianceHandler) UpdateReferenceAppliance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid reference appliance id",
+		})
+		return
+	}
+
+	var req entity.ReferenceApplianceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	reference, err := h.referenceService.UpdateReferenceAppliance(uint(id), req)
+	if err != nil {
+		statusCode := referenceApplianceErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Update reference appliance success",
+		"data":       reference,
+	})
+}
+
+func (h *referenceApplianceHandler) DeleteReferenceAppliance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid reference appliance id",
+		})
+		return
+	}
+
+	if err := h.referenceService.DeleteReferenceAppliance(uint(id)); err != nil {
+		statusCode := referenceApplianceErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Delete reference appliance success",
+	})
+}
+
+// ImportReferenceAppliances membaca katalog dari file CSV multipart (field "file")
+func (h *referenceApplianceHandler) ImportReferenceAppliances(c *gin.Context) {
+	file, _, statusCode, err := readFormFile(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.referenceService.ImportCSV(helper.LimitSize(file, helper.MaxUploadSize))
+	if err != nil {
+		statusCode := referenceApplianceErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+			"data":       result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Import reference appliances success",
+		"data":       result,
+	})
+}
+
+// GetReplacements menyarankan produk pengganti yang lebih hemat beserta penghematan tahunan dan balik modal
+func (h *referenceApplianceHandler) GetReplacements(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(c.Query("tariff"), c.Query("golongan")))
+	if !ok {
+		return
+	}
+
+	replacements, err := h.referenceService.GetReplacements(userID, tariff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get replacement suggestions success",
+		"data":       replacements,
+	})
+}
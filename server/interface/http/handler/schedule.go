@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type scheduleHandler struct {
+	scheduleService service.ScheduleService
+	tariffService   service.TariffService
+}
+
+func NewScheduleHandler(scheduleService service.ScheduleService, tariffService service.TariffService) scheduleHandler {
+	return scheduleHandler{scheduleService: scheduleService, tariffService: tariffService}
+}
+
+// requestErrorStatus membalas 400 untuk isi request yang tidak valid dan 500 untuk kegagalan database atau Redis
+func requestErrorStatus(err error) int {
+	var validation service.ValidationError
+	if errors.As(err, &validation) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GenerateSchedule menyusun jadwal 24 jam per appliance berdasarkan harga tarif, daya tersambung, dan batasan jam
+func (h *scheduleHandler) GenerateSchedule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(req.TariffCode, req.Golongan))
+	if !ok {
+		return
+	}
+
+	schedule, err := h.scheduleService.GenerateSchedule(userID, req, tariff)
+	if err != nil {
+		statusCode := requestErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Generate schedule success",
+		"data":       schedule,
+	})
+}
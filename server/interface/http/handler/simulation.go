@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type simulationHandler struct {
+	simulationService service.SimulationService
+	tariffService     service.TariffService
+}
+
+func NewSimulationHandler(simulationService service.SimulationService, tariffService service.TariffService) simulationHandler {
+	return simulationHandler{simulationService: simulationService, tariffService: tariffService}
+}
+
+// CreateSimulation menghitung penghematan harian dan bulanan dari perubahan appliance yang diajukan
+func (h *simulationHandler) CreateSimulation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.SimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(req.TariffCode, req.Golongan))
+	if !ok {
+		return
+	}
+
+	result, err := h.simulationService.Simulate(userID, req, tariff)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Simulation success",
+		"data":       result,
+	})
+}
+
+// CreateBatterySimulation menghitung pemangkasan beban puncak, penghematan, dan lama cadangan baterai
+func (h *simulationHandler) CreateBatterySimulation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.BatteryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(req.TariffCode, req.Golongan))
+	if !ok {
+		return
+	}
+
+	result, err := h.simulationService.SimulateBattery(userID, req, tariff)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Battery simulation success",
+		"data":       result,
+	})
+}
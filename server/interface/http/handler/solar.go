@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type solarHandler struct {
+	solarService  service.SolarService
+	tariffService service.TariffService
+}
+
+func NewSolarHandler(solarService service.SolarService, tariffService service.TariffService) solarHandler {
+	return solarHandler{solarService: solarService, tariffService: tariffService}
+}
+
+// EstimateSolar memperkirakan produksi, konsumsi sendiri, penurunan tagihan, dan balik modal PLTS atap
+func (h *solarHandler) EstimateSolar(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.SolarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, ok := resolveTariff(c, h.tariffService, tariffCode(req.TariffCode, req.Golongan))
+	if !ok {
+		return
+	}
+
+	sizing, err := h.solarService.Estimate(userID, req, tariff)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Solar estimate success",
+		"data":       sizing,
+	})
+}
+
+func (h *solarHandler) GetIrradiance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.solarService.GetIrradiance(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get irradiance profile success",
+		"data":       profile,
+	})
+}
+
+// UploadIrradiance mengganti profil iradiasi bawaan dengan CSV multipart (field "file") berkolom hour dan irradiance
+func (h *solarHandler) UploadIrradiance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, _, statusCode, err := readFormFile(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.solarService.SaveIrradiance(userID, helper.LimitSize(file, helper.MaxUploadSize))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, helper.ErrFileTooLarge) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Upload irradiance profile success",
+		"data":       profile,
+	})
+}
+
+func (h *solarHandler) ResetIrradiance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.solarService.ResetIrradiance(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Reset irradiance profile success",
+	})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tariffHandler struct {
+	tariffService service.TariffService
+}
+
+func NewTariffHandler(tariffService service.TariffService) tariffHandler {
+	return tariffHandler{tariffService: tariffService}
+}
+
+func tariffErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTariffNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTariffExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// resolveTariff mengambil jadwal tarif dari kode (atau nama golongan lama) yang dikirim client.
+// Kode yang tidak dikenal langsung dijawab 400 dan ok bernilai false.
+func resolveTariff(c *gin.Context, tariffService service.TariffService, code string) (helper.TariffSchedule, bool) {
+	schedule, err := tariffService.GetSchedule(code)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		message := err.Error()
+		switch {
+		case errors.Is(err, service.ErrTariffNotFound) && code == "":
+			statusCode, message = http.StatusBadRequest, "tariff is required"
+		case errors.Is(err, service.ErrTariffNotFound):
+			statusCode, message = http.StatusBadRequest, "unknown tariff code "+strconv.Quote(code)
+		}
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    message,
+		})
+		return nil, false
+	}
+	return schedule, true
+}
+
+// tariffCode memilih kode tarif; golongan tetap diterima untuk client lama
+func tariffCode(code, golongan string) string {
+	if code != "" {
+		return code
+	}
+	return golongan
+}
+
+// GetTariffs mengembalikan tarif yang berlaku saat ini; all=true mengembalikan seluruh periode tarif
+func (h *tariffHandler) GetTariffs(c *gin.Context) {
+	at := time.Now()
+	if all, _ := strconv.ParseBool(c.Query("all")); all {
+		at = time.Time{}
+	}
+
+	tariffs, err := h.tariffService.GetTariffs(at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":     false,
+			"statusCode": 500,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Get tariffs success",
+		"data":       tariffs,
+	})
+}
+
+func (h *tariffHandler) CreateTariff(c *gin.Context) {
+	var req entity.TariffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, err := h.tariffService.CreateTariff(req)
+	if err != nil {
+		statusCode := tariffErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":     true,
+		"statusCode": 201,
+		"message":    "Create tariff success",
+		"data":       tariff,
+	})
+}
+
+func (h *tariffHandler) UpdateTariff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid tariff id",
+		})
+		return
+	}
+
+	var req entity.TariffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	tariff, err := h.tariffService.UpdateTariff(uint(id), req)
+	if err != nil {
+		statusCode := tariffErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Update tariff success",
+		"data":       tariff,
+	})
+}
+
+func (h *tariffHandler) DeleteTariff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    "invalid tariff id",
+		})
+		return
+	}
+
+	if err := h.tariffService.DeleteTariff(uint(id)); err != nil {
+		statusCode := tariffErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Delete tariff success",
+	})
+}
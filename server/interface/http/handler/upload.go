@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uploadOptions adalah opsi upload yang dikirim bersama file (form field) atau URL (JSON body)
+type uploadOptions struct {
+	URL     string `json:"url"`
+	Profile string `json:"profile"`
+	DryRun  bool   `json:"dry_run"`
+	Mode    string `json:"mode"`
+	Async   bool   `json:"async"`
+
+	// Filename dan ContentType berasal dari file multipart, dipakai untuk memilih importer
+	Filename    string `json:"-"`
+	ContentType string `json:"-"`
+}
+
+// readUploadRequest membaca opsi upload beserta file dari multipart form (field "file").
+// Untuk JSON {"url": ...} file bernilai nil dan belum diunduh.
+func readUploadRequest(c *gin.Context) (io.ReadCloser, uploadOptions, int, error) {
+	var opts uploadOptions
+	var file io.ReadCloser
+
+	if c.ContentType() == "multipart/form-data" {
+		formFile, header, statusCode, err := readFormFile(c)
+		if err != nil {
+			return nil, opts, statusCode, err
+		}
+		file, opts.Filename, opts.ContentType = formFile, header.Filename, header.Header.Get("Content-Type")
+	} else {
+		// Bind request body ke struct opts
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			return nil, opts, http.StatusBadRequest, err
+		}
+		if opts.URL == "" {
+			return nil, opts, http.StatusBadRequest, errors.New("url or multipart file is required")
+		}
+	}
+
+	if err := fillUploadOptions(c, &opts); err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, opts, http.StatusBadRequest, err
+	}
+	return file, opts, http.StatusOK, nil
+}
+
+// readFormFile membuka file multipart (field "file") dengan batas ukuran helper.MaxUploadSize
+func readFormFile(c *gin.Context) (multipart.File, *multipart.FileHeader, int, error) {
+	// Beri sedikit kelonggaran untuk overhead boundary multipart
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, helper.MaxUploadSize+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, nil, http.StatusRequestEntityTooLarge, helper.ErrFileTooLarge
+		}
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("file is required: %w", err)
+	}
+	if header.Size > helper.MaxUploadSize {
+		file.Close()
+		return nil, nil, http.StatusRequestEntityTooLarge, helper.ErrFileTooLarge
+	}
+	return file, header, http.StatusOK, nil
+}
+
+// fillUploadOptions melengkapi opsi yang tidak dikirim di body dari form field atau query string
+func fillUploadOptions(c *gin.Context, opts *uploadOptions) error {
+	if opts.Profile == "" {
+		opts.Profile = c.DefaultPostForm("profile", c.Query("profile"))
+	}
+	if opts.Mode == "" {
+		opts.Mode = c.DefaultPostForm("mode", c.Query("mode"))
+	}
+	for name, value := range map[string]*bool{"dry_run": &opts.DryRun, "async": &opts.Async} {
+		raw := c.DefaultPostForm(name, c.Query(name))
+		if *value || raw == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+		*value = parsed
+	}
+	return normalizeImportMode(opts)
+}
+
+// uploadName mengembalikan nama file multipart atau URL sumber untuk mendeteksi format dari ekstensinya
+func uploadName(opts uploadOptions) string {
+	if opts.URL != "" {
+		return opts.URL
+	}
+	return opts.Filename
+}
+
+// openUploadSource mengembalikan file upload, atau mengunduh file dari URL jika upload berupa JSON
+func (h *fileHandler) openUploadSource(c *gin.Context, file io.ReadCloser, opts uploadOptions) (io.ReadCloser, error) {
+	if file != nil {
+		return file, nil
+	}
+
+	// Unduh file melalui fetcher yang menolak alamat internal dan membatasi ukuran/waktu
+	return h.fetcher.Fetch(c.Request.Context(), opts.URL)
+}
+
+// normalizeImportMode mengisi mode default (replace) dan menolak mode yang tidak dikenal
+func normalizeImportMode(opts *uploadOptions) error {
+	switch opts.Mode {
+	case "":
+		opts.Mode = entity.ImportModeReplace
+	case entity.ImportModeReplace, entity.ImportModeMerge:
+	default:
+		return fmt.Errorf("mode must be %q or %q", entity.ImportModeReplace, entity.ImportModeMerge)
+	}
+	return nil
+}
+
+// parseUpload membuka sumber upload, memilih profil mapping, dan mem-parsing file.
+// Jika gagal, response error sudah dikirim dan ok bernilai false.
+func (h *fileHandler) parseUpload(c *gin.Context, userID uint, file io.ReadCloser, opts uploadOptions) (*helper.ParsedCSV, entity.MappingProfileResponse, bool) {
+	var profile entity.MappingProfileResponse
+
+	source, err := h.openUploadSource(c, file, opts)
+	if err != nil {
+		statusCode := uploadErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return nil, profile, false
+	}
+	defer source.Close()
+
+	csvFile, err := helper.ReadUpload(source, opts.ContentType, uploadName(opts))
+	if err != nil {
+		statusCode := uploadErrorStatus(err)
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return nil, profile, false
+	}
+
+	profile, err = h.mappingProfileService.ResolveProfile(userID, opts.Profile, csvFile.Header)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, service.ErrMappingProfileNotFound) {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return nil, profile, false
+	}
+
+	parsed, err := csvFile.Parse(profile.ColumnMapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":     false,
+			"statusCode": 400,
+			"message":    fmt.Sprintf("profile %q cannot be applied: %v", profile.Name, err),
+			"data":       gin.H{"header": csvFile.Header, "profile": profile},
+		})
+		return nil, profile, false
+	}
+
+	return parsed, profile, true
+}
+
+// uploadErrorStatus memetakan error upload/unduhan ke status 4xx yang sesuai
+func uploadErrorStatus(err error) int {
+	var statusErr *helper.FetchStatusError
+	switch {
+	case errors.Is(err, helper.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, helper.ErrUnsupportedContentType), errors.Is(err, helper.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, helper.ErrFetchTimeout):
+		return http.StatusRequestTimeout
+	case errors.Is(err, helper.ErrFetchFailed), errors.As(err, &statusErr):
+		return http.StatusUnprocessableEntity
+	default:
+		// termasuk ErrURLNotAllowed, ErrAddressNotAllowed, ErrTooManyRedirects, dan CSV tidak valid
+		return http.StatusBadRequest
+	}
+}
+
+// PreviewUpload menampilkan hasil pemetaan kolom sebuah file tanpa menyimpan apa pun
+func (h *fileHandler) PreviewUpload(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, opts, statusCode, err := readUploadRequest(c)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"status":     false,
+			"statusCode": statusCode,
+			"message":    err.Error(),
+		})
+		return
+	}
+
+	parsed, profile, ok := h.parseUpload(c, userID, file, opts)
+	if !ok {
+		return
+	}
+
+	const sampleSize = 20
+	readings := parsed.Readings
+	if len(readings) > sampleSize {
+		readings = readings[:sampleSize]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     true,
+		"statusCode": 200,
+		"message":    "Preview upload success",
+		"data": gin.H{
+			"header":          parsed.Header,
+			"profile":         profile,
+			"appliances":      parsed.Appliances,
+			"sample_readings": readings,
+			"total_readings":  len(parsed.Readings),
+		},
+	})
+}
+
+// importReport menyusun laporan validasi upload dengan membandingkan appliance hasil parsing dan milik user
+func (h *fileHandler) importReport(userID uint, parsed *helper.ParsedCSV, profile entity.MappingProfileResponse, dryRun bool) (entity.ImportReport, error) {
+	existing, err := h.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return entity.ImportReport{}, err
+	}
+
+	names := make([]string, 0, len(existing))
+	for _, appliance := range existing {
+		names = append(names, appliance.Name)
+	}
+	return helper.BuildImportReport(parsed, profile.Name, names, dryRun), nil
+}
+
+// submitImportJob membuat import job di background dan langsung mengembalikan 202 beserta ID job
+func (h *fileHandler) submitImportJob(c *gin.Context, userID uint, file io.ReadCloser, opts uploadOptions) {
+	task := entity.ImportTask{
+		URL:         opts.URL,
+		Filename:    opts.Filename,
+		ContentType: opts.ContentType,
+		Profile:     opts.Profile,
+		Mode:        opts.Mode,
+		DryRun:      opts.DryRun,
+	}
+	source := opts.URL
+	if file != nil {
+		defer file.Close()
+		content, err := io.ReadAll(helper.LimitSize(file, helper.MaxUploadSize))
+		if err != nil {
+			statusCode := uploadErrorStatus(err)
+			c.JSON(statusCode, gin.H{
+				"status":     false,
+				"statusCode": statusCode,
+				"message":    err.Error(),
+			})
+			return
+		}
+		task.Content, source = content, opts.Filename
+	}
+
+	job, err := h.importJobService.Submit(userID, source, task)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":     false,
+			"statusCode": 503,
+			"message":    "failed create import job: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"status":     true,
+		"statusCode": 202,
+		"message":    "Import job queued",
+		"data":       job,
+	})
+}
@@ -52,3 +52,20 @@ func AuthMiddleware() gin.HandlerFunc {
 		ctx.Next()
 	})
 }
+
+// AdminMiddleware hanya meneruskan request dari user admin; dipasang setelah AuthMiddleware
+func AdminMiddleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		userData, _ := ctx.Get("user_data")
+		if !helper.IsAdmin(userData) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"statusCode": 403,
+				"status":     false,
+				"error":      "Forbidden",
+			})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	})
+}
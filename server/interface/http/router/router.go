@@ -1,6 +1,10 @@
 package router
 
 import (
+	"context"
+	"os"
+	"strconv"
+
 	"smart-home-energy-management-server/interface/http/middleware"
 	"smart-home-energy-management-server/interface/http/routes"
 
@@ -22,7 +26,21 @@ func SetupRouter(psql *gorm.DB, redis *redis.Client) *gin.Engine {
 
 	v1 := router.Group("/v1")
 	routes.UserRoutes(v1, psql, redis)
-	routes.FileRoutes(v1, psql, redis)
+	importJobService := routes.FileRoutes(v1, psql, redis)
+	routes.ReadingRoutes(v1, psql, redis)
+	routes.TariffRoutes(v1, psql)
+	routes.PrepaidRoutes(v1, psql)
+	routes.ScheduleRoutes(v1, psql, redis)
+	routes.SimulationRoutes(v1, psql, redis)
+	routes.ReferenceApplianceRoutes(v1, psql, redis)
+	routes.SolarRoutes(v1, psql, redis)
+
+	// Worker import job berjalan selama server hidup
+	importWorkers, err := strconv.Atoi(os.Getenv("IMPORT_WORKERS"))
+	if err != nil || importWorkers <= 0 {
+		importWorkers = 2
+	}
+	importJobService.Start(context.Background(), importWorkers)
 
 	return router
 }
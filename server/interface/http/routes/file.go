@@ -2,7 +2,10 @@ package routes
 
 import (
 	"os"
+
 	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/helper"
 	"smart-home-energy-management-server/internal/repository"
 	"smart-home-energy-management-server/internal/service"
 
@@ -11,7 +14,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func FileRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+// FileRoutes mendaftarkan route upload dan appliance, lalu mengembalikan service import job agar worker-nya
+// dijalankan oleh router
+func FileRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) service.ImportJobService {
 
 	redisRepository := repository.NewRedisRepository(redis)
 
@@ -21,20 +26,54 @@ func FileRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
 	applianceRepository := repository.NewApplianceRepository(psql)
 	applianceService := service.NewApplianceService(applianceRepository, redisRepository)
 
-	fileHandler := handler.NewFileHandler(applianceService, fileService, recommendationService)
+	readingRepository := repository.NewReadingRepository(psql)
+	readingService := service.NewReadingService(readingRepository)
+
+	mappingProfileRepository := repository.NewMappingProfileRepository(psql)
+	mappingProfileService := service.NewMappingProfileService(mappingProfileRepository)
+
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+
+	importService := service.NewImportService(repository.NewTransactor(psql), redisRepository)
 
-	version.POST("/upload", fileHandler.UploadFileCSV)
-	version.GET("/table", fileHandler.GetTable)
+	// Import job diproses worker di background; antrian memory hanya untuk satu instance server
+	fetcher := helper.NewFetcher(helper.DefaultFetcherConfig())
+	importQueue := repository.NewRedisImportQueue(redis)
+	if os.Getenv("IMPORT_QUEUE") == "memory" {
+		importQueue = repository.NewMemoryImportQueue(100)
+	}
+	importJobService := service.NewImportJobService(repository.NewImportJobRepository(psql), importQueue, importService, mappingProfileService, applianceService, fetcher)
+
+	fileHandler := handler.NewFileHandler(applianceService, fileService, recommendationService, readingService, mappingProfileService, importService, importJobService, tariffService, fetcher)
+	importJobHandler := handler.NewImportJobHandler(importJobService)
+	mappingProfileHandler := handler.NewMappingProfileHandler(mappingProfileService)
+
+	// Seluruh data file dan appliance dimiliki per user
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("upload", fileHandler.UploadFileCSV)
+	protected.POST("upload/preview", fileHandler.PreviewUpload)
+	protected.GET("imports/:id", importJobHandler.GetImport)
+	protected.POST("imports/:id/cancel", importJobHandler.CancelImport)
+	protected.GET("mapping-profiles", mappingProfileHandler.GetProfiles)
+	protected.POST("mapping-profiles", mappingProfileHandler.CreateProfile)
+	protected.PUT("mapping-profiles/:id", mappingProfileHandler.UpdateProfile)
+	protected.DELETE("mapping-profiles/:id", mappingProfileHandler.DeleteProfile)
+	protected.GET("table", fileHandler.GetTable)
 	// Dev-only: raw view of redis value for debugging.
 	if os.Getenv("MODE") == "development" {
-		version.GET("/table/raw", fileHandler.GetRawTable)
+		protected.GET("table/raw", fileHandler.GetRawTable)
 	}
-	version.POST("/chat", fileHandler.Chat)
-	version.POST("/tapas-chat", fileHandler.TapasChat)
-	version.GET("/appliance", fileHandler.GetAppliance)
-	version.GET("/all-appliances", fileHandler.GetAllAppliance)
-	version.PUT("/set-daily-target", fileHandler.SetDailyTarget)
-	version.POST("/get-daily-target", fileHandler.GetDailyTarget)
-	version.POST("/generate-daily-recommendations", fileHandler.GenerateDailyRecommendations)
-	version.POST("/generate-monthly-recommendations", fileHandler.GenerateMonthlyRecommendations)
+	protected.POST("chat", fileHandler.Chat)
+	protected.POST("tapas-chat", fileHandler.TapasChat)
+	protected.GET("appliance", fileHandler.GetAppliance)
+	protected.GET("all-appliances", fileHandler.GetAllAppliance)
+	protected.PUT("set-daily-target", fileHandler.SetDailyTarget)
+	protected.POST("get-daily-target", fileHandler.GetDailyTarget)
+	protected.GET("daily-target", fileHandler.GetDailyTarget)
+	protected.POST("generate-daily-recommendations", fileHandler.GenerateDailyRecommendations)
+	protected.POST("generate-monthly-recommendations", fileHandler.GenerateMonthlyRecommendations)
+	protected.GET("recommendations", fileHandler.GetRecommendations)
+
+	return importJobService
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func PrepaidRoutes(version *gin.RouterGroup, psql *gorm.DB) {
+	prepaidService := service.NewPrepaidService(repository.NewTokenPurchaseRepository(psql), repository.NewReadingRepository(psql))
+	prepaidHandler := handler.NewPrepaidHandler(prepaidService)
+
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.GET("prepaid/purchases", prepaidHandler.GetPurchases)
+	protected.POST("prepaid/purchases", prepaidHandler.CreatePurchase)
+	protected.DELETE("prepaid/purchases/:id", prepaidHandler.DeletePurchase)
+	protected.GET("prepaid/forecast", prepaidHandler.GetForecast)
+}
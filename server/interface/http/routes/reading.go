@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func ReadingRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+	redisRepository := repository.NewRedisRepository(redis)
+
+	applianceRepository := repository.NewApplianceRepository(psql)
+	applianceService := service.NewApplianceService(applianceRepository, redisRepository)
+
+	readingRepository := repository.NewReadingRepository(psql)
+	readingService := service.NewReadingService(readingRepository)
+
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+
+	readingHandler := handler.NewReadingHandler(applianceService, readingService, tariffService)
+
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.GET("appliances/:id/readings", readingHandler.GetApplianceReadings)
+	protected.GET("appliances/:id/usage", readingHandler.GetApplianceUsage)
+	protected.GET("usage", readingHandler.GetHouseholdUsage)
+	protected.GET("forecast/month", readingHandler.GetMonthForecast)
+}
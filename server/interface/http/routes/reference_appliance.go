@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func ReferenceApplianceRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+	applianceService := service.NewApplianceService(repository.NewApplianceRepository(psql), repository.NewRedisRepository(redis))
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+	referenceService := service.NewReferenceApplianceService(repository.NewReferenceApplianceRepository(psql), applianceService, repository.NewReadingRepository(psql))
+	referenceHandler := handler.NewReferenceApplianceHandler(referenceService, tariffService)
+
+	// Katalog dibaca semua user; perubahan katalog hanya oleh admin
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.GET("reference-appliances", referenceHandler.GetReferenceAppliances)
+	protected.GET("replacements", referenceHandler.GetReplacements)
+
+	admin := version.Group("/")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	admin.POST("reference-appliances", referenceHandler.CreateReferenceAppliance)
+	admin.POST("reference-appliances/import", referenceHandler.ImportReferenceAppliances)
+	admin.PUT("reference-appliances/:id", referenceHandler.UpdateReferenceAppliance)
+	admin.DELETE("reference-appliances/:id", referenceHandler.DeleteReferenceAppliance)
+}
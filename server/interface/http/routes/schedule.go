@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func ScheduleRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+	applianceService := service.NewApplianceService(repository.NewApplianceRepository(psql), repository.NewRedisRepository(redis))
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+	scheduleHandler := handler.NewScheduleHandler(service.NewScheduleService(applianceService), tariffService)
+	powerLimitService := service.NewPowerLimitService(applianceService, repository.NewReadingRepository(psql), tariffService)
+	powerLimitHandler := handler.NewPowerLimitHandler(powerLimitService, tariffService)
+
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("schedule", scheduleHandler.GenerateSchedule)
+	protected.POST("power-limit", powerLimitHandler.AnalyzePowerLimit)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func SimulationRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+	applianceService := service.NewApplianceService(repository.NewApplianceRepository(psql), repository.NewRedisRepository(redis))
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+	simulationService := service.NewSimulationService(applianceService, repository.NewReadingRepository(psql))
+	simulationHandler := handler.NewSimulationHandler(simulationService, tariffService)
+
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("simulations", simulationHandler.CreateSimulation)
+	protected.POST("simulations/battery", simulationHandler.CreateBatterySimulation)
+}
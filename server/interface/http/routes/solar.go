@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func SolarRoutes(version *gin.RouterGroup, psql *gorm.DB, redis *redis.Client) {
+	redisRepository := repository.NewRedisRepository(redis)
+	applianceService := service.NewApplianceService(repository.NewApplianceRepository(psql), redisRepository)
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+	solarService := service.NewSolarService(applianceService, repository.NewReadingRepository(psql), redisRepository)
+	solarHandler := handler.NewSolarHandler(solarService, tariffService)
+
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("solar/estimate", solarHandler.EstimateSolar)
+	protected.GET("solar/irradiance", solarHandler.GetIrradiance)
+	protected.POST("solar/irradiance", solarHandler.UploadIrradiance)
+	protected.DELETE("solar/irradiance", solarHandler.ResetIrradiance)
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"log"
+
+	"smart-home-energy-management-server/interface/http/handler"
+	"smart-home-energy-management-server/interface/http/middleware"
+	"smart-home-energy-management-server/internal/repository"
+	"smart-home-energy-management-server/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TariffRoutes(version *gin.RouterGroup, psql *gorm.DB) {
+	tariffService := service.NewTariffService(repository.NewTariffRepository(psql))
+	if err := tariffService.Seed(); err != nil {
+		log.Printf("error: seed tariffs: %v", err)
+	}
+
+	tariffHandler := handler.NewTariffHandler(tariffService)
+
+	// Daftar tarif dibaca semua user untuk dropdown; perubahan katalog hanya oleh admin
+	protected := version.Group("/")
+	protected.Use(middleware.AuthMiddleware())
+	protected.GET("tariffs", tariffHandler.GetTariffs)
+
+	admin := version.Group("/")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	admin.POST("tariffs", tariffHandler.CreateTariff)
+	admin.PUT("tariffs/:id", tariffHandler.UpdateTariff)
+	admin.DELETE("tariffs/:id", tariffHandler.DeleteTariff)
+}
@@ -4,9 +4,11 @@ import (
 	"gorm.io/gorm"
 )
 
+// Appliance unik per (user, nama) di antara appliance yang belum dihapus; kunci ini dipakai import merge
 type Appliance struct {
 	gorm.Model
-	Name           string
+	UserID         uint   `gorm:"index;uniqueIndex:idx_appliances_user_name,where:deleted_at IS NULL"`
+	Name           string `gorm:"uniqueIndex:idx_appliances_user_name"`
 	Type           string
 	Location       string
 	Power          int
@@ -0,0 +1,51 @@
+package entity
+
+// Mode import: replace mengganti seluruh data user, merge menggabungkan dengan data yang sudah ada
+const (
+	ImportModeReplace = "replace"
+	ImportModeMerge   = "merge"
+)
+
+// Aksi yang akan dilakukan terhadap appliance saat import
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// ImportResult adalah jumlah perubahan yang benar-benar disimpan oleh import
+type ImportResult struct {
+	Mode                string `json:"mode"`
+	AppliancesCreated   int    `json:"appliances_created"`
+	AppliancesUpdated   int    `json:"appliances_updated"`
+	AppliancesUnchanged int    `json:"appliances_unchanged"`
+	ReadingsAdded       int    `json:"readings_added"`
+	ReadingsDuplicated  int    `json:"readings_duplicated"`
+}
+
+// RowError menjelaskan baris file yang ditolak beserta kolom penyebabnya
+type RowError struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ApplianceChange adalah appliance yang akan dibuat atau diperbarui oleh import
+type ApplianceChange struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// ImportReport merangkum hasil validasi file upload
+type ImportReport struct {
+	DryRun          bool              `json:"dry_run"`
+	RowsRead        int               `json:"rows_read"`
+	RowsAccepted    int               `json:"rows_accepted"`
+	RowsRejected    int               `json:"rows_rejected"`
+	ErrorsTruncated bool              `json:"errors_truncated"`
+	Errors          []RowError        `json:"errors"`
+	Header          []string          `json:"header"`
+	Profile         string            `json:"profile"`
+	Schema          ColumnMapping     `json:"schema"`
+	Appliances      []ApplianceChange `json:"appliances"`
+	Readings        int               `json:"readings"`
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status import job
+const (
+	ImportJobQueued     = "queued"
+	ImportJobRunning    = "running"
+	ImportJobCommitting = "committing" // data sedang disimpan; job tidak dapat dibatalkan lagi
+	ImportJobSucceeded  = "succeeded"
+	ImportJobFailed     = "failed"
+	ImportJobCanceled   = "canceled"
+)
+
+// ImportJob mencatat upload yang diproses di background beserta progres dan hasilnya
+type ImportJob struct {
+	gorm.Model
+	UserID       uint   `gorm:"index"`
+	Status       string `gorm:"index"`
+	Source       string // nama file atau URL
+	Profile      string
+	Mode         string
+	DryRun       bool
+	Progress     int // persen
+	RowsRead     int
+	RowsAccepted int
+	RowsRejected int
+	Result       ImportResult `gorm:"embedded;embeddedPrefix:result_"`
+	Report       string       // ImportReport dalam format JSON
+	Message      string
+	StartedAt    *time.Time
+	FinishedAt   *time.Time
+	HeartbeatAt  *time.Time // diperbarui berkala oleh worker selama job berjalan
+	Payload      []byte     `gorm:"type:bytea"` // ImportTask dalam format JSON; satu-satunya salinan isi job
+}
+
+// ImportTask adalah isi satu import job yang disimpan di Payload; Content berisi file upload jika tidak
+// memakai URL. Antrian hanya membawa ID job.
+type ImportTask struct {
+	URL         string `json:"url,omitempty"`
+	Content     []byte `json:"content,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Profile     string `json:"profile"`
+	Mode        string `json:"mode"`
+	DryRun      bool   `json:"dry_run"`
+}
+
+type ImportJobResponse struct {
+	ID           uint          `json:"id"`
+	Status       string        `json:"status"`
+	Source       string        `json:"source"`
+	Profile      string        `json:"profile"`
+	Mode         string        `json:"mode"`
+	DryRun       bool          `json:"dry_run"`
+	Progress     int           `json:"progress"`
+	RowsRead     int           `json:"rows_read"`
+	RowsAccepted int           `json:"rows_accepted"`
+	RowsRejected int           `json:"rows_rejected"`
+	Result       ImportResult  `json:"result"`
+	Report       *ImportReport `json:"report,omitempty"`
+	Message      string        `json:"message,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	StartedAt    *time.Time    `json:"started_at"`
+	FinishedAt   *time.Time    `json:"finished_at"`
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// ColumnMapping menyatakan nama kolom header untuk setiap field, satuan nilainya, dan format waktunya.
+// Kolom kosong berarti field tersebut tidak ada di file; satuan kosong ditebak dari nama kolom.
+type ColumnMapping struct {
+	ApplianceColumn    string `json:"appliance_column"`
+	TimestampColumn    string `json:"timestamp_column"`
+	DateColumn         string `json:"date_column"`
+	TimeColumn         string `json:"time_column"`
+	TimestampFormat    string `json:"timestamp_format"`
+	EnergyColumn       string `json:"energy_column"`
+	EnergyUnit         string `json:"energy_unit"`
+	PowerColumn        string `json:"power_column"`
+	PowerUnit          string `json:"power_unit"`
+	DurationColumn     string `json:"duration_column"`
+	DurationUnit       string `json:"duration_unit"`
+	CostColumn         string `json:"cost_column"`
+	TypeColumn         string `json:"type_column"`
+	LocationColumn     string `json:"location_column"`
+	StatusColumn       string `json:"status_column"`
+	ConnectivityColumn string `json:"connectivity_column"`
+	// Pemisah angka; kosong berarti ditebak dari nilainya (mis. "1,25" atau "1.234,5")
+	DecimalSeparator  string `json:"decimal_separator"`
+	ThousandSeparator string `json:"thousand_separator"`
+}
+
+// MappingProfile adalah pemetaan kolom bernama milik user (profil bawaan tidak disimpan di database)
+type MappingProfile struct {
+	gorm.Model
+	UserID        uint   `gorm:"index"`
+	Name          string `gorm:"type:varchar(100);not null"`
+	Description   string
+	ColumnMapping `gorm:"embedded"`
+}
+
+type MappingProfileRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ColumnMapping
+}
+
+type MappingProfileResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BuiltIn     bool   `json:"built_in"`
+	ColumnMapping
+}
@@ -0,0 +1,94 @@
+package entity
+
+// Sumber data beban pada analisis daya tersambung
+const (
+	PowerLimitObserved = "observed"
+	PowerLimitPlanned  = "planned"
+)
+
+// Rekomendasi untuk jam yang melewati daya tersambung
+const (
+	PowerLimitOK      = "ok"
+	PowerLimitStagger = "stagger"
+	PowerLimitUpgrade = "upgrade"
+)
+
+// PlannedUsage adalah rencana nyala satu appliance; Runs dapat diambil langsung dari hasil POST /schedule
+type PlannedUsage struct {
+	ApplianceID uint          `json:"appliance_id"`
+	Runs        []ScheduleRun `json:"runs"`
+}
+
+// PowerLimitRequest meminta analisis beban per jam terhadap daya tersambung. Planned kosong berarti
+// memakai reading pada Date (default hari reading terakhir). VALimit 0 berarti daya tersambung dari tarif,
+// PowerFactor dan ConnectionFeePerVA kosong berarti memakai nilai default.
+type PowerLimitRequest struct {
+	TariffCode         string         `json:"tariff_code"`
+	Golongan           string         `json:"golongan"` // dipakai jika tariff_code kosong
+	Date               string         `json:"date"`
+	VALimit            int            `json:"va_limit"`
+	PowerFactor        float64        `json:"power_factor"`
+	ConnectionFeePerVA *float64       `json:"connection_fee_per_va"`
+	Planned            []PlannedUsage `json:"planned"`
+}
+
+// HourLoad adalah beban serentak pada satu jam, dihitung dari daya appliance yang menyala di jam tersebut
+type HourLoad struct {
+	Hour         string  `json:"hour"`
+	LoadW        float64 `json:"load_w"`
+	LoadVA       float64 `json:"load_va"`
+	ApplianceIDs []uint  `json:"appliance_ids"`
+	Overloaded   bool    `json:"overloaded"`
+}
+
+// StaggerSuggestion menyarankan memindahkan appliance ke jam lain; MoveTo kosong berarti tidak ada jam
+// yang cukup longgar. ExtraCost adalah selisih biaya per hari akibat perpindahan jam (negatif berarti hemat).
+type StaggerSuggestion struct {
+	ApplianceID uint    `json:"appliance_id"`
+	Name        string  `json:"name"`
+	Power       int     `json:"power"`
+	MoveTo      string  `json:"move_to"`
+	ExtraCost   float64 `json:"extra_cost"`
+}
+
+// PowerOverload adalah jam dengan beban melewati daya tersambung yang berisiko membuat MCB trip
+type PowerOverload struct {
+	Hour     string              `json:"hour"`
+	LoadVA   float64             `json:"load_va"`
+	ExcessVA float64             `json:"excess_va"`
+	Stagger  []StaggerSuggestion `json:"stagger"`
+	Resolved bool                `json:"resolved"`
+	Message  string              `json:"message"`
+}
+
+// UpgradeOption membandingkan tagihan bulanan dengan daya tersambung yang lebih besar.
+// MonthlyDifference tidak termasuk biaya penyambungan; EffectiveMonthlyCost menyebar biaya itu selama setahun.
+type UpgradeOption struct {
+	Tariff               string  `json:"tariff"`
+	Name                 string  `json:"name"`
+	VALimit              int     `json:"va_limit"`
+	PricePerKWh          float64 `json:"price_per_kwh"`
+	MonthlyKWh           float64 `json:"monthly_kwh"`
+	CurrentMonthlyCost   float64 `json:"current_monthly_cost"`
+	MonthlyCost          float64 `json:"monthly_cost"`
+	MonthlyDifference    float64 `json:"monthly_difference"`
+	ConnectionFee        float64 `json:"connection_fee"`
+	EffectiveMonthlyCost float64 `json:"effective_monthly_cost"`
+}
+
+// PowerLimitAnalysis adalah hasil pemeriksaan beban serentak per jam terhadap daya tersambung
+type PowerLimitAnalysis struct {
+	Date               string          `json:"date"`
+	Source             string          `json:"source"`
+	Tariff             string          `json:"tariff"`
+	VALimit            int             `json:"va_limit"`
+	PowerFactor        float64         `json:"power_factor"`
+	PeakVA             float64         `json:"peak_va"`
+	PeakHour           string          `json:"peak_hour"`
+	Hours              []HourLoad      `json:"hours"`
+	Overloads          []PowerOverload `json:"overloads"`
+	StaggerMonthlyCost float64         `json:"stagger_monthly_cost"`
+	Upgrade            *UpgradeOption  `json:"upgrade"`
+	Recommendation     string          `json:"recommendation"`
+	Message            string          `json:"message"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TokenPurchase adalah pembelian token listrik prabayar (pulsa listrik) milik user
+type TokenPurchase struct {
+	gorm.Model
+	UserID      uint      `gorm:"index"`
+	PurchasedAt time.Time `gorm:"index"`
+	Amount      float64   // rupiah
+	KWh         float64   `gorm:"column:kwh"`
+	Token       string    `gorm:"type:varchar(30)"`
+}
+
+type TokenPurchaseRequest struct {
+	PurchasedAt time.Time `json:"purchased_at"`
+	Amount      float64   `json:"amount"`
+	KWh         float64   `json:"kwh"`
+	Token       string    `json:"token"`
+}
+
+type TokenPurchaseResponse struct {
+	ID          uint      `json:"id"`
+	PurchasedAt time.Time `json:"purchased_at"`
+	Amount      float64   `json:"amount"`
+	KWh         float64   `json:"kwh"`
+	Token       string    `json:"token"`
+}
+
+// PrepaidForecast adalah sisa saldo kWh dan perkiraan tanggal saldo habis berdasarkan rata-rata pemakaian terbaru
+type PrepaidForecast struct {
+	AsOf            time.Time  `json:"as_of"`
+	CreditedKWh     float64    `json:"credited_kwh"`
+	ConsumedKWh     float64    `json:"consumed_kwh"`
+	BalanceKWh      float64    `json:"balance_kwh"`
+	Depleted        bool       `json:"depleted"`
+	WindowDays      int        `json:"window_days"`
+	AverageDailyKWh float64    `json:"average_daily_kwh"`
+	DaysRemaining   *float64   `json:"days_remaining"`
+	DepletionDate   *time.Time `json:"depletion_date"`
+	// Alert hanya diisi jika threshold diberikan
+	AlertThresholdKWh *float64   `json:"alert_threshold_kwh,omitempty"`
+	Alert             bool       `json:"alert"`
+	AlertDate         *time.Time `json:"alert_date,omitempty"`
+}
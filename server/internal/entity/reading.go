@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reading adalah satu baris data konsumsi energi sebuah appliance pada waktu tertentu
+type Reading struct {
+	gorm.Model
+	UserID      uint      `gorm:"index"`
+	ApplianceID uint      `gorm:"index:idx_readings_appliance_timestamp"`
+	Timestamp   time.Time `gorm:"index:idx_readings_appliance_timestamp"`
+	Energy      float64   // kWh
+	Power       int       // Watt
+	Duration    float64   // jam
+}
+
+type ReadingRequest struct {
+	ApplianceName string    `json:"appliance_name"`
+	Timestamp     time.Time `json:"timestamp"`
+	Energy        float64   `json:"energy"`
+	Power         int       `json:"power"`
+	Duration      float64   `json:"duration"`
+}
+
+type ReadingResponse struct {
+	ID          uint      `json:"id"`
+	ApplianceID uint      `json:"appliance_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Energy      float64   `json:"energy"`
+	Power       int       `json:"power"`
+	Duration    float64   `json:"duration"`
+}
+
+// UsageSummary merangkum penggunaan appliance dari riwayat reading
+type UsageSummary struct {
+	LastReadingAt time.Time `json:"last_reading_at"`
+	DailyEnergy   float64   `json:"daily_energy"`
+	DailyHours    float64   `json:"daily_hours"`
+	WeeklyEnergy  float64   `json:"weekly_energy"`
+	WeeklyHours   float64   `json:"weekly_hours"`
+	MonthlyEnergy float64   `json:"monthly_energy"`
+	MonthlyHours  float64   `json:"monthly_hours"`
+	AverageHours  float64   `json:"average_hours"`
+	DaysRecorded  int       `json:"days_recorded"`
+}
+
+// UsageBucket adalah total konsumsi dalam satu interval waktu (jam, hari, atau bulan)
+type UsageBucket struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Energy float64   `json:"energy"`
+	Hours  float64   `json:"hours"`
+	Cost   float64   `json:"cost"`
+	// UnpricedEnergy adalah bagian Energy tanpa tarif yang berlaku, sehingga tidak termasuk dalam Cost
+	UnpricedEnergy float64 `json:"unpriced_energy"`
+}
+
+// UsageReport adalah konsumsi per bucket. UnpricedReadings > 0 berarti sebagian reading jatuh di luar masa
+// berlaku seluruh versi tarif dan TotalCost lebih rendah dari tagihan sebenarnya.
+type UsageReport struct {
+	Bucket           string        `json:"bucket"`
+	TariffCode       string        `json:"tariff_code"`
+	Tarif            float64       `json:"tarif"`
+	TotalEnergy      float64       `json:"total_energy"`
+	TotalCost        float64       `json:"total_cost"`
+	UnpricedEnergy   float64       `json:"unpriced_energy"`
+	UnpricedReadings int           `json:"unpriced_readings"`
+	Buckets          []UsageBucket `json:"buckets"`
+}
+
+// ForecastDay adalah konsumsi satu hari di bulan perkiraan; Forecast bernilai true untuk hari yang belum lewat
+type ForecastDay struct {
+	Date     string  `json:"date"`
+	Energy   float64 `json:"energy"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+	Forecast bool    `json:"forecast"`
+}
+
+// MonthForecast memperkirakan total kWh dan tagihan akhir bulan dari riwayat reading. Energy dan Bill adalah
+// nilai harapan; Lower dan Upper adalah batas interval kepercayaan ConfidenceLevel.
+type MonthForecast struct {
+	Month           string  `json:"month"`
+	AsOf            string  `json:"as_of"`
+	TariffCode      string  `json:"tariff_code"`
+	HistoryDays     int     `json:"history_days"`
+	DaysElapsed     int     `json:"days_elapsed"`
+	DaysRemaining   int     `json:"days_remaining"`
+	ActualEnergy    float64 `json:"actual_energy"`
+	ActualCost      float64 `json:"actual_cost"`
+	Energy          float64 `json:"energy"`
+	EnergyLower     float64 `json:"energy_lower"`
+	EnergyUpper     float64 `json:"energy_upper"`
+	Bill            float64 `json:"bill"`
+	BillLower       float64 `json:"bill_lower"`
+	BillUpper       float64 `json:"bill_upper"`
+	ConfidenceLevel float64 `json:"confidence_level"`
+	// TrendPerDay adalah perubahan konsumsi harian (kWh) per hari; WeekdayEffects adalah selisih kWh tiap
+	// hari dalam minggu (Minggu lebih dulu) terhadap rata-rata
+	TrendPerDay    float64       `json:"trend_per_day"`
+	WeekdayEffects [7]float64    `json:"weekday_effects"`
+	Days           []ForecastDay `json:"days"`
+}
@@ -0,0 +1,77 @@
+package entity
+
+// Alasan jam rekomendasi sebuah appliance berbeda dari pemakaian biasanya
+const (
+	AllocationUnchanged       = "unchanged"
+	AllocationReducedBudget   = "reduced_budget"
+	AllocationPriorityMinimum = "priority_minimum"
+	AllocationBelowMinimum    = "budget_below_minimum"
+	AllocationUnknownPower    = "unknown_power"
+)
+
+// BudgetAllocation adalah jam pemakaian harian yang direkomendasikan untuk satu appliance
+type BudgetAllocation struct {
+	ApplianceID      uint          `json:"appliance_id"`
+	Name             string        `json:"name"`
+	Type             string        `json:"type"`
+	Priority         bool          `json:"priority"`
+	KWhPerHour       float64       `json:"kwh_per_hour"`
+	DesiredHours     float64       `json:"desired_hours"`
+	MinHours         float64       `json:"min_hours"`
+	MaxHours         float64       `json:"max_hours"`
+	RecommendedHours float64       `json:"recommended_hours"`
+	MonthlyKWh       float64       `json:"monthly_kwh"`
+	MonthlyCost      float64       `json:"monthly_cost"`
+	Utility          float64       `json:"utility"`
+	Schedule         []ScheduleRun `json:"schedule"`
+	Reason           string        `json:"reason"`
+	Message          string        `json:"message"`
+}
+
+// BudgetPlan adalah hasil optimasi anggaran energi bulanan
+type BudgetPlan struct {
+	DaysInMonth   int                `json:"days_in_month"`
+	Tarif         float64            `json:"tarif"`
+	BudgetKWh     float64            `json:"budget_kwh"`
+	BudgetCost    float64            `json:"budget_cost"`
+	DesiredKWh    float64            `json:"desired_kwh"`
+	AllocatedKWh  float64            `json:"allocated_kwh"`
+	AllocatedCost float64            `json:"allocated_cost"`
+	Utility       float64            `json:"utility"`
+	Allocations   []BudgetAllocation `json:"allocations"`
+}
+
+// Jenis rekomendasi
+const (
+	RecommendationKindBudget      = "monthly_budget"
+	RecommendationKindDailyTarget = "daily_target"
+	RecommendationKindDailyUsage  = "daily_usage"
+)
+
+// Tingkat kepentingan rekomendasi
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Kelompok rekomendasi yang disimpan; setiap kelompok menimpa hasil generate sebelumnya
+const (
+	RecommendationScopeMonthly = "monthly"
+	RecommendationScopeDaily   = "daily"
+)
+
+// Recommendation adalah satu rekomendasi terstruktur. Teks untuk pengguna tidak disimpan, tetapi dibentuk
+// dari MessageKey dan Params sesuai bahasa saat ditampilkan (Message).
+type Recommendation struct {
+	Kind          string                 `json:"kind"`
+	ApplianceID   uint                   `json:"appliance_id"`
+	ApplianceName string                 `json:"appliance_name"`
+	Severity      string                 `json:"severity"`
+	SavingsKWh    float64                `json:"savings_kwh"`
+	SavingsIDR    float64                `json:"savings_idr"`
+	Schedule      []ScheduleRun          `json:"schedule,omitempty"`
+	MessageKey    string                 `json:"message_key"`
+	Params        map[string]interface{} `json:"params"`
+	Message       string                 `json:"message,omitempty"`
+}
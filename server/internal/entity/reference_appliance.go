@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// ReferenceAppliance adalah produk acuan di katalog efisiensi yang sama untuk seluruh user.
+// StarRating adalah jumlah bintang label tanda hemat energi SNI (1–5), Price adalah harga pasaran dalam rupiah.
+type ReferenceAppliance struct {
+	gorm.Model
+	Type       string  `gorm:"type:varchar(100);index;not null"`
+	Brand      string  `gorm:"type:varchar(100)"`
+	ModelName  string  `gorm:"column:model;type:varchar(100)"`
+	Power      int     `gorm:"not null"`
+	StarRating int     `gorm:"column:star_rating"`
+	Price      float64 `gorm:"not null"`
+}
+
+type ReferenceApplianceRequest struct {
+	Type       string  `json:"type"`
+	Brand      string  `json:"brand"`
+	Model      string  `json:"model"`
+	Power      int     `json:"power"`
+	StarRating int     `json:"star_rating"`
+	Price      float64 `json:"price"`
+}
+
+type ReferenceApplianceResponse struct {
+	ID         uint    `json:"id"`
+	Type       string  `json:"type"`
+	Brand      string  `json:"brand"`
+	Model      string  `json:"model"`
+	Power      int     `json:"power"`
+	StarRating int     `json:"star_rating"`
+	Price      float64 `json:"price"`
+}
+
+// CatalogueImportResult merangkum import katalog dari CSV; baris dengan type, brand, dan model yang sama diperbarui
+type CatalogueImportResult struct {
+	RowsRead int        `json:"rows_read"`
+	Created  int        `json:"created"`
+	Updated  int        `json:"updated"`
+	Errors   []RowError `json:"errors"`
+}
+
+// Sumber jam pemakaian harian untuk perhitungan penghematan
+const (
+	UsageSourceReadings     = "readings"
+	UsageSourceAverageUsage = "average_usage"
+)
+
+// ReplacementOption adalah produk pengganti beserta penghematan tahunan dan lama balik modal.
+// PaybackYears kosong berarti penghematan tidak cukup untuk menutup harga produk.
+type ReplacementOption struct {
+	ReferenceID      uint     `json:"reference_id"`
+	Brand            string   `json:"brand"`
+	Model            string   `json:"model"`
+	Power            int      `json:"power"`
+	StarRating       int      `json:"star_rating"`
+	Price            float64  `json:"price"`
+	AnnualKWh        float64  `json:"annual_kwh"`
+	AnnualKWhSavings float64  `json:"annual_kwh_savings"`
+	AnnualSavings    float64  `json:"annual_savings"`
+	PaybackYears     *float64 `json:"payback_years"`
+}
+
+// ApplianceReplacement adalah saran penggantian untuk satu appliance user, terurut dari balik modal tercepat
+type ApplianceReplacement struct {
+	ApplianceID uint                `json:"appliance_id"`
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Power       int                 `json:"power"`
+	HoursPerDay float64             `json:"hours_per_day"`
+	UsageSource string              `json:"usage_source"`
+	AnnualKWh   float64             `json:"annual_kwh"`
+	AnnualCost  float64             `json:"annual_cost"`
+	Options     []ReplacementOption `json:"options"`
+}
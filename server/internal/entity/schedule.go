@@ -0,0 +1,78 @@
+package entity
+
+// ScheduleWindow adalah rentang jam [StartHour, EndHour) yang diizinkan; StartHour lebih besar dari EndHour
+// berarti rentang melewati tengah malam
+type ScheduleWindow struct {
+	StartHour int `json:"start_hour"`
+	EndHour   int `json:"end_hour"`
+}
+
+// ScheduleApplianceRequest adalah batasan jadwal untuk satu appliance. Hours kosong berarti memakai
+// AverageUsage appliance, AllowedHours kosong berarti appliance boleh menyala kapan saja.
+type ScheduleApplianceRequest struct {
+	ApplianceID   uint             `json:"appliance_id"`
+	Hours         *float64         `json:"hours"`
+	Continuous    bool             `json:"continuous"`
+	MinRunMinutes int              `json:"min_run_minutes"`
+	AllowedHours  []ScheduleWindow `json:"allowed_hours"`
+}
+
+// ScheduleRequest meminta jadwal 24 jam untuk seluruh appliance user pada tanggal Date (YYYY-MM-DD, WIB).
+// VALimit 0 berarti memakai daya tersambung dari tarif; PowerFactor 0 berarti memakai nilai default.
+type ScheduleRequest struct {
+	TariffCode        string                     `json:"tariff_code"`
+	Golongan          string                     `json:"golongan"` // dipakai jika tariff_code kosong
+	Date              string                     `json:"date"`
+	ResolutionMinutes int                        `json:"resolution_minutes"`
+	VALimit           int                        `json:"va_limit"`
+	PowerFactor       float64                    `json:"power_factor"`
+	Appliances        []ScheduleApplianceRequest `json:"appliances"`
+}
+
+// ScheduleRun adalah satu rentang waktu menyala tanpa jeda, End bernilai "24:00" untuk akhir hari
+type ScheduleRun struct {
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Hours float64 `json:"hours"`
+	Cost  float64 `json:"cost"`
+}
+
+// ApplianceSchedule adalah jadwal satu appliance; ScheduledHours lebih kecil dari RequestedHours jika
+// batasan jam, durasi, atau daya tersambung tidak memungkinkan
+type ApplianceSchedule struct {
+	ApplianceID    uint          `json:"appliance_id"`
+	Name           string        `json:"name"`
+	Power          int           `json:"power"`
+	Priority       bool          `json:"priority"`
+	Continuous     bool          `json:"continuous"`
+	RequestedHours float64       `json:"requested_hours"`
+	ScheduledHours float64       `json:"scheduled_hours"`
+	EnergyKWh      float64       `json:"energy_kwh"`
+	Cost           float64       `json:"cost"`
+	Runs           []ScheduleRun `json:"runs"`
+	Message        string        `json:"message"`
+}
+
+// ScheduleSlot adalah beban total dan harga pada satu slot waktu
+type ScheduleSlot struct {
+	Start        string  `json:"start"`
+	PricePerKWh  float64 `json:"price_per_kwh"`
+	LoadW        float64 `json:"load_w"`
+	ApplianceIDs []uint  `json:"appliance_ids"`
+}
+
+// DaySchedule adalah jadwal pemakaian appliance selama satu hari per slot ResolutionMinutes.
+// CapacityW adalah VALimit × PowerFactor; 0 berarti tanpa batas daya.
+type DaySchedule struct {
+	Date              string              `json:"date"`
+	Tariff            string              `json:"tariff"`
+	ResolutionMinutes int                 `json:"resolution_minutes"`
+	VALimit           int                 `json:"va_limit"`
+	PowerFactor       float64             `json:"power_factor"`
+	CapacityW         float64             `json:"capacity_w"`
+	PeakLoadW         float64             `json:"peak_load_w"`
+	TotalKWh          float64             `json:"total_kwh"`
+	TotalCost         float64             `json:"total_cost"`
+	Appliances        []ApplianceSchedule `json:"appliances"`
+	Slots             []ScheduleSlot      `json:"slots"`
+}
@@ -0,0 +1,115 @@
+package entity
+
+// Jenis perubahan appliance pada simulasi
+const (
+	SimulationUnchanged = "unchanged"
+	SimulationModify    = "modify"
+	SimulationRemove    = "remove"
+	SimulationAdd       = "add"
+)
+
+// SimulationChange adalah satu perubahan hipotetis. ApplianceID wajib untuk modify dan remove;
+// Name, Power, dan HoursPerDay wajib untuk add. Field kosong pada modify berarti tidak berubah.
+type SimulationChange struct {
+	Action      string   `json:"action"`
+	ApplianceID uint     `json:"appliance_id"`
+	Name        string   `json:"name"`
+	Power       *int     `json:"power"`
+	HoursPerDay *float64 `json:"hours_per_day"`
+}
+
+// SimulationRequest meminta proyeksi biaya bulan Tanggal (default bulan ini) setelah perubahan diterapkan
+type SimulationRequest struct {
+	TariffCode string             `json:"tariff_code"`
+	Golongan   string             `json:"golongan"` // dipakai jika tariff_code kosong
+	Tanggal    string             `json:"tanggal"`
+	Changes    []SimulationChange `json:"changes"`
+}
+
+// SimulationTotals adalah konsumsi dan biaya rumah pada satu skenario
+type SimulationTotals struct {
+	DailyKWh    float64 `json:"daily_kwh"`
+	MonthlyKWh  float64 `json:"monthly_kwh"`
+	DailyCost   float64 `json:"daily_cost"`
+	MonthlyCost float64 `json:"monthly_cost"`
+}
+
+// SimulatedAppliance membandingkan satu appliance sebelum dan sesudah perubahan
+type SimulatedAppliance struct {
+	ApplianceID         uint    `json:"appliance_id"`
+	Name                string  `json:"name"`
+	Action              string  `json:"action"`
+	BaselinePower       int     `json:"baseline_power"`
+	ScenarioPower       int     `json:"scenario_power"`
+	BaselineHours       float64 `json:"baseline_hours"`
+	ScenarioHours       float64 `json:"scenario_hours"`
+	BaselineMonthlyKWh  float64 `json:"baseline_monthly_kwh"`
+	ScenarioMonthlyKWh  float64 `json:"scenario_monthly_kwh"`
+	BaselineMonthlyCost float64 `json:"baseline_monthly_cost"`
+	ScenarioMonthlyCost float64 `json:"scenario_monthly_cost"`
+}
+
+// SimulationResult adalah proyeksi skenario dibanding kondisi saat ini; Savings bernilai positif jika lebih hemat
+type SimulationResult struct {
+	Month       string               `json:"month"`
+	DaysInMonth int                  `json:"days_in_month"`
+	Tariff      string               `json:"tariff"`
+	Baseline    SimulationTotals     `json:"baseline"`
+	Scenario    SimulationTotals     `json:"scenario"`
+	Savings     SimulationTotals     `json:"savings"`
+	Appliances  []SimulatedAppliance `json:"appliances"`
+}
+
+// BatteryRequest meminta simulasi baterai rumah/UPS pada hari Tanggal (default hari ini).
+// MaxDischargeKW kosong berarti sama dengan MaxChargeKW; PeakLimitKW kosong berarti daya tersambung tarif.
+type BatteryRequest struct {
+	TariffCode          string   `json:"tariff_code"`
+	Golongan            string   `json:"golongan"` // dipakai jika tariff_code kosong
+	Tanggal             string   `json:"tanggal"`
+	CapacityKWh         float64  `json:"capacity_kwh"`
+	MaxChargeKW         float64  `json:"max_charge_kw"`
+	MaxDischargeKW      float64  `json:"max_discharge_kw"`
+	RoundTripEfficiency float64  `json:"round_trip_efficiency"`
+	ReserveSoC          *float64 `json:"reserve_soc"` // porsi kapasitas yang disisakan untuk cadangan saat padam (0–1)
+	PeakLimitKW         float64  `json:"peak_limit_kw"`
+}
+
+// BatteryHour adalah keputusan baterai pada satu jam; GridKWh adalah energi yang ditarik dari PLN
+type BatteryHour struct {
+	Hour         int     `json:"hour"`
+	PricePerKWh  float64 `json:"price_per_kwh"`
+	LoadKWh      float64 `json:"load_kwh"`
+	ChargeKWh    float64 `json:"charge_kwh"`
+	DischargeKWh float64 `json:"discharge_kwh"`
+	GridKWh      float64 `json:"grid_kwh"`
+	SoCKWh       float64 `json:"soc_kwh"` // isi baterai di akhir jam
+}
+
+// BatteryResult membandingkan beban dan biaya harian tanpa dan dengan baterai. BackupHours adalah lama
+// appliance prioritas dapat menyala saat padam dari baterai penuh dan dari cadangan minimum; kosong jika
+// tidak ada appliance prioritas atau dayanya melebihi MaxDischargeKW.
+type BatteryResult struct {
+	Date                string        `json:"date"`
+	Tariff              string        `json:"tariff"`
+	ConsumptionSource   string        `json:"consumption_source"`
+	CapacityKWh         float64       `json:"capacity_kwh"`
+	MaxChargeKW         float64       `json:"max_charge_kw"`
+	MaxDischargeKW      float64       `json:"max_discharge_kw"`
+	RoundTripEfficiency float64       `json:"round_trip_efficiency"`
+	ReserveSoC          float64       `json:"reserve_soc"`
+	PeakLimitKW         float64       `json:"peak_limit_kw"`
+	PeakBeforeKW        float64       `json:"peak_before_kw"`
+	PeakAfterKW         float64       `json:"peak_after_kw"`
+	PeakShavingKW       float64       `json:"peak_shaving_kw"`
+	DailyKWhBefore      float64       `json:"daily_kwh_before"`
+	DailyKWhAfter       float64       `json:"daily_kwh_after"`
+	DailyCostBefore     float64       `json:"daily_cost_before"`
+	DailyCostAfter      float64       `json:"daily_cost_after"`
+	DailySavings        float64       `json:"daily_savings"`
+	MonthlySavings      float64       `json:"monthly_savings"`
+	DischargedKWh       float64       `json:"discharged_kwh"`
+	PriorityLoadKW      float64       `json:"priority_load_kw"`
+	BackupHoursFull     *float64      `json:"backup_hours_full"`
+	BackupHoursReserve  *float64      `json:"backup_hours_reserve"`
+	Hours               []BatteryHour `json:"hours"`
+}
@@ -0,0 +1,85 @@
+package entity
+
+// Sumber profil iradiasi dan profil konsumsi pada estimasi PLTS atap
+const (
+	IrradianceDefault  = "default"
+	IrradianceUploaded = "uploaded"
+
+	ConsumptionReadings     = "readings"
+	ConsumptionAverageUsage = "average_usage"
+)
+
+// IrradianceProfile adalah rata-rata iradiasi global horizontal (W/m²) untuk setiap jam 00–23 waktu Jakarta
+type IrradianceProfile struct {
+	Source string      `json:"source"`
+	Hours  [24]float64 `json:"hours"`
+	// DailyKWhPerM2 adalah jumlah iradiasi harian, setara peak sun hours
+	DailyKWhPerM2 float64 `json:"daily_kwh_per_m2"`
+}
+
+// SolarRequest meminta estimasi PLTS atap pada bulan Tanggal (default bulan ini).
+// KWp kosong berarti hanya rekomendasi ukuran; VALimit kosong berarti daya tersambung dari tarif.
+type SolarRequest struct {
+	TariffCode       string   `json:"tariff_code"`
+	Golongan         string   `json:"golongan"` // dipakai jika tariff_code kosong
+	Tanggal          string   `json:"tanggal"`
+	KWp              float64  `json:"kwp"`
+	VALimit          int      `json:"va_limit"`
+	CostPerKWp       *float64 `json:"cost_per_kwp"`
+	PerformanceRatio float64  `json:"performance_ratio"`
+	// ExportCredit adalah porsi kWh ekspor yang mengurangi kWh tagihan (0–1)
+	ExportCredit *float64 `json:"export_credit"`
+}
+
+// SolarHour adalah rata-rata harian satu jam: konsumsi rumah, produksi PLTS, dan pembagiannya
+type SolarHour struct {
+	Hour            int     `json:"hour"`
+	Irradiance      float64 `json:"irradiance"`
+	ConsumptionKWh  float64 `json:"consumption_kwh"`
+	GenerationKWh   float64 `json:"generation_kwh"`
+	SelfConsumedKWh float64 `json:"self_consumed_kwh"`
+	ExportedKWh     float64 `json:"exported_kwh"`
+}
+
+// SolarEstimate adalah proyeksi satu bulan untuk satu ukuran sistem.
+// PaybackYears kosong berarti sistem tidak menghemat tagihan.
+type SolarEstimate struct {
+	KWp                   float64  `json:"kwp"`
+	SystemCost            float64  `json:"system_cost"`
+	MonthlyGenerationKWh  float64  `json:"monthly_generation_kwh"`
+	MonthlyConsumptionKWh float64  `json:"monthly_consumption_kwh"`
+	SelfConsumedKWh       float64  `json:"self_consumed_kwh"`
+	ExportedKWh           float64  `json:"exported_kwh"`
+	ImportedKWh           float64  `json:"imported_kwh"`
+	BilledKWh             float64  `json:"billed_kwh"`
+	SelfConsumptionRatio  float64  `json:"self_consumption_ratio"`
+	SelfSufficiency       float64  `json:"self_sufficiency"`
+	BillBefore            float64  `json:"bill_before"`
+	BillAfter             float64  `json:"bill_after"`
+	BillReduction         float64  `json:"bill_reduction"`
+	AnnualSavings         float64  `json:"annual_savings"`
+	PaybackYears          *float64 `json:"payback_years"`
+	LifetimeNetBenefit    float64  `json:"lifetime_net_benefit"`
+}
+
+// SolarSizing adalah hasil estimasi PLTS atap. Estimate berisi ukuran yang diminta (jika ada) dan
+// Recommended ukuran dengan manfaat bersih terbesar selama umur sistem; Recommended kosong jika
+// tidak ada ukuran yang balik modal.
+type SolarSizing struct {
+	Month             string          `json:"month"`
+	DaysInMonth       int             `json:"days_in_month"`
+	Tariff            string          `json:"tariff"`
+	VALimit           int             `json:"va_limit"`
+	MaxKWp            float64         `json:"max_kwp"`
+	IrradianceSource  string          `json:"irradiance_source"`
+	ConsumptionSource string          `json:"consumption_source"`
+	CostPerKWp        float64         `json:"cost_per_kwp"`
+	PerformanceRatio  float64         `json:"performance_ratio"`
+	ExportCredit      float64         `json:"export_credit"`
+	LifetimeYears     int             `json:"lifetime_years"`
+	Estimate          *SolarEstimate  `json:"estimate"`
+	Recommended       *SolarEstimate  `json:"recommended"`
+	Candidates        []SolarEstimate `json:"candidates"`
+	// Hours adalah profil harian untuk ukuran yang diminta, atau ukuran rekomendasi jika tidak ada
+	Hours []SolarHour `json:"hours"`
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Model tarif
+const (
+	TariffModelFlat  = "flat"
+	TariffModelTOU   = "tou"
+	TariffModelBlock = "block"
+)
+
+// TariffWindow adalah rentang jam [StartHour, EndHour) dengan harga khusus, mis. WBP 17:00–22:00.
+// StartHour lebih besar dari EndHour berarti rentang melewati tengah malam.
+type TariffWindow struct {
+	Name        string  `json:"name"`
+	StartHour   int     `json:"start_hour"`
+	EndHour     int     `json:"end_hour"`
+	PricePerKWh float64 `json:"price_per_kwh"`
+}
+
+// TariffBlock adalah harga untuk konsumsi bulanan hingga UpToKWh; UpToKWh 0 berarti tanpa batas (blok terakhir)
+type TariffBlock struct {
+	UpToKWh     float64 `json:"up_to_kwh"`
+	PricePerKWh float64 `json:"price_per_kwh"`
+}
+
+// Tariff adalah golongan tarif listrik beserta harga per kWh yang berlaku pada rentang tanggal tertentu.
+// MaxVA bernilai 0 berarti tanpa batas atas daya; EffectiveTo kosong berarti masih berlaku.
+// PricePerKWh adalah harga flat, harga di luar window untuk model tou, atau harga acuan untuk model block.
+type Tariff struct {
+	gorm.Model
+	Code          string         `gorm:"type:varchar(50);index;not null"`
+	Name          string         `gorm:"type:varchar(100);not null"`
+	MinVA         int            `gorm:"column:min_va"`
+	MaxVA         int            `gorm:"column:max_va"`
+	PricePerKWh   float64        `gorm:"column:price_per_kwh;not null"`
+	Currency      string         `gorm:"type:varchar(3);not null"`
+	PricingModel  string         `gorm:"column:model;type:varchar(10);default:flat"`
+	Windows       []TariffWindow `gorm:"serializer:json"`
+	Blocks        []TariffBlock  `gorm:"serializer:json"`
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+}
+
+type TariffRequest struct {
+	Code          string         `json:"code"`
+	Name          string         `json:"name"`
+	MinVA         int            `json:"min_va"`
+	MaxVA         int            `json:"max_va"`
+	PricePerKWh   float64        `json:"price_per_kwh"`
+	Currency      string         `json:"currency"`
+	Model         string         `json:"model"`
+	Windows       []TariffWindow `json:"windows,omitempty"`
+	Blocks        []TariffBlock  `json:"blocks,omitempty"`
+	EffectiveFrom time.Time      `json:"effective_from"`
+	EffectiveTo   *time.Time     `json:"effective_to"`
+}
+
+type TariffResponse struct {
+	ID            uint           `json:"id"`
+	Code          string         `json:"code"`
+	Name          string         `json:"name"`
+	MinVA         int            `json:"min_va"`
+	MaxVA         int            `json:"max_va"`
+	PricePerKWh   float64        `json:"price_per_kwh"`
+	Currency      string         `json:"currency"`
+	Model         string         `json:"model"`
+	Windows       []TariffWindow `json:"windows,omitempty"`
+	Blocks        []TariffBlock  `json:"blocks,omitempty"`
+	EffectiveFrom time.Time      `json:"effective_from"`
+	EffectiveTo   *time.Time     `json:"effective_to"`
+}
@@ -13,6 +13,8 @@ type Users struct {
 	Password       string `gorm:"type:varchar(100);not null"`
 	EmailVerfiedAt sql.NullTime
 	Premium        bool `gorm:"default:false"`
+	// Admin boleh mengelola data bersama seperti katalog tarif
+	Admin bool `gorm:"default:false"`
 }
 
 type UsersResponse struct {
@@ -0,0 +1,149 @@
+package helper
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// DefaultRoundTripEfficiency adalah efisiensi isi-ulang baterai lithium rumah tangga beserta inverternya
+	DefaultRoundTripEfficiency = 0.9
+	// DefaultReserveSoC adalah porsi kapasitas yang tidak dipakai untuk menghemat biaya agar tersedia saat padam
+	DefaultReserveSoC = 0.2
+	// batterySteadyStateDays membatasi pengulangan hari sampai isi baterai di awal dan akhir hari sama
+	batterySteadyStateDays = 10
+)
+
+// BatteryOptions adalah spesifikasi baterai. PeakLimitKW nol berarti tanpa batas puncak.
+type BatteryOptions struct {
+	CapacityKWh         float64
+	MaxChargeKW         float64
+	MaxDischargeKW      float64
+	RoundTripEfficiency float64
+	ReserveSoC          float64
+	PeakLimitKW         float64
+}
+
+// SimulateBattery mensimulasikan baterai terhadap profil beban per jam pada hari day. Baterai mengisi dari
+// jaringan pada jam termurah (tanpa melewati batas puncak) dan mengosongkan diri ke beban rumah pada jam yang
+// harganya, setelah rugi efisiensi, lebih mahal dari jam termurah, atau saat beban melebihi batas puncak.
+// Cadangan ReserveSoC tidak dipakai kecuali saat padam. Hari diulang sampai isi baterai di awal dan akhir hari
+// sama, sehingga hasilnya adalah kondisi harian yang stabil.
+func SimulateBattery(load [24]float64, appliances []entity.ApplianceResponse, tariff TariffSchedule, day time.Time, options BatteryOptions) (entity.BatteryResult, error) {
+	if options.MaxDischargeKW == 0 {
+		options.MaxDischargeKW = options.MaxChargeKW
+	}
+	switch {
+	case options.CapacityKWh <= 0:
+		return entity.BatteryResult{}, errors.New("capacity_kwh must be greater than 0")
+	case options.MaxChargeKW <= 0 || options.MaxDischargeKW < 0:
+		return entity.BatteryResult{}, errors.New("max_charge_kw must be greater than 0 and max_discharge_kw cannot be negative")
+	case options.RoundTripEfficiency <= 0 || options.RoundTripEfficiency > 1:
+		return entity.BatteryResult{}, errors.New("round_trip_efficiency must be between 0 and 1")
+	case options.ReserveSoC < 0 || options.ReserveSoC >= 1:
+		return entity.BatteryResult{}, errors.New("reserve_soc must be at least 0 and less than 1")
+	case options.PeakLimitKW < 0:
+		return entity.BatteryResult{}, errors.New("peak_limit_kw cannot be negative")
+	}
+
+	start, _, err := BucketBounds(day, BucketDay)
+	if err != nil {
+		return entity.BatteryResult{}, err
+	}
+	var prices [24]float64
+	cheapest := math.Inf(1)
+	for hour := range prices {
+		prices[hour] = tariff.Cost(start.Add(time.Duration(hour)*time.Hour), 1, 0)
+		cheapest = math.Min(cheapest, prices[hour])
+	}
+
+	reserve := options.CapacityKWh * options.ReserveSoC
+	soc, hours := reserve, []entity.BatteryHour(nil)
+	for i := 0; i < batterySteadyStateDays; i++ {
+		var end float64
+		hours, end = dispatchBattery(load, prices, cheapest, soc, options)
+		if math.Abs(end-soc) < 1e-9 {
+			break
+		}
+		soc = end
+	}
+
+	result := entity.BatteryResult{
+		Date:                start.Format("2006-01-02"),
+		Tariff:              tariff.VersionAt(start).Code,
+		CapacityKWh:         options.CapacityKWh,
+		MaxChargeKW:         options.MaxChargeKW,
+		MaxDischargeKW:      options.MaxDischargeKW,
+		RoundTripEfficiency: options.RoundTripEfficiency,
+		ReserveSoC:          options.ReserveSoC,
+		PeakLimitKW:         options.PeakLimitKW,
+		Hours:               hours,
+	}
+	for _, hour := range hours {
+		result.PeakBeforeKW = math.Max(result.PeakBeforeKW, hour.LoadKWh)
+		result.PeakAfterKW = math.Max(result.PeakAfterKW, hour.GridKWh)
+		result.DailyKWhBefore += hour.LoadKWh
+		result.DailyKWhAfter += hour.GridKWh
+		result.DailyCostBefore += hour.LoadKWh * hour.PricePerKWh
+		result.DailyCostAfter += hour.GridKWh * hour.PricePerKWh
+		result.DischargedKWh += hour.DischargeKWh
+	}
+	result.PeakShavingKW = result.PeakBeforeKW - result.PeakAfterKW
+	result.DailySavings = result.DailyCostBefore - result.DailyCostAfter
+	result.MonthlySavings = result.DailySavings * float64(start.AddDate(0, 1, -start.Day()).Day())
+
+	// Saat padam appliance prioritas dianggap menyala bersamaan pada daya ratingnya
+	for _, appliance := range appliances {
+		if appliance.Priority {
+			result.PriorityLoadKW += applianceKWhPerHour(appliance)
+		}
+	}
+	if result.PriorityLoadKW > 0 && result.PriorityLoadKW <= options.MaxDischargeKW {
+		full := options.CapacityKWh / result.PriorityLoadKW
+		atReserve := reserve / result.PriorityLoadKW
+		result.BackupHoursFull, result.BackupHoursReserve = &full, &atReserve
+	}
+	return result, nil
+}
+
+// dispatchBattery menjalankan satu hari mulai dari isi baterai soc dan mengembalikan keputusan per jam
+// beserta isi baterai di akhir hari
+func dispatchBattery(load, prices [24]float64, cheapest, soc float64, options BatteryOptions) ([]entity.BatteryHour, float64) {
+	reserve := options.CapacityKWh * options.ReserveSoC
+	peakLimit := options.PeakLimitKW
+	if peakLimit == 0 {
+		peakLimit = math.Inf(1)
+	}
+
+	hours := make([]entity.BatteryHour, 24)
+	for hour := range hours {
+		var charge, discharge float64
+		expensive := prices[hour]*options.RoundTripEfficiency > cheapest+1e-9
+		switch {
+		case expensive || load[hour] > peakLimit:
+			want := load[hour]
+			if !expensive {
+				want = load[hour] - peakLimit
+			}
+			discharge = math.Max(math.Min(math.Min(want, options.MaxDischargeKW), soc-reserve), 0)
+		case prices[hour] <= cheapest+1e-9:
+			room := (options.CapacityKWh - soc) / options.RoundTripEfficiency
+			charge = math.Max(math.Min(math.Min(options.MaxChargeKW, room), peakLimit-load[hour]), 0)
+		}
+
+		soc += charge*options.RoundTripEfficiency - discharge
+		hours[hour] = entity.BatteryHour{
+			Hour:         hour,
+			PricePerKWh:  prices[hour],
+			LoadKWh:      load[hour],
+			ChargeKWh:    charge,
+			DischargeKWh: discharge,
+			GridKWh:      load[hour] - discharge + charge,
+			SoCKWh:       soc,
+		}
+	}
+	return hours, soc
+}
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"math"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestSimulateBattery_TOUArbitrage(t *testing.T) {
+	var load [24]float64
+	for hour := range load {
+		load[hour] = 1
+	}
+	options := BatteryOptions{CapacityKWh: 10, MaxChargeKW: 1, RoundTripEfficiency: 0.8}
+
+	result, err := SimulateBattery(load, nil, peakTariff, scheduleDay, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seluruh beban WBP 17:00–22:00 (5 kWh) dipasok baterai; mengisi 5/0,8 = 6,25 kWh pada harga 1000
+	for hour := 17; hour < 22; hour++ {
+		if result.Hours[hour].GridKWh != 0 {
+			t.Fatalf("expected no grid import at %02d:00, got %+v", hour, result.Hours[hour])
+		}
+	}
+	if !approxEqual(result.DischargedKWh, 5) || !approxEqual(result.DailyKWhAfter-result.DailyKWhBefore, 1.25) {
+		t.Fatalf("unexpected energy flows: %+v", result)
+	}
+	if !approxEqual(result.DailySavings, 3750) || !approxEqual(result.MonthlySavings, 3750*31) {
+		t.Fatalf("expected savings 3750/day, got %v (%v/month)", result.DailySavings, result.MonthlySavings)
+	}
+	// Kondisi stabil: isi baterai di akhir hari sama dengan awal hari berikutnya
+	if first, last := result.Hours[0], result.Hours[23]; !approxEqual(last.SoCKWh+first.ChargeKWh*0.8, first.SoCKWh) {
+		t.Fatalf("battery is not in steady state: start %+v end %+v", first, last)
+	}
+	if result.BackupHoursFull != nil {
+		t.Fatalf("no priority appliances, backup should be empty: %v", *result.BackupHoursFull)
+	}
+}
+
+func TestSimulateBattery_PeakShavingAndBackup(t *testing.T) {
+	var load [24]float64
+	for hour := range load {
+		load[hour] = 1
+	}
+	load[19] = 3
+	appliances := []entity.ApplianceResponse{
+		{ID: 1, Name: "Kulkas", Power: 150, Priority: true},
+		{ID: 2, Name: "Router", Power: 50, Priority: true},
+		{ID: 3, Name: "AC", Power: 1000},
+	}
+	options := BatteryOptions{CapacityKWh: 5, MaxChargeKW: 2, RoundTripEfficiency: 0.9, ReserveSoC: 0.2, PeakLimitKW: 2}
+
+	result, err := SimulateBattery(load, appliances, FlatTariff(1000), scheduleDay, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PeakBeforeKW != 3 || !approxEqual(result.PeakAfterKW, 2) || !approxEqual(result.PeakShavingKW, 1) {
+		t.Fatalf("expected peak shaved from 3 to 2 kW, got %+v", result)
+	}
+	// Tarif flat tidak memberi selisih harga, sehingga rugi efisiensi menjadi biaya
+	if !approxEqual(result.DailySavings, -1000/0.9+1000) {
+		t.Fatalf("expected efficiency loss as negative savings, got %v", result.DailySavings)
+	}
+	if result.PriorityLoadKW != 0.2 || !approxEqual(*result.BackupHoursFull, 25) || !approxEqual(*result.BackupHoursReserve, 5) {
+		t.Fatalf("unexpected backup: %v kW, %v h, %v h", result.PriorityLoadKW, *result.BackupHoursFull, *result.BackupHoursReserve)
+	}
+
+	appliances[0].Power = 3000
+	result, _ = SimulateBattery(load, appliances, FlatTariff(1000), scheduleDay, options)
+	if result.BackupHoursFull != nil {
+		t.Fatal("priority load above max discharge should not report backup hours")
+	}
+
+	if _, err := SimulateBattery(load, nil, FlatTariff(1000), scheduleDay, BatteryOptions{MaxChargeKW: 1, RoundTripEfficiency: 0.9}); err == nil {
+		t.Fatal("expected error for zero capacity")
+	}
+}
@@ -0,0 +1,492 @@
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// MaxUploadSize adalah ukuran maksimum file yang diterima dari upload maupun URL
+const MaxUploadSize int64 = 10 << 20
+
+var (
+	ErrFileTooLarge           = errors.New("file exceeds the maximum upload size")
+	ErrUnsupportedContentType = errors.New("file does not look like a csv text file")
+)
+
+// MaxReportedRowErrors membatasi jumlah error per baris yang disimpan di laporan import
+const MaxReportedRowErrors = 500
+
+// ParsedCSV adalah hasil satu kali parsing file: tabel untuk ditampilkan, appliance, dan reading per baris
+type ParsedCSV struct {
+	Header     []string
+	Table      map[string][]string
+	Mapping    entity.ColumnMapping
+	Appliances []entity.ApplianceRequest
+	Readings   []entity.ReadingRequest
+
+	RowsRead     int
+	RowsAccepted int
+	RowErrors    []entity.RowError
+}
+
+// CSVFile adalah isi mentah file CSV yang sudah dibaca satu kali dari sumbernya
+type CSVFile struct {
+	Header  []string
+	Records [][]string
+	// Lines adalah nomor baris file (dimulai dari 1) untuk setiap record
+	Lines     []int
+	Table     map[string][]string
+	RowErrors []entity.RowError
+}
+
+// ParseCSV membaca CSV dari r satu kali dan memetakan kolomnya secara otomatis dari nama header
+func ParseCSV(r io.Reader) (*ParsedCSV, error) {
+	file, err := ReadCSVFile(r)
+	if err != nil {
+		return nil, err
+	}
+	return file.Parse(DetectMapping(file.Header))
+}
+
+// ReadCSVFile membaca header dan seluruh baris CSV sekaligus menyusun tabel untuk ditampilkan
+func ReadCSVFile(r io.Reader) (*CSVFile, error) {
+	// Tebak pemisah kolom dari awal file dan buang BOM UTF-8 (umum pada ekspor Excel)
+	buffered := bufio.NewReaderSize(r, 64<<10)
+	sample, err := buffered.Peek(16 << 10)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("error reading CSV: %w", err)
+	}
+	delimiter := SniffDelimiter(sample)
+	if bytes.HasPrefix(sample, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = delimiter
+	// Read header first
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	if len(header) == 0 {
+		return nil, errors.New("csv header is empty")
+	}
+
+	table := make(map[string][]string)
+	for _, col := range header {
+		table[col] = []string{}
+	}
+
+	// Read remaining records one by one to be defensive against malformed rows
+	var records [][]string
+	var lines []int
+	var rowErrors []entity.RowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Baris rusak dilewati, tetapi kegagalan membaca sumber (mis. melebihi batas ukuran) menghentikan parsing
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("error reading CSV: %w", err)
+			}
+			// Catat di laporan dan lanjutkan ke baris berikutnya
+			rowErrors = append(rowErrors, entity.RowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		for i := 0; i < len(header); i++ {
+			var val string
+			if i < len(record) {
+				val = record[i]
+			} else {
+				val = ""
+			}
+			table[header[i]] = append(table[header[i]], val)
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	return &CSVFile{Header: header, Records: records, Lines: lines, Table: table, RowErrors: rowErrors}, nil
+}
+
+// Parse memetakan baris file ke appliance dan reading menggunakan mapping kolom yang diberikan
+func (f *CSVFile) Parse(mapping entity.ColumnMapping) (*ParsedCSV, error) {
+	if err := ValidateMapping(mapping); err != nil {
+		return nil, err
+	}
+	mapping = withHeaderUnits(mapping)
+
+	columns, err := resolveColumns(f.Header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	appliances, readings, accepted, rowErrors := parseApplianceRecords(f.Records, f.Lines, columns, mapping)
+
+	return &ParsedCSV{
+		Header:       f.Header,
+		Table:        f.Table,
+		Mapping:      mapping,
+		Appliances:   appliances,
+		Readings:     readings,
+		RowsRead:     len(f.Records) + len(f.RowErrors),
+		RowsAccepted: accepted,
+		RowErrors:    mergeRowErrors(f.RowErrors, rowErrors),
+	}, nil
+}
+
+// SniffCSV memastikan isi file berupa teks (bukan biner) dan mengembalikan reader yang tetap dimulai dari byte pertama
+func SniffCSV(r io.Reader) (io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if !strings.HasPrefix(contentType, "text/plain") && !strings.HasPrefix(contentType, "text/csv") {
+		return nil, fmt.Errorf("%w: detected %s", ErrUnsupportedContentType, contentType)
+	}
+
+	return io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// LimitSize membatasi jumlah byte yang dibaca dari r; membaca melebihi limit menghasilkan ErrFileTooLarge
+func LimitSize(r io.Reader, limit int64) io.Reader {
+	return &limitedReader{r: r, remaining: limit}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// cek apakah masih ada data setelah batas
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, ErrFileTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// parseApplianceRecords memetakan baris CSV ke appliance dan reading berdasarkan posisi kolom mapping.
+// Baris yang tidak valid ditolak dan dicatat sebagai RowError beserta nomor baris dan nama kolomnya.
+func parseApplianceRecords(records [][]string, lines []int, columns columnIndexes, mapping entity.ColumnMapping) ([]entity.ApplianceRequest, []entity.ReadingRequest, int, []entity.RowError) {
+	var appliances []entity.ApplianceRequest
+	var readings []entity.ReadingRequest
+	var rowErrors []entity.RowError
+	accepted := 0
+	deviceDurations := make(map[string][]float64)
+	seenNames := make(map[string]bool)
+
+	for i, record := range records {
+		line := i + 2
+		if i < len(lines) {
+			line = lines[i]
+		}
+		reject := func(column, message string) {
+			rowErrors = append(rowErrors, entity.RowError{Line: line, Column: column, Message: message})
+		}
+
+		// retrieve values safely by index
+		get := func(idx int) string {
+			if idx >= 0 && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		deviceName := get(columns.appliance)
+		if deviceName == "" {
+			reject(mapping.ApplianceColumn, "appliance name is empty")
+			continue
+		}
+
+		// energy (dinormalisasi ke kWh); nilai boleh memakai koma desimal atau akhiran satuan
+		energy, err := parseQuantity(get(columns.energy), quantityEnergy, mapping.EnergyUnit, mapping.DecimalSeparator, mapping.ThousandSeparator)
+		if err != nil {
+			reject(mapping.EnergyColumn, fmt.Sprintf("invalid energy value %q", get(columns.energy)))
+			continue
+		}
+
+		// power (dinormalisasi ke Watt)
+		power, ok := parseOptionalQuantity(get(columns.power), quantityPower, mapping.PowerUnit, mapping)
+		if !ok {
+			reject(mapping.PowerColumn, fmt.Sprintf("invalid power value %q", get(columns.power)))
+			continue
+		}
+		powerInt := int(math.Round(power))
+
+		// duration (dinormalisasi ke jam)
+		duration, ok := parseOptionalQuantity(get(columns.duration), quantityDuration, mapping.DurationUnit, mapping)
+		if !ok {
+			reject(mapping.DurationColumn, fmt.Sprintf("invalid duration value %q", get(columns.duration)))
+			continue
+		}
+
+		// cost
+		cost, ok := parseOptionalCurrency(get(columns.cost), mapping)
+		if !ok {
+			reject(mapping.CostColumn, fmt.Sprintf("invalid cost value %q", get(columns.cost)))
+			continue
+		}
+
+		// Setiap baris disimpan sebagai reading sehingga wajib memiliki waktu yang valid
+		rawTimestamp := get(columns.timestamp)
+		timestampColumn := mapping.TimestampColumn
+		if rawTimestamp == "" && columns.date >= 0 {
+			rawTimestamp = strings.TrimSpace(get(columns.date) + " " + get(columns.time))
+			timestampColumn = mapping.DateColumn
+		}
+		if timestampColumn == "" {
+			timestampColumn = "timestamp"
+		}
+		if rawTimestamp == "" {
+			reject(timestampColumn, "timestamp is empty")
+			continue
+		}
+		timestamp, err := parseMappedTimestamp(rawTimestamp, mapping.TimestampFormat)
+		if err != nil {
+			reject(timestampColumn, fmt.Sprintf("invalid timestamp %q", rawTimestamp))
+			continue
+		}
+		readings = append(readings, entity.ReadingRequest{
+			ApplianceName: deviceName,
+			Timestamp:     timestamp,
+			Energy:        energy,
+			Power:         powerInt,
+			Duration:      duration,
+		})
+		accepted++
+
+		// other fields
+		typ := get(columns.typ)
+		loc := get(columns.location)
+		status := get(columns.status)
+		conn := get(columns.connectivity)
+
+		// For duration fallback set to 0 if missing
+		// priority: example rule power > 500
+		priority := powerInt > 500
+
+		// If appliance already seen, append duration/energy
+		if seenNames[deviceName] {
+			deviceDurations[deviceName] = append(deviceDurations[deviceName], duration)
+			continue
+		}
+
+		seenNames[deviceName] = true
+
+		appliance := entity.ApplianceRequest{
+			Name:         deviceName,
+			Type:         typ,
+			Location:     loc,
+			Power:        powerInt,
+			Energy:       energy,
+			Cost:         cost,
+			Status:       status,
+			Connectivity: conn,
+			Priority:     priority,
+		}
+
+		deviceDurations[deviceName] = append(deviceDurations[deviceName], duration)
+		appliances = append(appliances, appliance)
+	}
+
+	// Kelompokkan reading per appliance untuk menghitung penggunaan dari riwayat
+	readingsByName := make(map[string][]entity.Reading)
+	for _, reading := range readings {
+		readingsByName[reading.ApplianceName] = append(readingsByName[reading.ApplianceName], entity.Reading{
+			Timestamp: reading.Timestamp,
+			Energy:    reading.Energy,
+			Power:     reading.Power,
+			Duration:  reading.Duration,
+		})
+	}
+
+	// Tambahkan AverageUsage untuk setiap appliance
+	for i := range appliances {
+		deviceName := appliances[i].Name
+
+		// Jika riwayat tersedia: UsageToday = hari terakhir, AverageUsage = rata-rata jam per hari
+		if history := readingsByName[deviceName]; len(history) > 0 {
+			summary := SummarizeReadings(history)
+			appliances[i].UsageToday = summary.DailyHours
+			appliances[i].AverageUsage = summary.AverageHours
+			appliances[i].Energy = summary.DailyEnergy
+			continue
+		}
+
+		durations := deviceDurations[deviceName]
+		if len(durations) > 0 {
+			var totalDuration float64
+			for _, d := range durations {
+				totalDuration += d
+			}
+			averageUsage := totalDuration / float64(len(durations))
+			appliances[i].UsageToday = totalDuration
+			appliances[i].AverageUsage = averageUsage
+		}
+	}
+
+	return appliances, readings, accepted, rowErrors
+}
+
+// parseOptionalNumber mengembalikan 0 untuk nilai kosong dan false jika nilai tidak berupa angka
+func parseOptionalNumber(value string, mapping entity.ColumnMapping) (float64, bool) {
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := ParseNumber(value, mapping.DecimalSeparator, mapping.ThousandSeparator)
+	return parsed, err == nil
+}
+
+// parseOptionalCurrency seperti parseOptionalNumber, tetapi nilai boleh diawali simbol rupiah
+func parseOptionalCurrency(value string, mapping entity.ColumnMapping) (float64, bool) {
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := parseCurrency(value, mapping.DecimalSeparator, mapping.ThousandSeparator)
+	return parsed, err == nil
+}
+
+// parseOptionalQuantity seperti parseOptionalNumber, tetapi mengubah nilai ke satuan standar
+func parseOptionalQuantity(value string, kind int, unit string, mapping entity.ColumnMapping) (float64, bool) {
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := parseQuantity(value, kind, unit, mapping.DecimalSeparator, mapping.ThousandSeparator)
+	return parsed, err == nil
+}
+
+// mergeRowErrors menggabungkan error pembacaan dan error parsing, diurutkan menurut nomor baris
+func mergeRowErrors(readErrors, parseErrors []entity.RowError) []entity.RowError {
+	merged := make([]entity.RowError, 0, len(readErrors)+len(parseErrors))
+	merged = append(merged, readErrors...)
+	merged = append(merged, parseErrors...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Line < merged[j].Line })
+	return merged
+}
+
+// BuildImportReport menyusun laporan validasi dari hasil parsing dan nama appliance yang sudah ada
+func BuildImportReport(parsed *ParsedCSV, profile string, existingNames []string, dryRun bool) entity.ImportReport {
+	existing := make(map[string]bool, len(existingNames))
+	for _, name := range existingNames {
+		existing[name] = true
+	}
+
+	changes := make([]entity.ApplianceChange, 0, len(parsed.Appliances))
+	for _, appliance := range parsed.Appliances {
+		action := entity.ImportActionCreate
+		if existing[appliance.Name] {
+			action = entity.ImportActionUpdate
+		}
+		changes = append(changes, entity.ApplianceChange{Name: appliance.Name, Action: action})
+	}
+
+	rowErrors := parsed.RowErrors
+	truncated := len(rowErrors) > MaxReportedRowErrors
+	if truncated {
+		rowErrors = rowErrors[:MaxReportedRowErrors]
+	}
+	if rowErrors == nil {
+		rowErrors = []entity.RowError{}
+	}
+
+	return entity.ImportReport{
+		DryRun:          dryRun,
+		RowsRead:        parsed.RowsRead,
+		RowsAccepted:    parsed.RowsAccepted,
+		RowsRejected:    parsed.RowsRead - parsed.RowsAccepted,
+		ErrorsTruncated: truncated,
+		Errors:          rowErrors,
+		Header:          parsed.Header,
+		Profile:         profile,
+		Schema:          parsed.Mapping,
+		Appliances:      changes,
+		Readings:        len(parsed.Readings),
+	}
+}
+
+// Lokasi waktu default untuk data tanpa zona waktu (sama dengan TimeZone database)
+var jakarta = func() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}()
+
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+	"02-01-2006 15:04",
+	"02-01-2006",
+}
+
+// parseMappedTimestamp memakai format profil jika ada, selain itu mengenali format umum
+func parseMappedTimestamp(value, format string) (time.Time, error) {
+	if format == "" {
+		return ParseTimestamp(value)
+	}
+	t, err := time.ParseInLocation(TimestampLayout(format), strings.TrimSpace(value), jakarta)
+	if err != nil {
+		// Nilai dari importer non-CSV (mis. tanggal XLSX) memakai format standar, bukan format profil
+		if fallback, fallbackErr := ParseTimestamp(value); fallbackErr == nil {
+			return fallback, nil
+		}
+	}
+	return t, err
+}
+
+// ParseTimestamp mengenali format tanggal/waktu yang umum di file ekspor smart meter
+func ParseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("timestamp is empty")
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, jakarta); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized timestamp format: %q", value)
+}
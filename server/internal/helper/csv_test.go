@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestParseCSV_TableAndAppliancesFromOneRead(t *testing.T) {
+	parsed, err := ParseCSV(strings.NewReader(detailedCSV))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+
+	if got := parsed.Table["device"]; len(got) != 2 || got[0] != "Refrigerator" {
+		t.Fatalf("unexpected device column in table: %v", got)
+	}
+	if len(parsed.Appliances) != 2 {
+		t.Fatalf("expected 2 appliances, got %d", len(parsed.Appliances))
+	}
+	if len(parsed.Readings) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(parsed.Readings))
+	}
+}
+
+func TestSniffCSV_RejectsBinary(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if _, err := SniffCSV(bytes.NewReader(png)); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Fatalf("expected ErrUnsupportedContentType, got %v", err)
+	}
+
+	r, err := SniffCSV(strings.NewReader(simpleCSV))
+	if err != nil {
+		t.Fatalf("expected csv text to pass sniffing, got %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	if string(content) != simpleCSV {
+		t.Fatalf("sniffing must not consume the file content")
+	}
+}
+
+func TestParseCSV_SizeLimit(t *testing.T) {
+	_, err := ParseCSV(LimitSize(strings.NewReader(simpleCSV), 60))
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got %v", err)
+	}
+
+	if _, err := ParseCSV(LimitSize(strings.NewReader(simpleCSV), int64(len(simpleCSV)))); err != nil {
+		t.Fatalf("file exactly at the limit should parse, got %v", err)
+	}
+}
+
+func TestParseCSV_RowErrorsReport(t *testing.T) {
+	input := "Appliance,Date,Time,Energy_Consumption\n" +
+		"Fridge,2024-01-01,00:00,0.5\n" +
+		",2024-01-01,01:00,0.5\n" +
+		"Fridge,2024-01-01,02:00,abc\n" +
+		"TV,not-a-date,03:00,0.2\n" +
+		"TV,2024-01-01,04:00,0.3\n"
+
+	parsed, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+	if parsed.RowsRead != 5 || parsed.RowsAccepted != 2 {
+		t.Fatalf("expected 5 rows read and 2 accepted, got %d/%d", parsed.RowsRead, parsed.RowsAccepted)
+	}
+
+	want := []entity.RowError{
+		{Line: 3, Column: "Appliance"},
+		{Line: 4, Column: "Energy_Consumption"},
+		{Line: 5, Column: "Date"},
+	}
+	if len(parsed.RowErrors) != len(want) {
+		t.Fatalf("expected %d row errors, got %+v", len(want), parsed.RowErrors)
+	}
+	for i, rowErr := range parsed.RowErrors {
+		if rowErr.Line != want[i].Line || rowErr.Column != want[i].Column {
+			t.Fatalf("row error %d: expected line %d column %q, got %+v", i, want[i].Line, want[i].Column, rowErr)
+		}
+	}
+
+	report := BuildImportReport(parsed, "KSE simple", []string{"TV"}, true)
+	if report.RowsRejected != 3 || !report.DryRun {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	actions := map[string]string{}
+	for _, change := range report.Appliances {
+		actions[change.Name] = change.Action
+	}
+	if actions["Fridge"] != entity.ImportActionCreate || actions["TV"] != entity.ImportActionUpdate {
+		t.Fatalf("unexpected appliance actions: %v", actions)
+	}
+}
+
+func TestParseCSV_RejectsRowsWithoutTimestamp(t *testing.T) {
+	input := "Appliance,Energy_Consumption\n" +
+		"Fridge,0.5\n" +
+		"TV,0.2\n"
+
+	parsed, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+	if parsed.RowsRead != 2 || parsed.RowsAccepted != 0 {
+		t.Fatalf("expected 2 rows read and 0 accepted, got %d/%d", parsed.RowsRead, parsed.RowsAccepted)
+	}
+	if len(parsed.Readings) != 0 || len(parsed.Appliances) != 0 {
+		t.Fatalf("rows without timestamp must not be stored, got %d readings and %d appliances", len(parsed.Readings), len(parsed.Appliances))
+	}
+	if len(parsed.RowErrors) != 2 {
+		t.Fatalf("expected 2 row errors, got %+v", parsed.RowErrors)
+	}
+	for i, rowErr := range parsed.RowErrors {
+		if rowErr.Line != i+2 || rowErr.Column != "timestamp" {
+			t.Fatalf("row error %d: expected line %d column %q, got %+v", i, i+2, "timestamp", rowErr)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrURLNotAllowed     = errors.New("only http and https urls are allowed")
+	ErrAddressNotAllowed = errors.New("url resolves to a private or internal address")
+	ErrTooManyRedirects  = errors.New("too many redirects")
+	ErrFetchTimeout      = errors.New("timed out fetching file")
+	ErrFetchFailed       = errors.New("failed to fetch file")
+)
+
+// FetchStatusError dikembalikan jika server tujuan tidak merespons 200 OK
+type FetchStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *FetchStatusError) Error() string {
+	return fmt.Sprintf("failed to fetch file: %s", e.Status)
+}
+
+type FetcherConfig struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	MaxBytes     int64
+	// AllowPrivate mematikan pemeriksaan alamat internal; hanya untuk pengujian lokal
+	AllowPrivate bool
+}
+
+func DefaultFetcherConfig() FetcherConfig {
+	return FetcherConfig{
+		Timeout:      30 * time.Second,
+		MaxRedirects: 3,
+		MaxBytes:     MaxUploadSize,
+	}
+}
+
+// Fetcher mengunduh file dari URL milik user tanpa membuka akses ke jaringan internal server
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+func NewFetcher(cfg FetcherConfig) *Fetcher {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !cfg.AllowPrivate {
+		// Diperiksa pada alamat IP yang benar-benar di-dial, sehingga aman dari DNS rebinding
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || IsDisallowedIP(ip) {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > cfg.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return validateFetchURL(req.URL)
+		},
+	}
+
+	return &Fetcher{client: client, maxBytes: cfg.MaxBytes}
+}
+
+// Fetch mengembalikan body file; pembacaan melebihi batas ukuran menghasilkan ErrFileTooLarge
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrURLNotAllowed, err)
+	}
+	if err := validateFetchURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrURLNotAllowed, err)
+	}
+
+	response, err := f.client.Do(req)
+	if err != nil {
+		return nil, classifyFetchError(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, &FetchStatusError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+
+	if response.ContentLength > f.maxBytes {
+		response.Body.Close()
+		return nil, ErrFileTooLarge
+	}
+
+	return &fetchBody{reader: LimitSize(response.Body, f.maxBytes), body: response.Body, contentType: response.Header.Get("Content-Type")}, nil
+}
+
+// fetchBody menerjemahkan error saat membaca body (mis. timeout) ke error bertipe milik Fetcher
+type fetchBody struct {
+	reader      io.Reader
+	body        io.ReadCloser
+	contentType string
+}
+
+func (b *fetchBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if err != nil && err != io.EOF && !errors.Is(err, ErrFileTooLarge) {
+		err = classifyFetchError(err)
+	}
+	return n, err
+}
+
+func (b *fetchBody) Close() error {
+	return b.body.Close()
+}
+
+// ContentType mengembalikan header Content-Type dari response server
+func (b *fetchBody) ContentType() string {
+	return b.contentType
+}
+
+func validateFetchURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrURLNotAllowed
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrURLNotAllowed)
+	}
+	return nil
+}
+
+func classifyFetchError(err error) error {
+	switch {
+	case errors.Is(err, ErrAddressNotAllowed), errors.Is(err, ErrTooManyRedirects), errors.Is(err, ErrURLNotAllowed):
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrFetchTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrFetchTimeout
+	}
+
+	return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+}
+
+var disallowedNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",       // "this" network
+		"100.64.0.0/10",   // carrier-grade NAT
+		"192.0.0.0/24",    // IETF protocol assignments
+		"192.0.2.0/24",    // TEST-NET-1
+		"198.18.0.0/15",   // benchmarking
+		"198.51.100.0/24", // TEST-NET-2
+		"203.0.113.0/24",  // TEST-NET-3
+		"240.0.0.0/4",     // reserved
+		"64:ff9b::/96",    // NAT64
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// IsDisallowedIP melaporkan apakah ip termasuk alamat loopback, privat, link-local, atau alamat khusus lain
+func IsDisallowedIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, network := range disallowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
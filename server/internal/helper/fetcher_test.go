@@ -0,0 +1,138 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetcher_RejectsNonHTTPScheme(t *testing.T) {
+	f := NewFetcher(DefaultFetcherConfig())
+	for _, u := range []string{"file:///etc/passwd", "gopher://example.com", "ftp://example.com/a.csv", "http://"} {
+		if _, err := f.Fetch(context.Background(), u); !errors.Is(err, ErrURLNotAllowed) {
+			t.Fatalf("expected ErrURLNotAllowed for %q, got %v", u, err)
+		}
+	}
+}
+
+func TestFetcher_RejectsLoopbackDestination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(simpleCSV))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(DefaultFetcherConfig())
+	if _, err := f.Fetch(context.Background(), srv.URL); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Fatalf("expected ErrAddressNotAllowed for loopback server, got %v", err)
+	}
+
+	// localhost harus ditolak setelah resolusi DNS
+	localhostURL := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	if _, err := f.Fetch(context.Background(), localhostURL); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Fatalf("expected ErrAddressNotAllowed for localhost, got %v", err)
+	}
+}
+
+func testFetcher(cfg FetcherConfig) *Fetcher {
+	cfg.AllowPrivate = true
+	return NewFetcher(cfg)
+}
+
+func TestFetcher_LimitsBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// tanpa Content-Length agar batas diterapkan saat membaca
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("a,b\n", 100)))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultFetcherConfig()
+	cfg.MaxBytes = 64
+	body, err := testFetcher(cfg).Fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected fetch error: %v", err)
+	}
+	defer body.Close()
+
+	if _, err := io.ReadAll(body); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got %v", err)
+	}
+}
+
+func TestFetcher_RedirectLimitAndStatus(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/loop":
+			http.Redirect(w, r, srv.URL+"/loop", http.StatusFound)
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Write([]byte(simpleCSV))
+		}
+	}))
+	defer srv.Close()
+
+	f := testFetcher(DefaultFetcherConfig())
+
+	if _, err := f.Fetch(context.Background(), srv.URL+"/loop"); !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("expected ErrTooManyRedirects, got %v", err)
+	}
+
+	var statusErr *FetchStatusError
+	if _, err := f.Fetch(context.Background(), srv.URL+"/missing"); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected FetchStatusError 404, got %v", err)
+	}
+
+	body, err := f.Fetch(context.Background(), srv.URL+"/data.csv")
+	if err != nil {
+		t.Fatalf("unexpected fetch error: %v", err)
+	}
+	defer body.Close()
+	if _, err := ParseCSV(body); err != nil {
+		t.Fatalf("expected fetched csv to parse, got %v", err)
+	}
+}
+
+func TestFetcher_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(simpleCSV))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultFetcherConfig()
+	cfg.Timeout = 50 * time.Millisecond
+	if _, err := testFetcher(cfg).Fetch(context.Background(), srv.URL); !errors.Is(err, ErrFetchTimeout) {
+		t.Fatalf("expected ErrFetchTimeout, got %v", err)
+	}
+}
+
+func TestIsDisallowedIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":            true,
+		"10.1.2.3":             true,
+		"172.16.0.1":           true,
+		"192.168.1.10":         true,
+		"169.254.169.254":      true,
+		"100.64.0.1":           true,
+		"0.0.0.0":              true,
+		"::1":                  true,
+		"fd00::1":              true,
+		"fe80::1":              true,
+		"::ffff:127.0.0.1":     true,
+		"8.8.8.8":              false,
+		"2001:4860:4860::8888": false,
+	}
+	for addr, want := range cases {
+		if got := IsDisallowedIP(net.ParseIP(addr)); got != want {
+			t.Errorf("IsDisallowedIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
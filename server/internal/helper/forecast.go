@@ -0,0 +1,254 @@
+package helper
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// ForecastHistoryDays adalah jumlah hari penuh sebelum hari ini yang dipakai untuk membaca tren dan pola mingguan
+	ForecastHistoryDays = 28
+	// ForecastConfidence adalah tingkat kepercayaan interval perkiraan (z = 1,96)
+	ForecastConfidence = 0.95
+	minForecastDays    = 14
+	forecastZ          = 1.96
+	// backfitting berhenti jika perubahan efek hari dalam minggu lebih kecil dari forecastTolerance
+	forecastIterations = 100
+	forecastTolerance  = 1e-12
+)
+
+var ErrInsufficientHistory = errors.New("at least 14 days of readings in the last 28 days are needed for a forecast")
+
+// forecastModel adalah konsumsi harian = level + trend × hari + efek hari dalam minggu
+type forecastModel struct {
+	level, trend float64
+	effects      [7]float64
+	sigma        float64
+	points       int
+}
+
+func (m forecastModel) predict(x float64, weekday time.Weekday) float64 {
+	return math.Max(m.level+m.trend*x+m.effects[weekday], 0)
+}
+
+// ForecastMonth memperkirakan total konsumsi dan tagihan bulan asOf. Hari sebelum hari ini dihitung dari
+// reading, sedangkan hari ini sampai akhir bulan diperkirakan dari model tren linear dengan efek hari dalam
+// minggu yang dicocokkan pada 28 hari penuh terakhir; hari tanpa reading tidak diikutkan. Interval memakai
+// simpangan residual harian ditambah ketidakpastian level. Biaya hari yang sudah lewat dihitung dari reading
+// (PriceReadings), sedangkan energi perkiraan dibagi ke jam-jam setiap hari mengikuti pola per jam riwayat
+// dan dihargai dengan tarif pada jam tersebut, melanjutkan konsumsi bulan berjalan untuk tarif block.
+func ForecastMonth(readings []entity.Reading, tariff TariffSchedule, asOf time.Time) (entity.MonthForecast, error) {
+	monthStart, monthEnd, err := BucketBounds(asOf, BucketMonth)
+	if err != nil {
+		return entity.MonthForecast{}, err
+	}
+	today := startOfDay(asOf)
+
+	daily := make(map[string]float64)
+	var elapsed, history []entity.Reading
+	for _, reading := range readings {
+		if !reading.Timestamp.Before(today) {
+			continue
+		}
+		daily[startOfDay(reading.Timestamp).Format("2006-01-02")] += reading.Energy
+		if !reading.Timestamp.Before(monthStart) {
+			elapsed = append(elapsed, reading)
+		}
+		if !reading.Timestamp.Before(today.AddDate(0, 0, -ForecastHistoryDays)) {
+			history = append(history, reading)
+		}
+	}
+
+	var xs, ys []float64
+	var weekdays []time.Weekday
+	for i := 0; i < ForecastHistoryDays; i++ {
+		day := today.AddDate(0, 0, i-ForecastHistoryDays)
+		if energy, ok := daily[day.Format("2006-01-02")]; ok {
+			xs = append(xs, float64(i-ForecastHistoryDays))
+			ys = append(ys, energy)
+			weekdays = append(weekdays, day.Weekday())
+		}
+	}
+	if len(xs) < minForecastDays {
+		return entity.MonthForecast{}, ErrInsufficientHistory
+	}
+	model := fitForecastModel(xs, ys, weekdays)
+
+	forecast := entity.MonthForecast{
+		Month:           monthStart.Format("2006-01"),
+		AsOf:            today.Format("2006-01-02"),
+		TariffCode:      tariff.VersionAt(monthStart).Code,
+		HistoryDays:     model.points,
+		ConfidenceLevel: ForecastConfidence,
+		TrendPerDay:     model.trend,
+		WeekdayEffects:  model.effects,
+		Days:            []entity.ForecastDay{},
+	}
+
+	for day := monthStart; day.Before(today); day = day.AddDate(0, 0, 1) {
+		energy := daily[day.Format("2006-01-02")]
+		forecast.ActualEnergy += energy
+		forecast.DaysElapsed++
+		forecast.Days = append(forecast.Days, entity.ForecastDay{Date: day.Format("2006-01-02"), Energy: energy, Lower: energy, Upper: energy})
+	}
+
+	var expected float64
+	var remaining []forecastRemainder
+	for x, day := 0, today; day.Before(monthEnd); x, day = x+1, day.AddDate(0, 0, 1) {
+		energy := model.predict(float64(x), day.Weekday())
+		expected += energy
+		remaining = append(remaining, forecastRemainder{start: day, energy: energy})
+		forecast.DaysRemaining++
+		forecast.Days = append(forecast.Days, entity.ForecastDay{
+			Date:     day.Format("2006-01-02"),
+			Energy:   energy,
+			Lower:    math.Max(energy-forecastZ*model.sigma, 0),
+			Upper:    energy + forecastZ*model.sigma,
+			Forecast: true,
+		})
+	}
+
+	// Galat harian dianggap saling bebas, sedangkan galat level sama untuk semua hari yang tersisa
+	days := float64(forecast.DaysRemaining)
+	spread := forecastZ * math.Sqrt(days*model.sigma*model.sigma+math.Pow(days*model.sigma, 2)/float64(model.points))
+
+	forecast.Energy = forecast.ActualEnergy + expected
+	forecast.EnergyLower = forecast.ActualEnergy + math.Max(expected-spread, 0)
+	forecast.EnergyUpper = forecast.ActualEnergy + expected + spread
+	for _, priced := range PriceReadings(elapsed, tariff) {
+		forecast.ActualCost += priced.Cost
+	}
+
+	shape, _ := HourlyConsumptionProfile(history)
+	price := func(total float64) float64 {
+		return forecast.ActualCost + priceRemainder(remaining, total, shape, tariff, forecast.ActualEnergy)
+	}
+	forecast.Bill = price(expected)
+	forecast.BillLower = price(forecast.EnergyLower - forecast.ActualEnergy)
+	forecast.BillUpper = price(forecast.EnergyUpper - forecast.ActualEnergy)
+	return forecast, nil
+}
+
+// forecastRemainder adalah energi perkiraan satu hari yang belum lewat
+type forecastRemainder struct {
+	start  time.Time
+	energy float64
+}
+
+// priceRemainder membagi total energi perkiraan ke setiap hari sebanding dengan perkiraannya (rata jika
+// perkiraannya nol), lalu ke setiap jam mengikuti shape, dan menghargai setiap jam dengan tarif pada jam itu
+func priceRemainder(days []forecastRemainder, total float64, shape [24]float64, tariff TariffSchedule, monthToDate float64) float64 {
+	var expected, hourly float64
+	for _, day := range days {
+		expected += day.energy
+	}
+	for _, energy := range shape {
+		hourly += energy
+	}
+
+	var cost float64
+	for _, day := range days {
+		share := 1 / float64(len(days))
+		if expected > 0 {
+			share = day.energy / expected
+		}
+		for hour := range shape {
+			weight := 1.0 / 24
+			if hourly > 0 {
+				weight = shape[hour] / hourly
+			}
+			energy := total * share * weight
+			cost += tariff.Cost(day.start.Add(time.Duration(hour)*time.Hour), energy, monthToDate)
+			monthToDate += energy
+		}
+	}
+	return cost
+}
+
+// fitForecastModel mencocokkan tren linear dan efek hari dalam minggu secara bergantian (backfitting)
+// sampai efeknya stabil; efek dipusatkan sehingga rata-ratanya pada data riwayat bernilai nol
+func fitForecastModel(xs, ys []float64, weekdays []time.Weekday) forecastModel {
+	n := float64(len(xs))
+	var model forecastModel
+	model.points = len(xs)
+
+	adjusted := make([]float64, len(ys))
+	for iteration := 0; iteration < forecastIterations; iteration++ {
+		for i := range ys {
+			adjusted[i] = ys[i] - model.effects[weekdays[i]]
+		}
+		model.level, model.trend = linearFit(xs, adjusted)
+
+		var sums, counts [7]float64
+		for i := range ys {
+			sums[weekdays[i]] += ys[i] - model.level - model.trend*xs[i]
+			counts[weekdays[i]]++
+		}
+		var effects [7]float64
+		var center float64
+		for weekday := range effects {
+			if counts[weekday] > 0 {
+				effects[weekday] = sums[weekday] / counts[weekday]
+				center += effects[weekday] * counts[weekday] / n
+			}
+		}
+
+		var change float64
+		for weekday := range effects {
+			if counts[weekday] > 0 {
+				effects[weekday] -= center
+			}
+			change = math.Max(change, math.Abs(effects[weekday]-model.effects[weekday]))
+		}
+		model.effects = effects
+		if change < forecastTolerance {
+			break
+		}
+	}
+	for i := range ys {
+		adjusted[i] = ys[i] - model.effects[weekdays[i]]
+	}
+	model.level, model.trend = linearFit(xs, adjusted)
+
+	present := 0
+	var seen [7]bool
+	for _, weekday := range weekdays {
+		if !seen[weekday] {
+			seen[weekday] = true
+			present++
+		}
+	}
+	var squares float64
+	for i := range ys {
+		residual := ys[i] - model.level - model.trend*xs[i] - model.effects[weekdays[i]]
+		squares += residual * residual
+	}
+	dof := math.Max(n-2-float64(present-1), 1)
+	model.sigma = math.Sqrt(squares / dof)
+	return model
+}
+
+// linearFit mengembalikan intercept dan kemiringan regresi kuadrat terkecil y terhadap x
+func linearFit(xs, ys []float64) (float64, float64) {
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var sxx, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	if sxx == 0 {
+		return meanY, 0
+	}
+	slope := sxy / sxx
+	return meanY - slope*meanX, slope
+}
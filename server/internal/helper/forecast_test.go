@@ -0,0 +1,114 @@
+package helper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// forecastAsOf jatuh pada Jumat; riwayat 28 hari penuh dimulai Jumat 16 Februari 2024
+var (
+	forecastAsOf        = time.Date(2024, time.March, 15, 10, 0, 0, 0, jakarta)
+	forecastHistoryFrom = time.Date(2024, time.February, 16, 0, 0, 0, 0, jakarta)
+)
+
+// syntheticReadings membuat satu reading per hari pukul 12:00 dengan energi energy(i) untuk hari ke-i
+func syntheticReadings(days int, energy func(i int, day time.Time) float64) []entity.Reading {
+	readings := make([]entity.Reading, 0, days)
+	for i := 0; i < days; i++ {
+		day := forecastHistoryFrom.AddDate(0, 0, i)
+		readings = append(readings, entity.Reading{Timestamp: day.Add(12 * time.Hour), Energy: energy(i, day)})
+	}
+	return readings
+}
+
+func TestForecastMonth_SyntheticSeries(t *testing.T) {
+	tests := []struct {
+		name   string
+		energy func(i int, day time.Time) float64
+		actual float64
+		total  float64
+		trend  float64
+	}{
+		{"flat", func(int, time.Time) float64 { return 10 }, 140, 310, 0},
+		// Akhir pekan 20 kWh, hari kerja 10 kWh; 15–31 Maret memuat 6 hari akhir pekan
+		{"weekly", func(_ int, day time.Time) float64 {
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				return 20
+			}
+			return 10
+		}, 180, 410, 0},
+		// Naik 0,5 kWh per hari: 1–14 Maret 17..23,5 kWh dan 15–31 Maret 24..32 kWh
+		{"trend", func(i int, _ time.Time) float64 { return 10 + 0.5*float64(i) }, 283.5, 759.5, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast, err := ForecastMonth(syntheticReadings(28, tt.energy), FlatTariff(1000), forecastAsOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if forecast.HistoryDays != 28 || forecast.DaysElapsed != 14 || forecast.DaysRemaining != 17 || len(forecast.Days) != 31 {
+				t.Fatalf("unexpected day counts: %+v", forecast)
+			}
+			if !approxEqual(forecast.ActualEnergy, tt.actual) || !approxEqual(forecast.Energy, tt.total) || !approxEqual(forecast.TrendPerDay, tt.trend) {
+				t.Fatalf("expected actual %v, total %v, trend %v; got %v, %v, %v", tt.actual, tt.total, tt.trend, forecast.ActualEnergy, forecast.Energy, forecast.TrendPerDay)
+			}
+			// Deret tanpa derau memberi interval selebar nol
+			if !approxEqual(forecast.EnergyLower, tt.total) || !approxEqual(forecast.EnergyUpper, tt.total) || !approxEqual(forecast.Bill, tt.total*1000) {
+				t.Fatalf("unexpected interval or bill: %+v", forecast)
+			}
+		})
+	}
+}
+
+func TestForecastMonth_ConfidenceInterval(t *testing.T) {
+	noisy := syntheticReadings(28, func(i int, _ time.Time) float64 {
+		if i%2 == 0 {
+			return 11
+		}
+		return 9
+	})
+
+	forecast, err := ForecastMonth(noisy, FlatTariff(1000), forecastAsOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(forecast.EnergyLower < forecast.Energy && forecast.Energy < forecast.EnergyUpper) {
+		t.Fatalf("expected a non-empty interval around %v, got [%v, %v]", forecast.Energy, forecast.EnergyLower, forecast.EnergyUpper)
+	}
+	if forecast.Energy < 300 || forecast.Energy > 320 || forecast.EnergyUpper-forecast.EnergyLower > 60 {
+		t.Fatalf("forecast drifted too far from 10 kWh/day: %+v", forecast)
+	}
+	if forecast.BillLower >= forecast.Bill || forecast.BillUpper <= forecast.Bill {
+		t.Fatalf("bill interval should follow the energy interval: %+v", forecast)
+	}
+
+	if _, err := ForecastMonth(noisy[len(noisy)-10:], FlatTariff(1000), forecastAsOf); !errors.Is(err, ErrInsufficientHistory) {
+		t.Fatalf("expected ErrInsufficientHistory, got %v", err)
+	}
+}
+
+func TestForecastMonth_PricesByHour(t *testing.T) {
+	// Seluruh konsumsi terjadi pukul 18:00 (WBP 2.000/kWh), 10 kWh per hari
+	readings := syntheticReadings(28, func(int, time.Time) float64 { return 10 })
+	for i := range readings {
+		readings[i].Timestamp = readings[i].Timestamp.Add(6 * time.Hour)
+	}
+	forecast, err := ForecastMonth(readings, peakTariff, forecastAsOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(forecast.ActualCost, 140*2000) || !approxEqual(forecast.Bill, 310*2000) {
+		t.Fatalf("expected peak pricing for actual and forecast, got %v / %v", forecast.ActualCost, forecast.Bill)
+	}
+
+	// Tarif block melanjutkan konsumsi bulan berjalan: 140 kWh aktual, lalu 60 kWh blok pertama dan 110 kWh blok kedua
+	block := TariffSchedule{{Code: "BLOCK", PricePerKWh: 1000, Model: entity.TariffModelBlock, Blocks: []entity.TariffBlock{{UpToKWh: 200, PricePerKWh: 1000}, {PricePerKWh: 2000}}}}
+	forecast, _ = ForecastMonth(readings, block, forecastAsOf)
+	if !approxEqual(forecast.ActualCost, 140000) || !approxEqual(forecast.Bill, 200000+110*2000) {
+		t.Fatalf("expected block pricing to continue from the actual consumption, got %v / %v", forecast.ActualCost, forecast.Bill)
+	}
+}
@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kolom tabel yang dihasilkan dari data interval Green Button (ESPI)
+const (
+	GreenButtonApplianceColumn = "Meter"
+	GreenButtonTimestampColumn = "Start"
+	GreenButtonEnergyColumn    = "Energy (Wh)"
+	GreenButtonDurationColumn  = "Duration (s)"
+)
+
+// espiUomWh adalah kode unit ESPI untuk watt-hour
+const espiUomWh = 72
+
+// greenButtonImporter membaca feed Atom Green Button: setiap IntervalReading menjadi satu baris,
+// dengan nama meter diambil dari judul entry UsagePoint
+type greenButtonImporter struct{}
+
+type espiReadingType struct {
+	Uom                  *int `xml:"uom"`
+	PowerOfTenMultiplier int  `xml:"powerOfTenMultiplier"`
+}
+
+type espiIntervalReading struct {
+	TimePeriod struct {
+		Duration int64 `xml:"duration"`
+		Start    int64 `xml:"start"`
+	} `xml:"timePeriod"`
+	Value int64 `xml:"value"`
+}
+
+func (greenButtonImporter) Read(r io.Reader) (*CSVFile, error) {
+	decoder := xml.NewDecoder(r)
+
+	meter := ""
+	entryTitle := ""
+	inEntry := false
+	readingType := espiReadingType{}
+	var intervals []espiIntervalReading
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if errors.Is(err, ErrFileTooLarge) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("error reading Green Button XML: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "entry":
+				inEntry, entryTitle = true, ""
+			case "title":
+				var title string
+				if err := decoder.DecodeElement(&title, &element); err != nil {
+					return nil, fmt.Errorf("error reading Green Button XML: %w", err)
+				}
+				if inEntry {
+					entryTitle = strings.TrimSpace(title)
+				}
+			case "UsagePoint":
+				if meter == "" {
+					meter = entryTitle
+				}
+			case "ReadingType":
+				if err := decoder.DecodeElement(&readingType, &element); err != nil {
+					return nil, fmt.Errorf("error reading Green Button ReadingType: %w", err)
+				}
+			case "IntervalReading":
+				var interval espiIntervalReading
+				if err := decoder.DecodeElement(&interval, &element); err != nil {
+					return nil, fmt.Errorf("error reading Green Button IntervalReading: %w", err)
+				}
+				intervals = append(intervals, interval)
+			}
+		case xml.EndElement:
+			if element.Name.Local == "entry" {
+				inEntry = false
+			}
+		}
+	}
+
+	if len(intervals) == 0 {
+		return nil, errors.New("green button file has no interval readings")
+	}
+	if readingType.Uom != nil && *readingType.Uom != espiUomWh {
+		return nil, fmt.Errorf("%w: green button unit of measure %d is not energy (Wh)", ErrUnsupportedFormat, *readingType.Uom)
+	}
+	if meter == "" {
+		meter = "Meter"
+	}
+
+	multiplier := math.Pow10(readingType.PowerOfTenMultiplier)
+	header := []string{GreenButtonApplianceColumn, GreenButtonTimestampColumn, GreenButtonEnergyColumn, GreenButtonDurationColumn}
+	records := make([][]string, 0, len(intervals))
+	lines := make([]int, 0, len(intervals))
+	for i, interval := range intervals {
+		start := time.Unix(interval.TimePeriod.Start, 0).In(jakarta)
+		records = append(records, []string{
+			meter,
+			start.Format(time.RFC3339),
+			strconv.FormatFloat(float64(interval.Value)*multiplier, 'f', -1, 64),
+			strconv.FormatInt(interval.TimePeriod.Duration, 10),
+		})
+		lines = append(lines, i+1)
+	}
+	return NewTableFile(header, records, lines)
+}
@@ -1,19 +1,13 @@
 package helper
 
 import (
-	"encoding/csv"
 	"errors"
 	"fmt"
-	"io"
-	"log"
 	"math/rand"
-	"net/http"
 	"net/smtp"
 	"os"
 	"regexp"
 	"sort"
-	"strconv"
-	"strings"
 	"time"
 	"unicode"
 
@@ -34,299 +28,136 @@ func StorageIsExist(path string) error {
 	return nil
 }
 
-func ReadCSV(fileURL string) (map[string][]string, error) {
-	// Unduh file dari URL
-	response, err := http.Get(fileURL)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching file from URL: %w", err)
+// SummarizeReadings menghitung penggunaan harian, mingguan (7 hari), dan bulanan (30 hari)
+// relatif terhadap hari reading terakhir
+func SummarizeReadings(readings []entity.Reading) entity.UsageSummary {
+	var summary entity.UsageSummary
+	if len(readings) == 0 {
+		return summary
 	}
-	defer response.Body.Close()
 
-	// Cek status HTTP
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch file: %s", response.Status)
+	for _, r := range readings {
+		if r.Timestamp.After(summary.LastReadingAt) {
+			summary.LastReadingAt = r.Timestamp
+		}
 	}
 
-	// Membaca CSV langsung dari response body
-	reader := csv.NewReader(response.Body)
-	// Read header first
-	header, err := reader.Read()
-	if err != nil {
-		return nil, fmt.Errorf("error reading CSV header: %w", err)
-	}
+	lastDay := startOfDay(summary.LastReadingAt)
+	weekStart := lastDay.AddDate(0, 0, -6)
+	monthStart := lastDay.AddDate(0, 0, -29)
 
-	if len(header) == 0 {
-		return nil, errors.New("csv header is empty")
-	}
+	days := make(map[time.Time]bool)
+	var totalHours float64
+	for _, r := range readings {
+		day := startOfDay(r.Timestamp)
+		days[day] = true
+		totalHours += r.Duration
 
-	result := make(map[string][]string)
-	for _, col := range header {
-		result[col] = []string{}
-	}
-
-	// Read remaining records one by one to be defensive against malformed rows
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
+		if day.Equal(lastDay) {
+			summary.DailyEnergy += r.Energy
+			summary.DailyHours += r.Duration
 		}
-		if err != nil {
-			// Log and continue to try to parse other rows
-			log.Printf("warning: error reading csv row: %v", err)
-			continue
+		if !day.Before(weekStart) {
+			summary.WeeklyEnergy += r.Energy
+			summary.WeeklyHours += r.Duration
 		}
-
-		for i := 0; i < len(header); i++ {
-			var val string
-			if i < len(record) {
-				val = record[i]
-			} else {
-				val = ""
-			}
-			result[header[i]] = append(result[header[i]], val)
+		if !day.Before(monthStart) {
+			summary.MonthlyEnergy += r.Energy
+			summary.MonthlyHours += r.Duration
 		}
 	}
 
-	return result, nil
-}
-
-func ParseCSVtoSliceOfStruct(fileURL string) ([]entity.ApplianceRequest, error) {
-	// Unduh file dari URL
-	response, err := http.Get(fileURL)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching file from URL: %w", err)
-	}
-	defer response.Body.Close()
+	summary.DaysRecorded = len(days)
+	summary.AverageHours = totalHours / float64(len(days))
 
-	// Cek status HTTP
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch file: %s", response.Status)
-	}
+	return summary
+}
 
-	// Membaca CSV langsung dari response body
-	reader := csv.NewReader(response.Body)
-	// Membaca header
-	header, err := reader.Read()
-	if err != nil {
-		return nil, fmt.Errorf("error reading csv header: %w", err)
-	}
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.In(jakarta).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+}
 
-	loweredHeader := make([]string, len(header))
-	for i, h := range header {
-		loweredHeader[i] = strings.ToLower(strings.TrimSpace(h))
-	}
+const (
+	BucketHour  = "hour"
+	BucketDay   = "day"
+	BucketMonth = "month"
+)
 
-	// Determine schema: simplified or detailed
-	// Simplified columns we expect: appliance (or device/name) and energy (or energy_consumption)
-	idxAppliance := -1
-	idxEnergy := -1
-	idxPower := -1
-	idxDuration := -1
-	idxCost := -1
-	idxType := -1
-	idxLocation := -1
-	idxStatus := -1
-	idxConnectivity := -1
-
-	for i, h := range loweredHeader {
-		switch {
-		case strings.Contains(h, "appliance") || strings.Contains(h, "device") || strings.Contains(h, "name"):
-			if idxAppliance == -1 {
-				idxAppliance = i
-			}
-		case strings.Contains(h, "energy") || strings.Contains(h, "energy_consumption") || strings.Contains(h, "kwh"):
-			if idxEnergy == -1 {
-				idxEnergy = i
-			}
-		case strings.Contains(h, "power"):
-			if idxPower == -1 {
-				idxPower = i
-			}
-		case strings.Contains(h, "duration") || strings.Contains(h, "usage"):
-			if idxDuration == -1 {
-				idxDuration = i
-			}
-		case strings.Contains(h, "cost") || strings.Contains(h, "price"):
-			if idxCost == -1 {
-				idxCost = i
-			}
-		case strings.Contains(h, "type"):
-			if idxType == -1 {
-				idxType = i
-			}
-		case strings.Contains(h, "location") || strings.Contains(h, "room"):
-			if idxLocation == -1 {
-				idxLocation = i
-			}
-		case strings.Contains(h, "status"):
-			if idxStatus == -1 {
-				idxStatus = i
-			}
-		case strings.Contains(h, "connect"):
-			if idxConnectivity == -1 {
-				idxConnectivity = i
-			}
-		}
+// BucketBounds mengembalikan awal dan akhir interval (zona waktu WIB) yang memuat waktu t
+func BucketBounds(t time.Time, bucket string) (time.Time, time.Time, error) {
+	local := t.In(jakarta)
+	switch bucket {
+	case BucketHour:
+		start := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, jakarta)
+		return start, start.Add(time.Hour), nil
+	case BucketDay:
+		start := startOfDay(local)
+		return start, start.AddDate(0, 0, 1), nil
+	case BucketMonth:
+		start := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, jakarta)
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid bucket %q, use hour, day or month", bucket)
 	}
+}
 
-	var appliances []entity.ApplianceRequest
-	deviceDurations := make(map[string][]float64)
-	seenNames := make(map[string]bool)
-
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Printf("warning: skipping csv row due to read error: %v", err)
-			continue
-		}
-
-		// retrieve values safely by index
-		get := func(idx int) string {
-			if idx >= 0 && idx < len(record) {
-				return strings.TrimSpace(record[idx])
-			}
-			return ""
-		}
-
-		deviceName := get(idxAppliance)
-		if deviceName == "" {
-			// fallback: if there is a Date,Time,Appliance simple schema, appliance may be at index 2
-			if len(record) > 2 {
-				deviceName = strings.TrimSpace(record[2])
-			}
-		}
-
-		// energy
-		var energy float64
-		if idxEnergy >= 0 {
-			energy, err = strconv.ParseFloat(get(idxEnergy), 64)
-			if err != nil {
-				log.Printf("warning: invalid energy for device %s: %v", deviceName, err)
-				continue
-			}
-		} else if len(record) > 3 {
-			// if simple schema: Date,Time,Appliance,Energy_Consumption,...
-			energy, err = strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
-			if err != nil {
-				log.Printf("warning: invalid energy (fallback) for device %s: %v", deviceName, err)
-				continue
-			}
-		}
-
-		// power
-		var powerInt int
-		if idxPower >= 0 {
-			powerInt, _ = strconv.Atoi(get(idxPower))
-		}
-
-		// duration
-		var duration float64
-		if idxDuration >= 0 {
-			duration, _ = strconv.ParseFloat(get(idxDuration), 64)
-		}
+// AggregateReadings menjumlahkan energi, durasi, dan biaya reading per bucket waktu, terurut dari yang terlama.
+// Biaya setiap reading dihitung dengan tarif yang berlaku pada timestamp reading tersebut; energi reading yang
+// tidak memiliki tarif berlaku dilaporkan terpisah di UnpricedEnergy karena biayanya tidak ikut terhitung.
+func AggregateReadings(readings []entity.Reading, bucket string, tariff TariffSchedule) (entity.UsageReport, error) {
+	return AggregatePricedReadings(PriceReadings(readings, tariff), bucket, tariff)
+}
 
-		// cost
-		var cost float64
-		if idxCost >= 0 {
-			cost, _ = strconv.ParseFloat(get(idxCost), 64)
+// AggregatePricedReadings sama dengan AggregateReadings untuk reading yang biayanya sudah dihitung
+func AggregatePricedReadings(readings []PricedReading, bucket string, tariff TariffSchedule) (entity.UsageReport, error) {
+	report := entity.UsageReport{Bucket: bucket, Buckets: []entity.UsageBucket{}}
+	if _, _, err := BucketBounds(time.Time{}, bucket); err != nil {
+		return report, err
+	}
+
+	var lastReadingAt time.Time
+	index := make(map[time.Time]int)
+	for _, reading := range readings {
+		start, end, _ := BucketBounds(reading.Timestamp, bucket)
+		i, ok := index[start]
+		if !ok {
+			i = len(report.Buckets)
+			index[start] = i
+			report.Buckets = append(report.Buckets, entity.UsageBucket{Start: start, End: end})
 		}
-
-		// other fields
-		typ := get(idxType)
-		loc := get(idxLocation)
-		status := get(idxStatus)
-		conn := get(idxConnectivity)
-
-		// If appliance name still empty, skip
-		if deviceName == "" {
-			log.Printf("warning: skipping row with empty appliance name: %v", record)
-			continue
+		report.Buckets[i].Energy += reading.Energy
+		report.Buckets[i].Hours += reading.Duration
+		report.Buckets[i].Cost += reading.Cost
+		if reading.Unpriced {
+			report.Buckets[i].UnpricedEnergy += reading.Energy
+			report.UnpricedReadings++
 		}
-
-		// For duration fallback set to 0 if missing
-		// priority: example rule power > 500
-		priority := powerInt > 500
-
-		// If appliance already seen, append duration/energy
-		if seenNames[deviceName] {
-			deviceDurations[deviceName] = append(deviceDurations[deviceName], duration)
-			continue
+		if reading.Timestamp.After(lastReadingAt) {
+			lastReadingAt = reading.Timestamp
 		}
+	}
 
-		seenNames[deviceName] = true
-
-		appliance := entity.ApplianceRequest{
-			Name:         deviceName,
-			Type:         typ,
-			Location:     loc,
-			Power:        powerInt,
-			Energy:       energy,
-			Cost:         cost,
-			Status:       status,
-			Connectivity: conn,
-			Priority:     priority,
-		}
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		return report.Buckets[i].Start.Before(report.Buckets[j].Start)
+	})
 
-		deviceDurations[deviceName] = append(deviceDurations[deviceName], duration)
-		appliances = append(appliances, appliance)
+	for _, b := range report.Buckets {
+		report.TotalEnergy += b.Energy
+		report.TotalCost += b.Cost
+		report.UnpricedEnergy += b.UnpricedEnergy
 	}
 
-	// Tambahkan AverageUsage untuk setiap appliance
-	for i := range appliances {
-		deviceName := appliances[i].Name
-		durations := deviceDurations[deviceName]
-		if len(durations) > 0 {
-			var totalDuration float64
-			for _, d := range durations {
-				totalDuration += d
-			}
-			averageUsage := totalDuration / float64(len(durations))
-			appliances[i].UsageToday = totalDuration
-			appliances[i].AverageUsage = averageUsage
-		}
+	// Tarif pada report adalah harga yang berlaku pada reading terakhir (atau saat ini jika tidak ada reading)
+	if lastReadingAt.IsZero() {
+		lastReadingAt = time.Now()
 	}
-
-	return appliances, nil
-}
-
-func GetTarif(golongan string) float64 {
-	switch golongan {
-	case "Subsidi daya 450 VA":
-		return 415.00
-	case "Subsidi daya 900 VA":
-		return 605.00
-	case "R-1/TR daya 900 VA":
-		return 1352.00
-	case "R-1/TR daya 1300 VA":
-		return 1444.70
-	case "R-1/TR daya 2200 VA":
-		return 1444.70
-	case "R-2/TR daya 3500 VA - 5500 VA":
-		return 1699.53
-	case "R-3/TR daya 6600 VA ke atas":
-		return 1699.53
-	case "B-2/TR daya 6600 VA - 200 kVA":
-		return 1444.70
-	case "B-3/TM daya di atas 200 kVA":
-		return 1114.74
-	case "I-3/TM daya di atas 200 kVA":
-		return 1114.74
-	case "I-4/TT daya 30.000 kVA ke atas":
-		return 996.74
-	case "P-1/TR daya 6600 VA - 200 kVA":
-		return 1699.53
-	case "P-2/TM daya di atas 200 kVA":
-		return 1522.88
-	case "P-3/TR penerangan jalan umum":
-		return 1699.53
-	case "L/TR", "L/TM", "L/TT":
-		return 1644.00
-	default:
-		return -1
+	report.Tarif = tariff.RateAt(lastReadingAt)
+	if len(tariff) > 0 {
+		report.TariffCode = tariff[0].Code
 	}
+
+	return report, nil
 }
 
 func JumlahHariDalamBulan(tanggal string) (int, error) {
@@ -348,90 +179,35 @@ func JumlahHariDalamBulan(tanggal string) (int, error) {
 	return hariTerakhir.Day(), nil
 }
 
-func PrintRecommendationsMonthlyUsage(appliances []entity.ApplianceResponse, tarif float64, daysInMonth int, maxEnergy float64) []string {
-	timeSlots := []string{"00:00–06:00", "06:00–12:00", "12:00–18:00", "18:00–24:00"}
-
-	// Hitung penggunaan bulanan dan biaya untuk setiap perangkat
-	for i := range appliances {
-		appliances[i].MonthlyUse = appliances[i].AverageUsage * float64(daysInMonth)
-		appliances[i].Cost = appliances[i].MonthlyUse * tarif
-	}
-
-	// Urutkan perangkat berdasarkan prioritas (true > false) lalu penggunaan daya (desc)
-	sort.Slice(appliances, func(i, j int) bool {
-		if appliances[i].Priority == appliances[j].Priority {
-			return appliances[i].AverageUsage > appliances[j].AverageUsage
-		}
-		return appliances[i].Priority
-	})
-
-	allocatedEnergy := 0.0
-	selectedAppliances := []entity.ApplianceResponse{}
-
-	// Alokasikan perangkat ke jadwal
-	for _, appliance := range appliances {
-		if allocatedEnergy+appliance.MonthlyUse <= maxEnergy {
-			allocatedEnergy += appliance.MonthlyUse
-			appliance.RecommendedSchedule = RecommendationsMonthlyUsage(appliance, timeSlots)
-			selectedAppliances = append(selectedAppliances, appliance)
-		}
-	}
-
-	// Buat hasil output
+// PrintRecommendationsMonthlyUsage menampilkan hasil OptimizeMonthlyBudget sebagai teks rekomendasi
+func PrintRecommendationsMonthlyUsage(plan entity.BudgetPlan) []string {
 	result := []string{}
-	result = append(result, fmt.Sprintf("Jadwal Penggunaan Appliances (Total Energi = %.2f kWh, Biaya = Rp%.2f):", allocatedEnergy, allocatedEnergy*tarif))
-	for _, appliance := range selectedAppliances {
-		result = append(result, fmt.Sprintf("Name: %s, Type: %s, Priority: %t, Monthly Use: %.2f kWh, Cost: Rp%.2f, Schedule: %v",
-			appliance.Name, appliance.Type, appliance.Priority, appliance.MonthlyUse, appliance.Cost, appliance.RecommendedSchedule))
-	}
-
-	return result
-}
-
-func RecommendationsMonthlyUsage(appliance entity.ApplianceResponse, timeSlots []string) []string {
-	schedule := []string{}
-
-	// Prioritaskan jadwal berdasarkan daya perangkat
-	if appliance.AverageUsage >= 1.0 { // Perangkat daya besar (>= 1 kWh rata-rata)
-		for _, slot := range []string{"18:00–24:00", "00:00–06:00"} {
-			schedule = append(schedule, slot)
-		}
-	} else { // Perangkat daya kecil (< 1 kWh rata-rata)
-		for _, slot := range []string{"06:00–12:00", "12:00–18:00"} {
-			schedule = append(schedule, slot)
+	result = append(result, fmt.Sprintf("Jadwal Penggunaan Appliances (Total Energi = %.2f kWh, Biaya = Rp%.2f):", plan.AllocatedKWh, plan.AllocatedCost))
+	for _, allocation := range plan.Allocations {
+		if allocation.RecommendedHours <= 0 {
+			continue
 		}
+		result = append(result, fmt.Sprintf("Name: %s, Type: %s, Priority: %t, Monthly Use: %.2f kWh, Cost: Rp%.2f, Schedule: %v, Hours/Day: %.2f, Reason: %s",
+			allocation.Name, allocation.Type, allocation.Priority, allocation.MonthlyKWh, allocation.MonthlyCost, runLabels(allocation.Schedule), allocation.RecommendedHours, allocation.Message))
 	}
 
-	return schedule
+	return result
 }
 
+// PrintRecommendationsDailyUsage menampilkan status target harian (daily_target) dalam format lama
 func PrintRecommendationsDailyUsage(appliances []entity.ApplianceResponse, tariff float64) []DailySummary {
 	var summary []DailySummary
 	for _, appliance := range appliances {
-		// Hitung energi yang dikonsumsi saat ini
-		currentEnergy := float64(appliance.Power) * appliance.UsageToday / 1000.0 // kWh
-		// Biaya penggunaan saat ini
-		currentCost := currentEnergy * tariff
-
-		// Periksa apakah penggunaan melebihi target harian
-		var applianceSummary DailySummary
-		if appliance.UsageToday > appliance.DailyUseTarget {
-			applianceSummary.ApplianceName = appliance.Name
-			applianceSummary.Type = appliance.Type
-			applianceSummary.Message = fmt.Sprintf("WARNING: %s telah melebihi target harian!", appliance.Name)
-			applianceSummary.IsOveruse = true
-		} else {
-			applianceSummary.ApplianceName = appliance.Name
-			applianceSummary.Type = appliance.Type
-			applianceSummary.Message = fmt.Sprintf("%s dalam batas target harian. (Penggunaan: %.2f jam, Target: %.2f jam)", appliance.Name, appliance.UsageToday, appliance.DailyUseTarget)
-			applianceSummary.IsOveruse = false
-		}
-
-		// Cetak informasi biaya
-		applianceSummary.Usage = appliance.UsageToday
-		applianceSummary.Target = appliance.DailyUseTarget
-		applianceSummary.Info = fmt.Sprintf("Biaya saat ini untuk %s: IDR %.2f", appliance.Name, currentCost)
-		summary = append(summary, applianceSummary)
+		recommendation := dailyTargetRecommendation(appliance, tariff)
+		summary = append(summary, DailySummary{
+			ApplianceName: appliance.Name,
+			Type:          appliance.Type,
+			Message:       RenderRecommendation(recommendation, LanguageID),
+			Info:          fmt.Sprintf("Biaya saat ini untuk %s: IDR %.2f", appliance.Name, recommendation.Params["cost"]),
+			IsOveruse:     recommendation.Severity == entity.SeverityCritical,
+			Usage:         appliance.UsageToday,
+			Target:        appliance.DailyUseTarget,
+		})
 	}
 
 	return summary
@@ -501,13 +277,15 @@ func ConvertToResponseType(data interface{}) interface{} {
 	}
 }
 
-func GenerateToken(username string, email string, premium bool) (string, error) {
+func GenerateToken(id uint, username string, email string, premium bool, admin bool) (string, error) {
 	secretKey := os.Getenv("JWT_SECRET")
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := jwt.MapClaims{
+		"id":       id,
 		"username": username,
 		"email":    email,
 		"premium":  premium,
+		"admin":    admin,
 		"exp":      expirationTime.Unix(),
 	}
 
@@ -547,6 +325,31 @@ func VerifyToken(jwtToken string) (interface{}, error) {
 	return claims, nil
 }
 
+// GetUserID returns the user ID carried in the claims that AuthMiddleware stores as "user_data".
+func GetUserID(userData interface{}) (uint, error) {
+	claims, ok := userData.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid user data")
+	}
+
+	id, ok := claims["id"].(float64)
+	if !ok || id < 1 {
+		return 0, errors.New("user id not found in token")
+	}
+
+	return uint(id), nil
+}
+
+// IsAdmin bernilai true jika claims pada "user_data" menandai user sebagai admin
+func IsAdmin(userData interface{}) bool {
+	claims, ok := userData.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	admin, _ := claims["admin"].(bool)
+	return admin
+}
+
 // SafeVerifyToken calls VerifyToken and recovers from any panic, returning an error instead.
 func SafeVerifyToken(jwtToken string) (data interface{}, err error) {
 	defer func() {
@@ -1,9 +1,11 @@
 package helper
 
 import (
-	"net/http"
-	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
 )
 
 // simple CSV sample: Date,Time,Appliance,Energy_Consumption,Room,Status
@@ -19,16 +21,12 @@ const detailedCSV = `timestamp,device,type,location,power,duration,usage_kwh,cos
 2022-01-01T01:00:00Z,TV,Entertainment,Living Room,50,0.5,0.3,0.12,Off,IR
 `
 
-func TestParseCSVtoSliceOfStruct_SimpleSchema(t *testing.T) {
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(simpleCSV))
-	}))
-	defer srv.Close()
-
-	apps, err := ParseCSVtoSliceOfStruct(srv.URL)
+func TestParseCSV_SimpleSchema(t *testing.T) {
+	parsed, err := ParseCSV(strings.NewReader(simpleCSV))
 	if err != nil {
-		t.Fatalf("ParseCSVtoSliceOfStruct simple schema returned error: %v", err)
+		t.Fatalf("ParseCSV simple schema returned error: %v", err)
 	}
+	apps := parsed.Appliances
 
 	if len(apps) == 0 {
 		t.Fatalf("expected at least one appliance parsed from simple csv, got 0")
@@ -51,16 +49,12 @@ func TestParseCSVtoSliceOfStruct_SimpleSchema(t *testing.T) {
 	}
 }
 
-func TestParseCSVtoSliceOfStruct_DetailedSchema(t *testing.T) {
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(detailedCSV))
-	}))
-	defer srv.Close()
-
-	apps, err := ParseCSVtoSliceOfStruct(srv.URL)
+func TestParseCSV_DetailedSchema(t *testing.T) {
+	parsed, err := ParseCSV(strings.NewReader(detailedCSV))
 	if err != nil {
-		t.Fatalf("ParseCSVtoSliceOfStruct detailed schema returned error: %v", err)
+		t.Fatalf("ParseCSV detailed schema returned error: %v", err)
 	}
+	apps := parsed.Appliances
 
 	if len(apps) != 2 {
 		t.Fatalf("expected 2 appliances parsed from detailed csv, got %d", len(apps))
@@ -77,3 +71,148 @@ func TestParseCSVtoSliceOfStruct_DetailedSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCSV_KeepsEveryRow(t *testing.T) {
+	parsed, err := ParseCSV(strings.NewReader(simpleCSV))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+	apps, readings := parsed.Appliances, parsed.Readings
+
+	if len(apps) != 2 {
+		t.Fatalf("expected 2 appliances, got %d", len(apps))
+	}
+	if len(readings) != 3 {
+		t.Fatalf("expected one reading per csv row (3), got %d", len(readings))
+	}
+
+	for _, a := range apps {
+		if a.Name == "Refrigerator" && a.Energy != 1.2+1.1 {
+			t.Fatalf("expected Refrigerator energy to sum the last day's readings (2.3), got %v", a.Energy)
+		}
+	}
+
+	if got := readings[1].Timestamp.Hour(); got != 1 {
+		t.Fatalf("expected second reading at 01:00, got hour %d", got)
+	}
+}
+
+func TestSummarizeReadings_DailyWeeklyMonthly(t *testing.T) {
+	last := time.Date(2024, 3, 31, 20, 0, 0, 0, jakarta)
+
+	var readings []entity.Reading
+	// 40 hari berturut-turut, masing-masing 2 jam dan 1 kWh
+	for i := 0; i < 40; i++ {
+		readings = append(readings, entity.Reading{
+			Timestamp: last.AddDate(0, 0, -i),
+			Energy:    1,
+			Duration:  2,
+		})
+	}
+
+	summary := SummarizeReadings(readings)
+
+	if !summary.LastReadingAt.Equal(last) {
+		t.Fatalf("expected last reading %v, got %v", last, summary.LastReadingAt)
+	}
+	if summary.DailyEnergy != 1 || summary.DailyHours != 2 {
+		t.Fatalf("unexpected daily figures: %+v", summary)
+	}
+	if summary.WeeklyEnergy != 7 || summary.MonthlyEnergy != 30 {
+		t.Fatalf("unexpected weekly/monthly energy: %+v", summary)
+	}
+	if summary.DaysRecorded != 40 || summary.AverageHours != 2 {
+		t.Fatalf("unexpected averages: %+v", summary)
+	}
+}
+
+func TestAggregateReadings_Buckets(t *testing.T) {
+	day := time.Date(2024, 1, 31, 0, 0, 0, 0, jakarta)
+	readings := []entity.Reading{
+		{Timestamp: day.Add(23 * time.Hour), Energy: 2, Duration: 1},
+		{Timestamp: day.Add(1 * time.Hour), Energy: 1, Duration: 1},
+		{Timestamp: day.Add(1*time.Hour + 30*time.Minute), Energy: 0.5, Duration: 0.5},
+		{Timestamp: day.AddDate(0, 0, 1), Energy: 4, Duration: 2},
+	}
+
+	hourly, err := AggregateReadings(readings, BucketHour, FlatTariff(1000))
+	if err != nil {
+		t.Fatalf("AggregateReadings returned error: %v", err)
+	}
+	if len(hourly.Buckets) != 3 {
+		t.Fatalf("expected 3 hourly buckets, got %d", len(hourly.Buckets))
+	}
+	if first := hourly.Buckets[0]; first.Energy != 1.5 || first.Cost != 1500 || first.Hours != 1.5 {
+		t.Fatalf("unexpected first hourly bucket: %+v", first)
+	}
+
+	monthly, err := AggregateReadings(readings, BucketMonth, FlatTariff(1000))
+	if err != nil {
+		t.Fatalf("AggregateReadings returned error: %v", err)
+	}
+	if len(monthly.Buckets) != 2 {
+		t.Fatalf("expected January and February buckets, got %d", len(monthly.Buckets))
+	}
+	if monthly.TotalEnergy != 7.5 || monthly.TotalCost != 7500 {
+		t.Fatalf("unexpected totals: energy=%v cost=%v", monthly.TotalEnergy, monthly.TotalCost)
+	}
+
+	if _, err := AggregateReadings(readings, "week", FlatTariff(1000)); err == nil {
+		t.Fatalf("expected error for unsupported bucket")
+	}
+}
+
+func TestAggregateReadings_HistoricalTariff(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, jakarta)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, jakarta)
+	tariff := NewTariffSchedule([]entity.TariffResponse{
+		{Code: "R-1/1300", PricePerKWh: 1500, EffectiveFrom: april},
+		{Code: "R-1/1300", PricePerKWh: 1000, EffectiveFrom: march},
+	})
+	readings := []entity.Reading{
+		{Timestamp: march.AddDate(0, 0, -1), Energy: 1},
+		{Timestamp: march.Add(time.Hour), Energy: 2},
+		{Timestamp: april.Add(time.Hour), Energy: 2},
+	}
+
+	report, err := AggregateReadings(readings, BucketMonth, tariff)
+	if err != nil {
+		t.Fatalf("AggregateReadings returned error: %v", err)
+	}
+	// Februari jatuh sebelum versi pertama sehingga tidak memiliki harga
+	if report.Buckets[0].Cost != 0 || report.Buckets[0].UnpricedEnergy != 1 {
+		t.Fatalf("expected February to be unpriced, got %+v", report.Buckets[0])
+	}
+	if report.Buckets[1].Cost != 2000 || report.Buckets[2].Cost != 3000 {
+		t.Fatalf("unexpected bucket costs: %+v", report.Buckets)
+	}
+	if report.UnpricedEnergy != 1 || report.UnpricedReadings != 1 {
+		t.Fatalf("expected one unpriced reading, got %v kWh in %d readings", report.UnpricedEnergy, report.UnpricedReadings)
+	}
+	if report.Tarif != 1500 || report.TariffCode != "R-1/1300" {
+		t.Fatalf("expected latest rate 1500 for R-1/1300, got %v %q", report.Tarif, report.TariffCode)
+	}
+}
+
+func TestAggregateReadings_FlagsReadingsWithoutTariff(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, jakarta)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, jakarta)
+	tariff := NewTariffSchedule([]entity.TariffResponse{
+		{Code: "R-1/1300", PricePerKWh: 1000, EffectiveFrom: march, EffectiveTo: &april},
+	})
+	readings := []entity.Reading{
+		{Timestamp: march.Add(time.Hour), Energy: 2},
+		{Timestamp: april.Add(time.Hour), Energy: 3},
+	}
+
+	report, err := AggregateReadings(readings, BucketMonth, tariff)
+	if err != nil {
+		t.Fatalf("AggregateReadings returned error: %v", err)
+	}
+	if report.TotalCost != 2000 || report.UnpricedEnergy != 3 || report.UnpricedReadings != 1 {
+		t.Fatalf("expected April reading flagged as unpriced, got %+v", report)
+	}
+	if report.Buckets[0].UnpricedEnergy != 0 || report.Buckets[1].UnpricedEnergy != 3 {
+		t.Fatalf("unexpected unpriced energy per bucket: %+v", report.Buckets)
+	}
+}
@@ -0,0 +1,269 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Format file yang dapat di-import
+const (
+	FormatCSV         = "csv"
+	FormatJSON        = "json"
+	FormatXLSX        = "xlsx"
+	FormatGreenButton = "greenbutton"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported import file format")
+
+// Importer membaca satu format file menjadi tabel header dan baris, sehingga seluruh format
+// dipetakan ke appliance dan reading dengan profil mapping kolom yang sama
+type Importer interface {
+	Read(r io.Reader) (*CSVFile, error)
+}
+
+var importers = map[string]Importer{
+	FormatCSV:         csvImporter{},
+	FormatJSON:        jsonImporter{},
+	FormatXLSX:        xlsxImporter{},
+	FormatGreenButton: greenButtonImporter{},
+}
+
+var contentTypeFormats = map[string]string{
+	"text/csv":             FormatCSV,
+	"application/csv":      FormatCSV,
+	"application/json":     FormatJSON,
+	"text/json":            FormatJSON,
+	"application/xml":      FormatGreenButton,
+	"text/xml":             FormatGreenButton,
+	"application/atom+xml": FormatGreenButton,
+	"application/espi+xml": FormatGreenButton,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": FormatXLSX,
+}
+
+var extensionFormats = map[string]string{
+	".csv":  FormatCSV,
+	".txt":  FormatCSV,
+	".json": FormatJSON,
+	".xlsx": FormatXLSX,
+	".xml":  FormatGreenButton,
+}
+
+// ContentTyper diimplementasikan oleh sumber file yang mengetahui Content-Type aslinya (mis. body hasil Fetch)
+type ContentTyper interface {
+	ContentType() string
+}
+
+// DetectFormat memilih format dari ekstensi nama file atau path URL, lalu dari Content-Type;
+// string kosong jika tidak dikenali. Ekstensi didahulukan karena browser sering mengirim
+// Content-Type generik (mis. CSV sebagai application/vnd.ms-excel).
+func DetectFormat(contentType, filename string) string {
+	if u, err := url.Parse(filename); err == nil && u.Scheme != "" {
+		filename = u.Path
+	}
+	if format, ok := extensionFormats[strings.ToLower(path.Ext(filename))]; ok {
+		return format
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return contentTypeFormats[mediaType]
+	}
+	return ""
+}
+
+// ImporterFor mengembalikan importer untuk format yang diberikan
+func ImporterFor(format string) (Importer, error) {
+	importer, ok := importers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return importer, nil
+}
+
+// ReadUpload membatasi ukuran lalu membaca file upload satu kali dengan importer yang sesuai.
+// filename boleh berupa URL sumber; Content-Type kosong diambil dari source jika tersedia.
+// Jika format tidak dapat ditentukan dari Content-Type atau ekstensi, format ditebak dari isi file.
+func ReadUpload(source io.Reader, contentType, filename string) (*CSVFile, error) {
+	if typed, ok := source.(ContentTyper); ok && contentType == "" {
+		contentType = typed.ContentType()
+	}
+	source = LimitSize(source, MaxUploadSize)
+
+	format := DetectFormat(contentType, filename)
+	if format == "" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(source, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, err
+		}
+		head = head[:n]
+		format = sniffFormat(head)
+		source = io.MultiReader(bytes.NewReader(head), source)
+	}
+
+	importer, err := ImporterFor(format)
+	if err != nil {
+		return nil, err
+	}
+	return importer.Read(source)
+}
+
+// sniffFormat menebak format dari byte pertama file
+func sniffFormat(head []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return FormatXLSX
+	case bytes.HasPrefix(trimmed, []byte("[")), bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatJSON
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatGreenButton
+	default:
+		return FormatCSV
+	}
+}
+
+// NewTableFile menyusun CSVFile dari header dan baris yang dibaca oleh importer non-CSV
+func NewTableFile(header []string, records [][]string, lines []int) (*CSVFile, error) {
+	if len(header) == 0 {
+		return nil, errors.New("file header is empty")
+	}
+
+	table := make(map[string][]string, len(header))
+	for _, col := range header {
+		table[col] = []string{}
+	}
+	for _, record := range records {
+		for i, col := range header {
+			var val string
+			if i < len(record) {
+				val = record[i]
+			}
+			table[col] = append(table[col], val)
+		}
+	}
+	return &CSVFile{Header: header, Records: records, Lines: lines, Table: table}, nil
+}
+
+type csvImporter struct{}
+
+func (csvImporter) Read(r io.Reader) (*CSVFile, error) {
+	sniffed, err := SniffCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	return ReadCSVFile(sniffed)
+}
+
+// jsonImporter membaca array objek JSON ({"data": [...]} juga diterima); kunci objek menjadi kolom
+// sesuai urutan kemunculannya dan nomor baris adalah urutan objek di array
+type jsonImporter struct{}
+
+func (jsonImporter) Read(r io.Reader) (*CSVFile, error) {
+	var payload json.RawMessage
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("error reading JSON: %w", err)
+	}
+
+	payload = bytes.TrimSpace(payload)
+	if bytes.HasPrefix(payload, []byte("{")) {
+		var wrapper struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &wrapper); err != nil || wrapper.Data == nil {
+			return nil, errors.New("json must be an array of objects or an object with a data array")
+		}
+		payload = wrapper.Data
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(payload, &items); err != nil {
+		return nil, errors.New("json must be an array of objects or an object with a data array")
+	}
+
+	var header []string
+	columns := make(map[string]int)
+	var rows []map[string]string
+	for i, item := range items {
+		keys, values, err := decodeJSONObject(item)
+		if err != nil {
+			return nil, fmt.Errorf("json item %d: %w", i+1, err)
+		}
+		for _, key := range keys {
+			if _, ok := columns[key]; !ok {
+				columns[key] = len(header)
+				header = append(header, key)
+			}
+		}
+		rows = append(rows, values)
+	}
+
+	records := make([][]string, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	for i, values := range rows {
+		record := make([]string, len(header))
+		for key, value := range values {
+			record[columns[key]] = value
+		}
+		records = append(records, record)
+		lines = append(lines, i+1)
+	}
+	return NewTableFile(header, records, lines)
+}
+
+// decodeJSONObject membaca satu objek datar dengan mempertahankan urutan kuncinya
+func decodeJSONObject(raw json.RawMessage) ([]string, map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil, errors.New("item is not an object")
+	}
+
+	var keys []string
+	values := make(map[string]string)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := token.(string)
+
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values[key] = jsonValueString(value)
+	}
+	return keys, values, nil
+}
+
+func jsonValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		// Nilai bersarang disimpan apa adanya sebagai JSON
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
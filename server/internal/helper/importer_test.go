@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		contentType, filename, want string
+	}{
+		{"application/vnd.ms-excel", "usage.csv", FormatCSV},
+		{"application/octet-stream", "usage.XLSX", FormatXLSX},
+		{"application/json; charset=utf-8", "", FormatJSON},
+		{"", "https://utility.example/export.xml?token=1", FormatGreenButton},
+		{"application/octet-stream", "export", ""},
+	}
+	for _, c := range cases {
+		if got := DetectFormat(c.contentType, c.filename); got != c.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", c.contentType, c.filename, got, c.want)
+		}
+	}
+}
+
+func TestReadUpload_JSON(t *testing.T) {
+	input := `{"data": [
+		{"Appliance": "Fridge", "Date": "2024-01-01", "Time": "00:00", "Energy_Consumption": 0.5},
+		{"Appliance": "TV", "Date": "2024-01-01", "Time": "01:00", "Energy_Consumption": 0.2, "Room": "Living"}
+	]}`
+
+	file, err := ReadUpload(strings.NewReader(input), "", "")
+	if err != nil {
+		t.Fatalf("ReadUpload returned error: %v", err)
+	}
+	if strings.Join(file.Header, ",") != "Appliance,Date,Time,Energy_Consumption,Room" {
+		t.Fatalf("unexpected header: %v", file.Header)
+	}
+
+	parsed, err := file.Parse(DetectMapping(file.Header))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.Appliances) != 2 || len(parsed.Readings) != 2 || parsed.Appliances[1].Location != "Living" {
+		t.Fatalf("unexpected parse result: %+v", parsed.Appliances)
+	}
+}
+
+func TestReadUpload_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Usage" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Target="worksheets/usage.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Device Name</t></si><si><t>Time</t></si><si><t>Electricity(kWh)</t></si><si><r><t>Air </t></r><r><t>Conditioner</t></r></si></sst>`,
+		"xl/styles.xml":        `<styleSheet><numFmts><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd hh:mm:ss"/></numFmts><cellXfs><xf numFmtId="0"/><xf numFmtId="164"/></cellXfs></styleSheet>`,
+		"xl/worksheets/usage.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2" s="1"><v>45292.5</v></c><c r="C2"><v>1.25</v></c></row>
+			<row r="4"><c r="A4" t="inlineStr"><is><t>Fan</t></is></c><c r="C4"><v>0.1</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		w, _ := archive.Create(name)
+		w.Write([]byte(content))
+	}
+	archive.Close()
+
+	file, err := ReadUpload(bytes.NewReader(buf.Bytes()), "", "usage.xlsx")
+	if err != nil {
+		t.Fatalf("ReadUpload returned error: %v", err)
+	}
+	if got := file.Table["Time"]; len(got) != 2 || got[0] != "2024-01-01 12:00:00" {
+		t.Fatalf("unexpected time column: %v", got)
+	}
+	if file.Lines[1] != 4 || file.Records[0][0] != "Air Conditioner" {
+		t.Fatalf("unexpected records: %v lines %v", file.Records, file.Lines)
+	}
+
+	parsed, err := file.Parse(entity.ColumnMapping{
+		ApplianceColumn: "Device Name",
+		TimestampColumn: "Time",
+		TimestampFormat: "YYYY-MM-DD HH:mm:ss",
+		EnergyColumn:    "Electricity(kWh)",
+	})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.Readings) != 1 || parsed.Readings[0].Energy != 1.25 {
+		t.Fatalf("expected one reading from the dated row, got %+v", parsed.Readings)
+	}
+}
+
+func TestReadUpload_GreenButton(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:espi="http://naesb.org/espi">
+  <entry><title>Rumah Utama</title><content><espi:UsagePoint/></content></entry>
+  <entry><title>Energy</title><content><espi:ReadingType><espi:uom>72</espi:uom><espi:powerOfTenMultiplier>0</espi:powerOfTenMultiplier></espi:ReadingType></content></entry>
+  <entry><content><espi:IntervalBlock>
+    <espi:IntervalReading><espi:timePeriod><espi:duration>3600</espi:duration><espi:start>1704067200</espi:start></espi:timePeriod><espi:value>450</espi:value></espi:IntervalReading>
+    <espi:IntervalReading><espi:timePeriod><espi:duration>3600</espi:duration><espi:start>1704070800</espi:start></espi:timePeriod><espi:value>300</espi:value></espi:IntervalReading>
+  </espi:IntervalBlock></content></entry>
+</feed>`
+
+	file, err := ReadUpload(strings.NewReader(input), "application/atom+xml", "")
+	if err != nil {
+		t.Fatalf("ReadUpload returned error: %v", err)
+	}
+
+	profile := SuggestMappingProfile(file.Header, BuiltinMappingProfiles())
+	if profile.Name != "Green Button" {
+		t.Fatalf("expected Green Button profile, got %q", profile.Name)
+	}
+	parsed, err := file.Parse(profile.ColumnMapping)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.Readings) != 2 || parsed.Readings[0].ApplianceName != "Rumah Utama" {
+		t.Fatalf("unexpected readings: %+v", parsed.Readings)
+	}
+	if parsed.Readings[0].Energy != 0.45 || parsed.Readings[0].Duration != 1 {
+		t.Fatalf("expected 0.45 kWh over 1 hour, got %+v", parsed.Readings[0])
+	}
+	if !parsed.Readings[0].Timestamp.Equal(parsed.Readings[1].Timestamp.Add(-time.Hour)) {
+		t.Fatalf("unexpected timestamps: %v %v", parsed.Readings[0].Timestamp, parsed.Readings[1].Timestamp)
+	}
+}
+
+func TestReadUpload_XLSXRejectsOversizedCellRef(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, _ := archive.Create("xl/worksheets/sheet1.xml")
+	w.Write([]byte(`<worksheet><sheetData>
+		<row r="1"><c r="A1" t="inlineStr"><is><t>Appliance</t></is></c></row>
+		<row r="2"><c r="ZZZZZZZZZ2"><v>1</v></c></row>
+	</sheetData></worksheet>`))
+	archive.Close()
+
+	if _, err := ReadUpload(bytes.NewReader(buf.Bytes()), "", "usage.xlsx"); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Fatalf("expected ErrInvalidWorkbook, got %v", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	cases := map[string]int{"A1": 0, "C12": 2, "AA3": 26, "XFD1": 16383}
+	for ref, want := range cases {
+		got, err := columnIndex(ref)
+		if err != nil || got != want {
+			t.Errorf("columnIndex(%q) = %d, %v; want %d", ref, got, err, want)
+		}
+	}
+	for _, ref := range []string{"XFE1", "AAAA1", "12", ""} {
+		if _, err := columnIndex(ref); !errors.Is(err, ErrInvalidWorkbook) {
+			t.Errorf("columnIndex(%q) expected ErrInvalidWorkbook, got %v", ref, err)
+		}
+	}
+}
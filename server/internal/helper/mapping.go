@@ -0,0 +1,314 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// AutoMappingProfile adalah nama profil untuk deteksi kolom otomatis dari nama header
+const AutoMappingProfile = "auto"
+
+const (
+	UnitKWh    = "kWh"
+	UnitWh     = "Wh"
+	UnitW      = "W"
+	UnitKW     = "kW"
+	UnitHour   = "h"
+	UnitMinute = "min"
+	UnitSecond = "s"
+)
+
+// BuiltinMappingProfiles adalah profil bawaan untuk format ekspor yang umum dipakai user
+func BuiltinMappingProfiles() []entity.MappingProfileResponse {
+	return []entity.MappingProfileResponse{
+		{
+			Name:        "Kaggle smart home",
+			Description: "Kaggle Smart Home Energy Consumption dataset",
+			BuiltIn:     true,
+			ColumnMapping: entity.ColumnMapping{
+				ApplianceColumn: "Appliance Type",
+				DateColumn:      "Date",
+				TimeColumn:      "Time",
+				TimestampFormat: "YYYY-MM-DD HH:mm",
+				EnergyColumn:    "Energy Consumption (kWh)",
+				EnergyUnit:      UnitKWh,
+			},
+		},
+		{
+			Name:        "Tuya export",
+			Description: "Tuya / Smart Life smart plug statistics export",
+			BuiltIn:     true,
+			ColumnMapping: entity.ColumnMapping{
+				ApplianceColumn: "Device Name",
+				TimestampColumn: "Time",
+				TimestampFormat: "YYYY-MM-DD HH:mm:ss",
+				EnergyColumn:    "Electricity(kWh)",
+				EnergyUnit:      UnitKWh,
+				PowerColumn:     "Power(W)",
+				PowerUnit:       UnitW,
+			},
+		},
+		{
+			Name:        "KSE simple",
+			Description: "Date,Time,Appliance,Energy_Consumption,Room,Status",
+			BuiltIn:     true,
+			ColumnMapping: entity.ColumnMapping{
+				ApplianceColumn: "Appliance",
+				DateColumn:      "Date",
+				TimeColumn:      "Time",
+				EnergyColumn:    "Energy_Consumption",
+				EnergyUnit:      UnitKWh,
+				LocationColumn:  "Room",
+				StatusColumn:    "Status",
+			},
+		},
+		{
+			Name:        "Green Button",
+			Description: "Green Button / ESPI interval data (XML)",
+			BuiltIn:     true,
+			ColumnMapping: entity.ColumnMapping{
+				ApplianceColumn: GreenButtonApplianceColumn,
+				TimestampColumn: GreenButtonTimestampColumn,
+				EnergyColumn:    GreenButtonEnergyColumn,
+				EnergyUnit:      UnitWh,
+				DurationColumn:  GreenButtonDurationColumn,
+				DurationUnit:    UnitSecond,
+			},
+		},
+	}
+}
+
+// DetectMapping menebak kolom dari kata kunci pada nama header (perilaku parser lama)
+func DetectMapping(header []string) entity.ColumnMapping {
+	var mapping entity.ColumnMapping
+
+	set := func(field *string, column string) {
+		if *field == "" {
+			*field = column
+		}
+	}
+
+	for _, column := range header {
+		h := strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case strings.Contains(h, "appliance") || strings.Contains(h, "device") || strings.Contains(h, "name"):
+			set(&mapping.ApplianceColumn, column)
+		case strings.Contains(h, "energy") || strings.Contains(h, "energy_consumption") || strings.Contains(h, "kwh"):
+			set(&mapping.EnergyColumn, column)
+		case strings.Contains(h, "power"):
+			set(&mapping.PowerColumn, column)
+		case strings.Contains(h, "duration") || strings.Contains(h, "usage"):
+			set(&mapping.DurationColumn, column)
+		case strings.Contains(h, "cost") || strings.Contains(h, "price"):
+			set(&mapping.CostColumn, column)
+		case strings.Contains(h, "type"):
+			set(&mapping.TypeColumn, column)
+		case strings.Contains(h, "location") || strings.Contains(h, "room"):
+			set(&mapping.LocationColumn, column)
+		case strings.Contains(h, "status"):
+			set(&mapping.StatusColumn, column)
+		case strings.Contains(h, "connect"):
+			set(&mapping.ConnectivityColumn, column)
+		case strings.Contains(h, "timestamp") || strings.Contains(h, "datetime"):
+			set(&mapping.TimestampColumn, column)
+		case h == "date" || h == "tanggal":
+			set(&mapping.DateColumn, column)
+		case h == "time" || h == "waktu":
+			set(&mapping.TimeColumn, column)
+		}
+	}
+
+	return withHeaderUnits(mapping)
+}
+
+// ValidateMapping memastikan kolom wajib terisi dan satuan dikenali
+func ValidateMapping(mapping entity.ColumnMapping) error {
+	if strings.TrimSpace(mapping.ApplianceColumn) == "" {
+		return errors.New("appliance_column is required")
+	}
+	if strings.TrimSpace(mapping.EnergyColumn) == "" {
+		return errors.New("energy_column is required")
+	}
+	if mapping.TimeColumn != "" && mapping.DateColumn == "" {
+		return errors.New("time_column requires date_column")
+	}
+	if _, err := ConvertEnergy(0, mapping.EnergyUnit); err != nil {
+		return err
+	}
+	if _, err := ConvertPower(0, mapping.PowerUnit); err != nil {
+		return err
+	}
+	if _, err := ConvertDuration(0, mapping.DurationUnit); err != nil {
+		return err
+	}
+	return ValidateSeparators(mapping.DecimalSeparator, mapping.ThousandSeparator)
+}
+
+// mappedColumns mengembalikan seluruh nama kolom yang dideklarasikan mapping
+func mappedColumns(mapping entity.ColumnMapping) []string {
+	var columns []string
+	for _, column := range []string{
+		mapping.ApplianceColumn, mapping.TimestampColumn, mapping.DateColumn, mapping.TimeColumn,
+		mapping.EnergyColumn, mapping.PowerColumn, mapping.DurationColumn, mapping.CostColumn,
+		mapping.TypeColumn, mapping.LocationColumn, mapping.StatusColumn, mapping.ConnectivityColumn,
+	} {
+		if column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// SuggestMappingProfile memilih profil yang seluruh kolomnya ada di header, mengutamakan yang paling banyak kolomnya.
+// Jika tidak ada yang cocok, dikembalikan profil "auto" hasil DetectMapping.
+func SuggestMappingProfile(header []string, candidates []entity.MappingProfileResponse) entity.MappingProfileResponse {
+	index := headerIndex(header)
+
+	best := -1
+	bestScore := 0
+	for i, candidate := range candidates {
+		if ValidateMapping(candidate.ColumnMapping) != nil {
+			continue
+		}
+
+		columns := mappedColumns(candidate.ColumnMapping)
+		matched := true
+		for _, column := range columns {
+			if _, ok := index[normalizeColumn(column)]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched && len(columns) > bestScore {
+			best, bestScore = i, len(columns)
+		}
+	}
+
+	if best >= 0 {
+		return candidates[best]
+	}
+
+	return entity.MappingProfileResponse{
+		Name:          AutoMappingProfile,
+		Description:   "Detected from header names",
+		BuiltIn:       true,
+		ColumnMapping: DetectMapping(header),
+	}
+}
+
+func normalizeColumn(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
+
+func headerIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		if _, exists := index[normalizeColumn(column)]; !exists {
+			index[normalizeColumn(column)] = i
+		}
+	}
+	return index
+}
+
+// columnIndexes adalah posisi kolom mapping di header; -1 jika tidak ada
+type columnIndexes struct {
+	appliance, timestamp, date, time    int
+	energy, power, duration, cost       int
+	typ, location, status, connectivity int
+}
+
+func resolveColumns(header []string, mapping entity.ColumnMapping) (columnIndexes, error) {
+	index := headerIndex(header)
+	var missing []string
+
+	lookup := func(column string) int {
+		if column == "" {
+			return -1
+		}
+		i, ok := index[normalizeColumn(column)]
+		if !ok {
+			missing = append(missing, column)
+			return -1
+		}
+		return i
+	}
+
+	columns := columnIndexes{
+		appliance:    lookup(mapping.ApplianceColumn),
+		timestamp:    lookup(mapping.TimestampColumn),
+		date:         lookup(mapping.DateColumn),
+		time:         lookup(mapping.TimeColumn),
+		energy:       lookup(mapping.EnergyColumn),
+		power:        lookup(mapping.PowerColumn),
+		duration:     lookup(mapping.DurationColumn),
+		cost:         lookup(mapping.CostColumn),
+		typ:          lookup(mapping.TypeColumn),
+		location:     lookup(mapping.LocationColumn),
+		status:       lookup(mapping.StatusColumn),
+		connectivity: lookup(mapping.ConnectivityColumn),
+	}
+
+	if len(missing) > 0 {
+		return columns, fmt.Errorf("columns not found in header: %s", strings.Join(missing, ", "))
+	}
+	if columns.appliance < 0 {
+		return columns, errors.New("appliance column not found in header")
+	}
+
+	return columns, nil
+}
+
+// ConvertEnergy mengubah nilai energi ke kWh
+func ConvertEnergy(value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "kwh":
+		return value, nil
+	case "wh":
+		return value / 1000, nil
+	default:
+		return 0, fmt.Errorf("unsupported energy unit %q, use kWh or Wh", unit)
+	}
+}
+
+// ConvertPower mengubah nilai daya ke Watt
+func ConvertPower(value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "w":
+		return value, nil
+	case "kw":
+		return value * 1000, nil
+	default:
+		return 0, fmt.Errorf("unsupported power unit %q, use W or kW", unit)
+	}
+}
+
+// ConvertDuration mengubah nilai durasi ke jam
+func ConvertDuration(value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "h", "hour", "hours", "jam":
+		return value, nil
+	case "min", "minute", "minutes", "menit":
+		return value / 60, nil
+	case "s", "sec", "second", "seconds", "detik":
+		return value / 3600, nil
+	default:
+		return 0, fmt.Errorf("unsupported duration unit %q, use h, min or s", unit)
+	}
+}
+
+// TimestampLayout mengubah format seperti "DD/MM/YYYY HH:mm" menjadi layout time.Parse
+func TimestampLayout(format string) string {
+	replacer := strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+		"HH", "15",
+		"mm", "04",
+		"ss", "05",
+	)
+	return replacer.Replace(format)
+}
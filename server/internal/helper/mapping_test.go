@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const kaggleCSV = `Home ID,Appliance Type,Energy Consumption (kWh),Time,Date,Outdoor Temperature (°C),Season,Household Size
+94,Fridge,0.2,21:12,2023-12-02,-1.0,Fall,2
+435,Oven,0.23,20:11,2023-08-06,31.1,Summer,5
+`
+
+func TestSuggestMappingProfile(t *testing.T) {
+	file, err := ReadCSVFile(strings.NewReader(kaggleCSV))
+	if err != nil {
+		t.Fatalf("ReadCSVFile returned error: %v", err)
+	}
+
+	profile := SuggestMappingProfile(file.Header, BuiltinMappingProfiles())
+	if profile.Name != "Kaggle smart home" {
+		t.Fatalf("expected Kaggle profile to be suggested, got %q", profile.Name)
+	}
+
+	parsed, err := file.Parse(profile.ColumnMapping)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.Readings) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(parsed.Readings))
+	}
+	want := time.Date(2023, 12, 2, 21, 12, 0, 0, jakarta)
+	if !parsed.Readings[0].Timestamp.Equal(want) {
+		t.Fatalf("expected timestamp %v, got %v", want, parsed.Readings[0].Timestamp)
+	}
+
+	// header tanpa profil yang cocok jatuh ke deteksi otomatis
+	auto := SuggestMappingProfile(strings.Split("timestamp,device,usage_kwh", ","), BuiltinMappingProfiles())
+	if auto.Name != AutoMappingProfile || auto.ApplianceColumn != "device" || auto.EnergyColumn != "usage_kwh" {
+		t.Fatalf("unexpected auto-detected profile: %+v", auto)
+	}
+}
+
+func TestCSVFileParse_CustomMappingUnits(t *testing.T) {
+	file, err := ReadCSVFile(strings.NewReader(`ts,alat,energi_wh,daya_kw,lama_menit
+05/01/2024 07:30,Pompa Air,1500,0.75,120
+`))
+	if err != nil {
+		t.Fatalf("ReadCSVFile returned error: %v", err)
+	}
+
+	mapping := entity.ColumnMapping{
+		ApplianceColumn: "alat",
+		TimestampColumn: "ts",
+		TimestampFormat: "DD/MM/YYYY HH:mm",
+		EnergyColumn:    "energi_wh",
+		EnergyUnit:      UnitWh,
+		PowerColumn:     "daya_kw",
+		PowerUnit:       UnitKW,
+		DurationColumn:  "lama_menit",
+		DurationUnit:    UnitMinute,
+	}
+
+	parsed, err := file.Parse(mapping)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	reading := parsed.Readings[0]
+	if reading.Energy != 1.5 || reading.Power != 750 || reading.Duration != 2 {
+		t.Fatalf("expected normalized units (1.5 kWh, 750 W, 2 h), got %+v", reading)
+	}
+	if reading.Timestamp.Day() != 5 || reading.Timestamp.Month() != time.January {
+		t.Fatalf("expected 5 January from DD/MM/YYYY format, got %v", reading.Timestamp)
+	}
+	if !parsed.Appliances[0].Priority {
+		t.Fatalf("expected 750 W appliance to be marked priority")
+	}
+
+	mapping.EnergyColumn = "missing"
+	if _, err := file.Parse(mapping); err == nil {
+		t.Fatalf("expected error for column missing from header")
+	}
+}
@@ -0,0 +1,289 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// Jenis besaran untuk konversi satuan
+const (
+	quantityEnergy = iota
+	quantityPower
+	quantityDuration
+)
+
+// csvDelimiters adalah pemisah kolom yang dikenali saat sniffing, berurutan menurut prioritas
+var csvDelimiters = []rune{',', ';', '\t', '|'}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// SniffDelimiter menebak pemisah kolom dari baris pertama (dan kedua bila ada) sampel file.
+// Pemisah yang jumlahnya sama di kedua baris diutamakan; default-nya koma.
+func SniffDelimiter(sample []byte) rune {
+	sample = bytes.TrimPrefix(sample, utf8BOM)
+	lines := splitSampleLines(sample, 2)
+	if len(lines) == 0 {
+		return ','
+	}
+
+	best, bestCount := ',', 0
+	fallback, fallbackCount := ',', 0
+	for _, delimiter := range csvDelimiters {
+		count := countOutsideQuotes(lines[0], delimiter)
+		if count == 0 {
+			continue
+		}
+		if count > fallbackCount {
+			fallback, fallbackCount = delimiter, count
+		}
+		if len(lines) > 1 && countOutsideQuotes(lines[1], delimiter) != count {
+			continue
+		}
+		if count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	if bestCount > 0 {
+		return best
+	}
+	return fallback
+}
+
+// splitSampleLines mengambil hingga n baris dari sampel tanpa memotong baris yang berada di dalam tanda kutip
+func splitSampleLines(sample []byte, n int) []string {
+	var lines []string
+	var current strings.Builder
+	inQuote := false
+	for _, r := range string(sample) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current.WriteRune(r)
+		case (r == '\n' || r == '\r') && !inQuote:
+			if current.Len() > 0 {
+				lines = append(lines, current.String())
+				current.Reset()
+				if len(lines) == n {
+					return lines
+				}
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	// Baris terakhir yang terpotong oleh batas sampel hanya dipakai jika belum ada baris lain
+	if current.Len() > 0 && len(lines) == 0 {
+		lines = append(lines, current.String())
+	}
+	return lines
+}
+
+func countOutsideQuotes(line string, delimiter rune) int {
+	count := 0
+	inQuote := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case r == delimiter && !inQuote:
+			count++
+		}
+	}
+	return count
+}
+
+// ValidateSeparators memastikan pemisah desimal dan ribuan dikenali dan tidak sama
+func ValidateSeparators(decimal, thousand string) error {
+	for _, separator := range []string{decimal, thousand} {
+		switch separator {
+		case "", ".", ",", " ", "'":
+		default:
+			return fmt.Errorf("unsupported number separator %q, use \".\", \",\", \" \" or \"'\"", separator)
+		}
+	}
+	if decimal != "" && decimal == thousand {
+		return errors.New("decimal_separator and thousand_separator must differ")
+	}
+	return nil
+}
+
+// ParseNumber membaca angka dengan pemisah desimal/ribuan yang diberikan. Jika pemisah desimal kosong,
+// format ditebak: pemisah yang muncul terakhir adalah desimal ("1.234,5" dan "1,234.5"), koma tunggal
+// dianggap desimal ("1,25"), dan titik yang muncul lebih dari sekali adalah pemisah ribuan ("1.250.000").
+// Hanya angka desimal yang diterima; NaN, Inf dan angka heksadesimal ditolak.
+func ParseNumber(value, decimal, thousand string) (float64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "\u00a0", " ")
+	if value == "" {
+		return 0, errors.New("number is empty")
+	}
+
+	if decimal == "" {
+		decimal, thousand = guessSeparators(value, thousand)
+	}
+	if thousand != "" {
+		value = strings.ReplaceAll(value, thousand, "")
+	}
+	value = strings.ReplaceAll(value, " ", "")
+	if decimal != "." {
+		value = strings.ReplaceAll(value, decimal, ".")
+	}
+
+	if strings.Trim(value, "0123456789.+-eE") != "" {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return number, nil
+}
+
+func guessSeparators(value, thousand string) (string, string) {
+	lastDot, lastComma := strings.LastIndex(value, "."), strings.LastIndex(value, ",")
+	switch {
+	case thousand == ",":
+		return ".", thousand
+	case thousand == ".":
+		return ",", thousand
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			return ",", "."
+		}
+		return ".", ","
+	case lastComma >= 0:
+		if strings.Count(value, ",") > 1 {
+			return ".", ","
+		}
+		return ",", thousand
+	case strings.Count(value, ".") > 1:
+		return ",", "."
+	default:
+		return ".", thousand
+	}
+}
+
+// parseQuantity membaca nilai yang boleh diakhiri satuan (mis. "1,25 kWh" atau "500W") dan mengubahnya
+// ke satuan standar; tanpa akhiran, satuan kolom (unit) yang dipakai
+func parseQuantity(value string, kind int, unit, decimal, thousand string) (float64, error) {
+	value = strings.TrimSpace(value)
+	number, suffix := splitUnitSuffix(value)
+	if suffix != "" {
+		unit = suffix
+	}
+
+	parsed, err := ParseNumber(number, decimal, thousand)
+	if err != nil {
+		return 0, err
+	}
+
+	switch kind {
+	case quantityEnergy:
+		return ConvertEnergy(parsed, unit)
+	case quantityPower:
+		return ConvertPower(parsed, unit)
+	default:
+		return ConvertDuration(parsed, unit)
+	}
+}
+
+// splitUnitSuffix memisahkan akhiran huruf (satuan) dari angka
+func splitUnitSuffix(value string) (string, string) {
+	number := strings.TrimRightFunc(value, unicode.IsLetter)
+	return strings.TrimSpace(number), value[len(number):]
+}
+
+// parseCurrency membaca nominal rupiah, dengan atau tanpa simbol mata uang. Jika pemisah tidak ditentukan,
+// titik tunggal yang diikuti tepat tiga angka adalah pemisah ribuan ("1.250" = 1250, bukan 1,25).
+func parseCurrency(value, decimal, thousand string) (float64, error) {
+	value = trimCurrency(value)
+	if decimal == "" && thousand == "" && rupiahThousands(value) {
+		decimal, thousand = ",", "."
+	}
+	return ParseNumber(value, decimal, thousand)
+}
+
+// trimCurrency membuang simbol mata uang rupiah di depan nilai biaya, mis. "Rp 1.250"
+func trimCurrency(value string) string {
+	value = strings.TrimSpace(value)
+	for _, prefix := range []string{"Rp.", "Rp", "IDR"} {
+		if len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			return strings.TrimSpace(value[len(prefix):])
+		}
+	}
+	return value
+}
+
+// rupiahThousands melaporkan apakah nilai hanya memuat satu titik yang diikuti tepat tiga angka, mis. "899.000"
+func rupiahThousands(value string) bool {
+	dot := strings.Index(value, ".")
+	if dot <= 0 || strings.Count(value, ".") > 1 || strings.Contains(value, ",") {
+		return false
+	}
+	fraction := value[dot+1:]
+	return len(fraction) == 3 && strings.Trim(fraction, "0123456789") == ""
+}
+
+// withHeaderUnits mengisi satuan yang kosong dari petunjuk pada nama kolom, mis. "Energy (Wh)" atau "Power_kW"
+func withHeaderUnits(mapping entity.ColumnMapping) entity.ColumnMapping {
+	if mapping.EnergyUnit == "" {
+		mapping.EnergyUnit = unitFromHeader(mapping.EnergyColumn, quantityEnergy)
+	}
+	if mapping.PowerUnit == "" {
+		mapping.PowerUnit = unitFromHeader(mapping.PowerColumn, quantityPower)
+	}
+	if mapping.DurationUnit == "" {
+		mapping.DurationUnit = unitFromHeader(mapping.DurationColumn, quantityDuration)
+	}
+	return mapping
+}
+
+// unitFromHeader mencari kata satuan pada nama kolom; jika tidak ada, satuan standar yang dipakai
+func unitFromHeader(column string, kind int) string {
+	words := strings.FieldsFunc(strings.ToLower(column), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		switch kind {
+		case quantityEnergy:
+			switch word {
+			case "wh":
+				return UnitWh
+			case "kwh":
+				return UnitKWh
+			}
+		case quantityPower:
+			switch word {
+			case "kw":
+				return UnitKW
+			case "w", "watt", "watts":
+				return UnitW
+			}
+		case quantityDuration:
+			switch word {
+			case "min", "mins", "minute", "minutes", "menit":
+				return UnitMinute
+			case "s", "sec", "secs", "second", "seconds", "detik":
+				return UnitSecond
+			case "h", "hr", "hrs", "hour", "hours", "jam":
+				return UnitHour
+			}
+		}
+	}
+
+	switch kind {
+	case quantityEnergy:
+		return UnitKWh
+	case quantityPower:
+		return UnitW
+	default:
+		return UnitHour
+	}
+}
@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestSniffDelimiter(t *testing.T) {
+	cases := map[string]rune{
+		"a,b,c\n1,2,3\n":                  ',',
+		"\xef\xbb\xbfa;b;c\n1,5;2;3\n":    ';',
+		"a\tb\n1\t2\n":                    '\t',
+		"a|b|c\n1|2|3\n":                  '|',
+		"\"x;y\",b,c\n\"1;2\",2,3\n":      ',',
+		"Perangkat;Energi (Wh)\nTV;1,5\n": ';',
+	}
+	for sample, want := range cases {
+		if got := SniffDelimiter([]byte(sample)); got != want {
+			t.Errorf("SniffDelimiter(%q) = %q, want %q", sample, got, want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	cases := []struct {
+		value, decimal, thousand string
+		want                     float64
+	}{
+		{"1,25", "", "", 1.25},
+		{"1.25", "", "", 1.25},
+		{"1.234,5", "", "", 1234.5},
+		{"1,234.5", "", "", 1234.5},
+		{"1.250.000", "", "", 1250000},
+		{"1.250", ",", ".", 1250},
+		{"1 250,75", ",", " ", 1250.75},
+		{"-0,5", "", "", -0.5},
+	}
+	for _, c := range cases {
+		got, err := ParseNumber(c.value, c.decimal, c.thousand)
+		if err != nil || got != c.want {
+			t.Errorf("ParseNumber(%q, %q, %q) = %v, %v; want %v", c.value, c.decimal, c.thousand, got, err, c.want)
+		}
+	}
+	for _, value := range []string{"1,2,3.4.5", "NaN", "Inf", "-infinity", "0x1p4", "1e999"} {
+		if _, err := ParseNumber(value, "", ""); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	cases := map[string]float64{
+		"Rp 1.250":     1250,
+		"Rp 899.000":   899000,
+		"Rp. 1.250,5":  1250.5,
+		"IDR 1,250":    1.25,
+		"Rp 4.250.000": 4250000,
+		"1.250":        1250,
+		"899.000":      899000,
+		"1.5":          1.5,
+	}
+	for value, want := range cases {
+		got, err := parseCurrency(value, "", "")
+		if err != nil || got != want {
+			t.Errorf("parseCurrency(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+}
+
+func TestParseCSV_SemicolonDecimalCommaAndUnits(t *testing.T) {
+	input := "\xef\xbb\xbfPerangkat;Tanggal;Energi (Wh);Daya (kW);Durasi (menit);Biaya\n" +
+		"Kulkas;2024-01-01 00:00;1250,5;0,15;90;Rp 1.500\n" +
+		"TV;2024-01-01 01:00;300 Wh;120 W;1,5 jam;2000\n"
+
+	file, err := ReadCSVFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSVFile returned error: %v", err)
+	}
+	if file.Header[0] != "Perangkat" {
+		t.Fatalf("BOM must be stripped from the first header, got %q", file.Header[0])
+	}
+
+	parsed, err := file.Parse(entity.ColumnMapping{
+		ApplianceColumn:   "Perangkat",
+		TimestampColumn:   "Tanggal",
+		EnergyColumn:      "Energi (Wh)",
+		PowerColumn:       "Daya (kW)",
+		DurationColumn:    "Durasi (menit)",
+		CostColumn:        "Biaya",
+		ThousandSeparator: ".",
+	})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(parsed.RowErrors) != 0 || len(parsed.Readings) != 2 {
+		t.Fatalf("expected 2 readings without errors, got %+v / %+v", parsed.Readings, parsed.RowErrors)
+	}
+
+	fridge, tv := parsed.Readings[0], parsed.Readings[1]
+	if fridge.Energy != 1.2505 || fridge.Power != 150 || fridge.Duration != 1.5 {
+		t.Fatalf("unexpected normalized fridge reading: %+v", fridge)
+	}
+	if tv.Energy != 0.3 || tv.Power != 120 || tv.Duration != 1.5 {
+		t.Fatalf("unexpected normalized tv reading: %+v", tv)
+	}
+	if parsed.Appliances[0].Cost != 1500 {
+		t.Fatalf("expected cost 1500, got %v", parsed.Appliances[0].Cost)
+	}
+}
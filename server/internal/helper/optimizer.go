@@ -0,0 +1,191 @@
+package helper
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// allocationStep adalah penambahan jam per langkah optimasi (15 menit)
+	allocationStep = 0.25
+	// priorityWeight membuat jam appliance prioritas bernilai dua kali lipat
+	priorityWeight = 2.0
+	// priorityMinShare adalah porsi minimal pemakaian appliance prioritas jika target harian belum diatur
+	priorityMinShare = 0.5
+	maxHoursPerDay   = 24.0
+)
+
+var ErrInvalidDaysInMonth = errors.New("days in month must be positive")
+
+// OptimizeMonthlyBudget membagi anggaran energi bulanan ke jam pemakaian harian setiap appliance.
+// Setiap appliance memiliki utilitas konkaf w·D·ln(1 + h/D) (D = jam pemakaian biasa), sehingga jam
+// tambahan makin kurang bernilai. Semua appliance dimulai dari jam minimalnya, lalu anggaran sisa diberikan
+// per 15 menit ke appliance dengan tambahan utilitas per kWh terbesar. Karena utilitasnya konkaf dan biaya
+// per jam konstan, cara ini menghasilkan alokasi optimal (water-filling) dan tidak ada appliance yang
+// tersingkir hanya karena urutannya.
+func OptimizeMonthlyBudget(appliances []entity.ApplianceResponse, tarif float64, daysInMonth int, maxEnergy float64) (entity.BudgetPlan, error) {
+	if daysInMonth <= 0 {
+		return entity.BudgetPlan{}, ErrInvalidDaysInMonth
+	}
+	plan := entity.BudgetPlan{
+		DaysInMonth: daysInMonth,
+		Tarif:       tarif,
+		BudgetKWh:   maxEnergy,
+		BudgetCost:  maxEnergy * tarif,
+		Allocations: make([]entity.BudgetAllocation, 0, len(appliances)),
+	}
+	days := float64(daysInMonth)
+
+	var optimized []int
+	minEnergy := 0.0
+	for _, appliance := range appliances {
+		allocation := entity.BudgetAllocation{
+			ApplianceID:  appliance.ID,
+			Name:         appliance.Name,
+			Type:         appliance.Type,
+			Priority:     appliance.Priority,
+			KWhPerHour:   applianceKWhPerHour(appliance),
+			DesiredHours: math.Min(math.Max(appliance.AverageUsage, 0), maxHoursPerDay),
+		}
+		allocation.MaxHours = allocation.DesiredHours
+		if appliance.Priority {
+			allocation.MinHours = allocation.DesiredHours * priorityMinShare
+			if appliance.DailyUseTarget > 0 && appliance.DailyUseTarget < allocation.DesiredHours {
+				allocation.MinHours = appliance.DailyUseTarget
+			}
+		}
+		allocation.RecommendedHours = allocation.MinHours
+		plan.DesiredKWh += allocation.DesiredHours * allocation.KWhPerHour * days
+
+		if allocation.KWhPerHour <= 0 {
+			// Tanpa daya atau energi yang tercatat, pemakaian tidak dapat dihitung terhadap anggaran
+			allocation.RecommendedHours = allocation.DesiredHours
+		} else {
+			optimized = append(optimized, len(plan.Allocations))
+			minEnergy += allocation.MinHours * allocation.KWhPerHour * days
+		}
+		plan.Allocations = append(plan.Allocations, allocation)
+	}
+
+	belowMinimum := minEnergy > maxEnergy
+	if belowMinimum {
+		// Anggaran bahkan tidak cukup untuk jam minimal: semua jam minimal diperkecil secara proporsional
+		scale := 0.0
+		if minEnergy > 0 {
+			scale = math.Max(maxEnergy, 0) / minEnergy
+		}
+		for _, i := range optimized {
+			plan.Allocations[i].RecommendedHours = plan.Allocations[i].MinHours * scale
+		}
+	} else {
+		remaining := maxEnergy - minEnergy
+		for remaining > 1e-9 {
+			best, bestStep, bestRatio := -1, 0.0, 0.0
+			for _, i := range optimized {
+				allocation := &plan.Allocations[i]
+				step := math.Min(allocationStep, allocation.MaxHours-allocation.RecommendedHours)
+				step = math.Min(step, remaining/(allocation.KWhPerHour*days))
+				if step <= 1e-9 {
+					continue
+				}
+				gain := allocationUtility(*allocation, allocation.RecommendedHours+step) - allocationUtility(*allocation, allocation.RecommendedHours)
+				if ratio := gain / (step * allocation.KWhPerHour * days); best < 0 || ratio > bestRatio {
+					best, bestStep, bestRatio = i, step, ratio
+				}
+			}
+			if best < 0 {
+				break
+			}
+			plan.Allocations[best].RecommendedHours += bestStep
+			remaining -= bestStep * plan.Allocations[best].KWhPerHour * days
+		}
+	}
+
+	for i := range plan.Allocations {
+		allocation := &plan.Allocations[i]
+		// Dibulatkan ke bawah agar total tidak melewati anggaran
+		if allocation.RecommendedHours < allocation.DesiredHours-1e-9 {
+			allocation.RecommendedHours = math.Floor(allocation.RecommendedHours*100+1e-6) / 100
+		} else {
+			allocation.RecommendedHours = allocation.DesiredHours
+		}
+		allocation.MonthlyKWh = allocation.RecommendedHours * allocation.KWhPerHour * days
+		allocation.MonthlyCost = allocation.MonthlyKWh * tarif
+		allocation.Utility = allocationUtility(*allocation, allocation.RecommendedHours)
+		allocation.Reason = allocationReason(*allocation, belowMinimum)
+		allocation.Message = RenderRecommendation(BudgetRecommendation(*allocation, daysInMonth, tarif), LanguageID)
+
+		plan.AllocatedKWh += allocation.MonthlyKWh
+		plan.AllocatedCost += allocation.MonthlyCost
+		plan.Utility += allocation.Utility
+	}
+
+	return plan, nil
+}
+
+// ScheduleBudgetPlan mengisi Schedule setiap alokasi dengan jadwal harian sepanjang RecommendedHours yang
+// disusun BuildDaySchedule pada hari day, sehingga jam nyala mengikuti harga termurah dan daya tersambung tarif
+func ScheduleBudgetPlan(plan *entity.BudgetPlan, tariff TariffSchedule, day time.Time) {
+	var tasks []ScheduleTask
+	var allocations []int
+	for i, allocation := range plan.Allocations {
+		if allocation.RecommendedHours <= 0 || allocation.KWhPerHour <= 0 {
+			continue
+		}
+		tasks = append(tasks, ScheduleTask{
+			Appliance: entity.ApplianceResponse{
+				ID:       allocation.ApplianceID,
+				Name:     allocation.Name,
+				Power:    int(math.Round(allocation.KWhPerHour * 1000)),
+				Priority: allocation.Priority,
+			},
+			Hours: allocation.RecommendedHours,
+		})
+		allocations = append(allocations, i)
+	}
+
+	schedule := BuildDaySchedule(day, tasks, tariff, ScheduleOptions{VALimit: ContractedVA(tariff.VersionAt(day))})
+	for i, index := range allocations {
+		plan.Allocations[index].Schedule = schedule.Appliances[i].Runs
+	}
+}
+
+// applianceKWhPerHour memakai daya appliance; jika kosong, dihitung dari energi dan durasi pemakaian hari ini
+func applianceKWhPerHour(appliance entity.ApplianceResponse) float64 {
+	if appliance.Power > 0 {
+		return float64(appliance.Power) / 1000
+	}
+	if appliance.Energy > 0 && appliance.UsageToday > 0 {
+		return appliance.Energy / appliance.UsageToday
+	}
+	return 0
+}
+
+func allocationUtility(allocation entity.BudgetAllocation, hours float64) float64 {
+	if allocation.DesiredHours <= 0 {
+		return 0
+	}
+	weight := 1.0
+	if allocation.Priority {
+		weight = priorityWeight
+	}
+	return weight * allocation.DesiredHours * math.Log1p(hours/allocation.DesiredHours)
+}
+
+func allocationReason(allocation entity.BudgetAllocation, belowMinimum bool) string {
+	switch {
+	case allocation.KWhPerHour <= 0:
+		return entity.AllocationUnknownPower
+	case allocation.RecommendedHours >= allocation.DesiredHours:
+		return entity.AllocationUnchanged
+	case allocation.Priority && belowMinimum:
+		return entity.AllocationBelowMinimum
+	case allocation.Priority && allocation.RecommendedHours <= allocation.MinHours:
+		return entity.AllocationPriorityMinimum
+	default:
+		return entity.AllocationReducedBudget
+	}
+}
@@ -0,0 +1,94 @@
+package helper
+
+import (
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+var budgetAppliances = []entity.ApplianceResponse{
+	{ID: 1, Name: "AC", Power: 1000, AverageUsage: 8},
+	{ID: 2, Name: "Kulkas", Power: 100, AverageUsage: 24, Priority: true},
+	{ID: 3, Name: "TV", Power: 100, AverageUsage: 4},
+	{ID: 4, Name: "Lampu", Power: 50, AverageUsage: 6},
+}
+
+func TestOptimizeMonthlyBudget_ReducesInsteadOfDropping(t *testing.T) {
+	// Pemakaian biasa 240 + 72 + 12 + 9 = 333 kWh; anggaran hanya 150 kWh
+	plan, _ := OptimizeMonthlyBudget(budgetAppliances, 1000, 30, 150)
+
+	if plan.DesiredKWh != 333 {
+		t.Fatalf("expected desired 333 kWh, got %v", plan.DesiredKWh)
+	}
+	if plan.AllocatedKWh > 150+1e-9 || plan.AllocatedKWh < 149 {
+		t.Fatalf("expected allocation close to the 150 kWh budget, got %v", plan.AllocatedKWh)
+	}
+	for _, allocation := range plan.Allocations {
+		if allocation.RecommendedHours <= 0 {
+			t.Fatalf("%s should get reduced hours instead of being dropped: %+v", allocation.Name, allocation)
+		}
+		if allocation.RecommendedHours < allocation.MinHours-0.01 || allocation.RecommendedHours > allocation.DesiredHours {
+			t.Fatalf("%s hours %v outside [%v, %v]", allocation.Name, allocation.RecommendedHours, allocation.MinHours, allocation.DesiredHours)
+		}
+	}
+
+	// Appliance kecil terpenuhi seluruhnya, AC yang paling boros energi dikurangi
+	byName := make(map[string]entity.BudgetAllocation)
+	for _, allocation := range plan.Allocations {
+		byName[allocation.Name] = allocation
+	}
+	if byName["Lampu"].Reason != entity.AllocationUnchanged || byName["TV"].Reason != entity.AllocationUnchanged {
+		t.Fatalf("expected small appliances unchanged, got %+v %+v", byName["Lampu"], byName["TV"])
+	}
+	if byName["AC"].Reason != entity.AllocationReducedBudget || byName["Kulkas"].RecommendedHours < 12 {
+		t.Fatalf("expected AC reduced and fridge above its minimum, got %+v %+v", byName["AC"], byName["Kulkas"])
+	}
+}
+
+func TestOptimizeMonthlyBudget_Bounds(t *testing.T) {
+	ample, _ := OptimizeMonthlyBudget(budgetAppliances, 1000, 30, 1000)
+	for _, allocation := range ample.Allocations {
+		if allocation.Reason != entity.AllocationUnchanged {
+			t.Fatalf("expected every appliance unchanged with ample budget, got %+v", allocation)
+		}
+	}
+
+	// Minimal kulkas (12 jam × 0,1 kW × 30 hari = 36 kWh) melebihi anggaran 18 kWh
+	tight, _ := OptimizeMonthlyBudget(budgetAppliances, 1000, 30, 18)
+	for _, allocation := range tight.Allocations {
+		switch {
+		case allocation.Priority && (allocation.Reason != entity.AllocationBelowMinimum || allocation.RecommendedHours != 6):
+			t.Fatalf("expected fridge scaled to 6 hours, got %+v", allocation)
+		case !allocation.Priority && allocation.RecommendedHours != 0:
+			t.Fatalf("expected %s to get no hours, got %+v", allocation.Name, allocation)
+		}
+	}
+}
+
+func TestOptimizeMonthlyBudget_RejectsZeroDays(t *testing.T) {
+	if _, err := OptimizeMonthlyBudget(budgetAppliances, 1000, 0, 150); err != ErrInvalidDaysInMonth {
+		t.Fatalf("expected ErrInvalidDaysInMonth, got %v", err)
+	}
+}
+
+func TestScheduleBudgetPlan_MatchesRecommendedHours(t *testing.T) {
+	plan, _ := OptimizeMonthlyBudget(budgetAppliances, 1000, 30, 150)
+	ScheduleBudgetPlan(&plan, peakTariff, scheduleDay)
+
+	for _, allocation := range plan.Allocations {
+		hours := 0.0
+		for _, run := range allocation.Schedule {
+			hours += run.Hours
+			start, _ := parseClock(run.Start)
+			end, _ := parseClock(run.End)
+			// Di luar WBP hanya tersedia 19 jam
+			if allocation.RecommendedHours <= 19 && start < 22*60 && end > 17*60 {
+				t.Fatalf("%s should avoid the peak window, got run %+v", allocation.Name, run)
+			}
+		}
+		// Jadwal dibulatkan ke atas ke slot 15 menit
+		if hours < allocation.RecommendedHours || hours > allocation.RecommendedHours+0.25 {
+			t.Fatalf("%s scheduled %v hours for %v recommended hours: %+v", allocation.Name, hours, allocation.RecommendedHours, allocation.Schedule)
+		}
+	}
+}
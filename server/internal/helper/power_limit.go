@@ -0,0 +1,362 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// DefaultConnectionFeePerVA adalah biaya penyambungan per VA saat menaikkan daya rumah tangga tegangan rendah
+	DefaultConnectionFeePerVA = 969.0
+	// upgradeAmortizationMonths adalah lama biaya penyambungan disebar saat dibandingkan dengan tagihan bulanan
+	upgradeAmortizationMonths = 12
+)
+
+// standardVA adalah daya tersambung PLN tegangan rendah yang dapat dipasang; di atasnya daya dibulatkan ke 1.000 VA
+var standardVA = []int{450, 900, 1300, 2200, 3500, 4400, 5500, 6600, 7700, 10600, 11000, 13200, 16500, 22000, 23000, 33000, 41500, 53000, 66000, 82500, 105000, 131000, 147000, 164000, 197000}
+
+// HourlyUsage adalah daya (watt) setiap appliance yang menyala pada jam 0–23, dengan key ID appliance
+type HourlyUsage [24]map[uint]float64
+
+func newHourlyUsage() HourlyUsage {
+	var usage HourlyUsage
+	for hour := range usage {
+		usage[hour] = make(map[uint]float64)
+	}
+	return usage
+}
+
+// PowerLimitOptions mengatur batas daya tersambung dan biaya naik daya; PowerFactor 0 berarti nilai default
+type PowerLimitOptions struct {
+	VALimit            int
+	PowerFactor        float64
+	ConnectionFeePerVA float64
+}
+
+// ObservedHourlyUsage menandai appliance menyala pada jam reading yang memiliki energi atau durasi.
+// Beban dihitung dari daya pada reading (atau daya appliance) karena MCB trip mengikuti daya sesaat,
+// bukan rata-rata energi per jam.
+func ObservedHourlyUsage(readings []entity.Reading, appliances []entity.ApplianceResponse) HourlyUsage {
+	powers := make(map[uint]int, len(appliances))
+	for _, appliance := range appliances {
+		powers[appliance.ID] = appliance.Power
+	}
+
+	usage := newHourlyUsage()
+	for _, reading := range readings {
+		if reading.ApplianceID == 0 || (reading.Energy <= 0 && reading.Duration <= 0) {
+			continue
+		}
+		power := reading.Power
+		if power <= 0 {
+			power = powers[reading.ApplianceID]
+		}
+		if power <= 0 {
+			continue
+		}
+		hour := reading.Timestamp.In(jakarta).Hour()
+		usage[hour][reading.ApplianceID] = math.Max(usage[hour][reading.ApplianceID], float64(power))
+	}
+	return usage
+}
+
+// PlannedHourlyUsage menandai appliance menyala pada setiap jam yang beririsan dengan rencana nyalanya.
+// Run dengan End lebih kecil dari Start dianggap melewati tengah malam.
+func PlannedHourlyUsage(planned []entity.PlannedUsage, appliances []entity.ApplianceResponse) (HourlyUsage, error) {
+	byID := make(map[uint]entity.ApplianceResponse, len(appliances))
+	for _, appliance := range appliances {
+		byID[appliance.ID] = appliance
+	}
+
+	usage := newHourlyUsage()
+	for _, plan := range planned {
+		appliance, ok := byID[plan.ApplianceID]
+		if !ok {
+			return HourlyUsage{}, fmt.Errorf("appliance %d not found", plan.ApplianceID)
+		}
+		for _, run := range plan.Runs {
+			start, err := parseClock(run.Start)
+			if err != nil {
+				return HourlyUsage{}, fmt.Errorf("invalid start for appliance %d: %w", plan.ApplianceID, err)
+			}
+			end, err := parseClock(run.End)
+			if err != nil {
+				return HourlyUsage{}, fmt.Errorf("invalid end for appliance %d: %w", plan.ApplianceID, err)
+			}
+			if start == end {
+				return HourlyUsage{}, fmt.Errorf("run for appliance %d must have different start and end", plan.ApplianceID)
+			}
+			if appliance.Power <= 0 {
+				continue
+			}
+			for hour := 0; hour < 24; hour++ {
+				if clockOverlaps(start, end, hour*60, hour*60+60) {
+					usage[hour][appliance.ID] = float64(appliance.Power)
+				}
+			}
+		}
+	}
+	return usage, nil
+}
+
+// parseClock mengubah "HH:MM" menjadi menit sejak tengah malam; "24:00" diterima sebagai akhir hari
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("time %q must use HH:MM", value)
+	}
+	hour, errHour := strconv.Atoi(parts[0])
+	minute, errMinute := strconv.Atoi(parts[1])
+	if errHour != nil || errMinute != nil || hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("time %q must be between 00:00 and 24:00", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// clockOverlaps bernilai true jika rentang [start, end) beririsan dengan [from, to); start > end melewati tengah malam
+func clockOverlaps(start, end, from, to int) bool {
+	if start < end {
+		return start < to && from < end
+	}
+	return start < to || from < end
+}
+
+func hourLabel(hour int) string {
+	return fmt.Sprintf("%02d:00", hour)
+}
+
+// AnalyzePowerLimit membandingkan beban serentak per jam dengan VALimit. Untuk setiap jam yang melewati batas,
+// appliance non-prioritas berdaya terbesar disarankan pindah ke jam yang masih longgar dengan selisih harga
+// paling kecil. Biaya perpindahan jam lalu dibandingkan dengan naik daya ke golongan terkecil di catalogue
+// yang mampu menampung beban puncak.
+func AnalyzePowerLimit(day time.Time, usage HourlyUsage, appliances []entity.ApplianceResponse, tariff TariffSchedule, catalogue []entity.TariffResponse, options PowerLimitOptions) entity.PowerLimitAnalysis {
+	if options.PowerFactor <= 0 {
+		options.PowerFactor = DefaultPowerFactor
+	}
+	if options.ConnectionFeePerVA < 0 {
+		options.ConnectionFeePerVA = 0
+	}
+
+	day = day.In(jakarta)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, jakarta)
+	days := float64(time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, jakarta).Day())
+	current := tariff.VersionAt(start)
+	limit := float64(options.VALimit)
+
+	names := make(map[uint]entity.ApplianceResponse, len(appliances))
+	for _, appliance := range appliances {
+		names[appliance.ID] = appliance
+	}
+
+	result := entity.PowerLimitAnalysis{
+		Date:        start.Format("2006-01-02"),
+		Tariff:      current.Code,
+		VALimit:     options.VALimit,
+		PowerFactor: options.PowerFactor,
+		Hours:       make([]entity.HourLoad, 24),
+		Overloads:   []entity.PowerOverload{},
+	}
+
+	var prices, loadVA [24]float64
+	var active [24]map[uint]float64
+	for hour := range usage {
+		prices[hour] = tariff.Cost(start.Add(time.Duration(hour)*time.Hour), 1, 0)
+		active[hour] = make(map[uint]float64, len(usage[hour]))
+		load := entity.HourLoad{Hour: hourLabel(hour), ApplianceIDs: []uint{}}
+		for id, power := range usage[hour] {
+			active[hour][id] = power
+			load.LoadW += power
+			load.ApplianceIDs = append(load.ApplianceIDs, id)
+		}
+		sort.Slice(load.ApplianceIDs, func(i, j int) bool { return load.ApplianceIDs[i] < load.ApplianceIDs[j] })
+		load.LoadVA = load.LoadW / options.PowerFactor
+		load.Overloaded = limit > 0 && load.LoadVA > limit+1e-9
+		loadVA[hour] = load.LoadVA
+		result.Hours[hour] = load
+		if load.LoadVA > result.PeakVA {
+			result.PeakVA, result.PeakHour = load.LoadVA, load.Hour
+		}
+	}
+
+	if limit <= 0 {
+		result.Recommendation = entity.PowerLimitOK
+		result.Message = "Tarif tidak membatasi daya tersambung."
+		return result
+	}
+
+	resolved := true
+	for hour := range result.Hours {
+		if !result.Hours[hour].Overloaded {
+			continue
+		}
+		overload := entity.PowerOverload{
+			Hour:     result.Hours[hour].Hour,
+			LoadVA:   loadVA[hour],
+			ExcessVA: loadVA[hour] - limit,
+			Stagger:  []entity.StaggerSuggestion{},
+		}
+
+		// Appliance prioritas tidak dipindahkan; sisanya dicoba mulai dari daya terbesar
+		var candidates []uint
+		for _, id := range result.Hours[hour].ApplianceIDs {
+			if !names[id].Priority {
+				candidates = append(candidates, id)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return active[hour][candidates[i]] > active[hour][candidates[j]]
+		})
+
+		excess := overload.ExcessVA
+		var moved []string
+		for _, id := range candidates {
+			if excess <= 1e-9 {
+				break
+			}
+			power := active[hour][id]
+			va := power / options.PowerFactor
+			suggestion := entity.StaggerSuggestion{ApplianceID: id, Name: names[id].Name, Power: int(power)}
+
+			target := -1
+			for t := 0; t < 24; t++ {
+				if t == hour || active[t][id] > 0 || loadVA[t]+va > limit+1e-9 {
+					continue
+				}
+				if target < 0 || prices[t] < prices[target]-1e-9 ||
+					(prices[t] < prices[target]+1e-9 && absInt(t-hour) < absInt(target-hour)) {
+					target = t
+				}
+			}
+			if target >= 0 {
+				suggestion.MoveTo = hourLabel(target)
+				suggestion.ExtraCost = (prices[target] - prices[hour]) * power / 1000
+				active[target][id] = power
+				delete(active[hour], id)
+				loadVA[target] += va
+				loadVA[hour] -= va
+				excess -= va
+				result.StaggerMonthlyCost += suggestion.ExtraCost * days
+				moved = append(moved, fmt.Sprintf("%s ke %s", suggestion.Name, suggestion.MoveTo))
+			}
+			overload.Stagger = append(overload.Stagger, suggestion)
+		}
+
+		overload.Resolved = excess <= 1e-9
+		if overload.Resolved {
+			overload.Message = fmt.Sprintf("Beban pukul %s mencapai %.0f VA (batas %d VA). Pindahkan %s agar MCB tidak trip.", overload.Hour, overload.LoadVA, options.VALimit, strings.Join(moved, ", "))
+		} else {
+			resolved = false
+			overload.Message = fmt.Sprintf("Beban pukul %s mencapai %.0f VA (batas %d VA) dan tidak cukup dikurangi dengan memindahkan appliance non-prioritas.", overload.Hour, overload.LoadVA, options.VALimit)
+		}
+		result.Overloads = append(result.Overloads, overload)
+	}
+
+	if len(result.Overloads) == 0 {
+		result.Recommendation = entity.PowerLimitOK
+		result.Message = fmt.Sprintf("Beban tertinggi %.0f VA masih di bawah daya tersambung %d VA.", result.PeakVA, options.VALimit)
+		return result
+	}
+
+	result.Upgrade = upgradeOption(current, catalogue, usage, tariff, start, days, result.PeakVA, options)
+	switch {
+	case !resolved && result.Upgrade != nil:
+		result.Recommendation = entity.PowerLimitUpgrade
+		result.Message = fmt.Sprintf("Memindahkan jam pemakaian saja tidak cukup. Naikkan daya ke %s (%d VA) dengan tambahan biaya sekitar Rp%.2f/bulan.", result.Upgrade.Tariff, result.Upgrade.VALimit, result.Upgrade.EffectiveMonthlyCost)
+	case !resolved:
+		result.Recommendation = entity.PowerLimitStagger
+		result.Message = "Tidak ada golongan tarif yang mampu menampung beban puncak. Kurangi appliance yang menyala bersamaan."
+	case result.Upgrade != nil && result.Upgrade.EffectiveMonthlyCost < result.StaggerMonthlyCost:
+		result.Recommendation = entity.PowerLimitUpgrade
+		result.Message = fmt.Sprintf("Naik daya ke %s (%d VA) lebih murah (Rp%.2f/bulan) dibanding memindahkan jam pemakaian (Rp%.2f/bulan).", result.Upgrade.Tariff, result.Upgrade.VALimit, result.Upgrade.EffectiveMonthlyCost, result.StaggerMonthlyCost)
+	default:
+		result.Recommendation = entity.PowerLimitStagger
+		result.Message = fmt.Sprintf("Memindahkan jam pemakaian lebih murah (Rp%.2f/bulan) dibanding naik daya.", result.StaggerMonthlyCost)
+	}
+	return result
+}
+
+// upgradeOption memilih golongan non-subsidi dengan kelompok yang sama (mis. R) dan daya terkecil yang
+// mampu menampung peakVA, lalu menghitung tagihan bulanan dari pola pemakaian yang dianalisis
+func upgradeOption(current entity.TariffResponse, catalogue []entity.TariffResponse, usage HourlyUsage, tariff TariffSchedule, start time.Time, days, peakVA float64, options PowerLimitOptions) *entity.UpgradeOption {
+	group := tariffGroup(current.Code)
+	var best *entity.TariffResponse
+	bestVA := 0
+	for i := range catalogue {
+		candidate := catalogue[i]
+		if tariffGroup(candidate.Code) != group || strings.HasSuffix(candidate.Code, "-S") {
+			continue
+		}
+		va, ok := upgradeVA(candidate, math.Max(peakVA, float64(options.VALimit+1)))
+		if !ok {
+			continue
+		}
+		if best == nil || va < bestVA || (va == bestVA && candidate.PricePerKWh < best.PricePerKWh) {
+			best, bestVA = &catalogue[i], va
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	upgraded := NewTariffSchedule([]entity.TariffResponse{*best})
+	option := &entity.UpgradeOption{
+		Tariff:        best.Code,
+		Name:          best.Name,
+		VALimit:       bestVA,
+		PricePerKWh:   best.PricePerKWh,
+		ConnectionFee: float64(bestVA-options.VALimit) * options.ConnectionFeePerVA,
+	}
+	for hour := range usage {
+		at := start.Add(time.Duration(hour) * time.Hour)
+		for _, power := range usage[hour] {
+			energy := power / 1000 * days
+			option.CurrentMonthlyCost += tariff.Cost(at, energy, option.MonthlyKWh)
+			option.MonthlyCost += upgraded.Cost(at, energy, option.MonthlyKWh)
+			option.MonthlyKWh += energy
+		}
+	}
+	option.MonthlyDifference = option.MonthlyCost - option.CurrentMonthlyCost
+	option.EffectiveMonthlyCost = option.MonthlyDifference + option.ConnectionFee/upgradeAmortizationMonths
+	return option
+}
+
+// upgradeVA mengembalikan daya standar terkecil dalam rentang [MinVA, MaxVA] golongan yang tidak kurang dari
+// need; MaxVA 0 berarti tanpa batas atas. ok false jika rentang golongan tidak dapat menampung need.
+func upgradeVA(tariff entity.TariffResponse, need float64) (int, bool) {
+	need = math.Max(need, float64(tariff.MinVA))
+	va := int(math.Ceil(need/1000)) * 1000
+	for _, step := range standardVA {
+		if float64(step) >= need {
+			va = step
+			break
+		}
+	}
+	if va < tariff.MinVA {
+		va = tariff.MinVA
+	}
+	if tariff.MaxVA > 0 && va > tariff.MaxVA {
+		return 0, false
+	}
+	return va, true
+}
+
+// tariffGroup mengambil kelompok tarif dari kode, mis. "R-1/900" menjadi "R"
+func tariffGroup(code string) string {
+	if i := strings.IndexAny(code, "-/"); i >= 0 {
+		return code[:i]
+	}
+	return code
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
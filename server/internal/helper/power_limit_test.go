@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func defaultCatalogue() []entity.TariffResponse {
+	var catalogue []entity.TariffResponse
+	for _, req := range DefaultTariffs() {
+		catalogue = append(catalogue, entity.TariffResponse{
+			Code:          req.Code,
+			Name:          req.Name,
+			MinVA:         req.MinVA,
+			MaxVA:         req.MaxVA,
+			PricePerKWh:   req.PricePerKWh,
+			Currency:      req.Currency,
+			Model:         req.Model,
+			Windows:       req.Windows,
+			EffectiveFrom: req.EffectiveFrom,
+		})
+	}
+	return catalogue
+}
+
+func catalogueSchedule(code string) TariffSchedule {
+	for _, tariff := range defaultCatalogue() {
+		if tariff.Code == code {
+			return NewTariffSchedule([]entity.TariffResponse{tariff})
+		}
+	}
+	return nil
+}
+
+func TestAnalyzePowerLimit_SuggestsStagger(t *testing.T) {
+	appliances := []entity.ApplianceResponse{
+		{ID: 1, Name: "AC", Power: 700, Priority: true},
+		{ID: 2, Name: "Setrika", Power: 400},
+		{ID: 3, Name: "Rice Cooker", Power: 300},
+	}
+	usage, err := PlannedHourlyUsage([]entity.PlannedUsage{
+		{ApplianceID: 1, Runs: []entity.ScheduleRun{{Start: "19:00", End: "21:00"}}},
+		{ApplianceID: 2, Runs: []entity.ScheduleRun{{Start: "19:00", End: "20:00"}}},
+		{ApplianceID: 3, Runs: []entity.ScheduleRun{{Start: "19:15", End: "19:45"}}},
+	}, appliances)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tariff := catalogueSchedule("R-1/900")
+	analysis := AnalyzePowerLimit(scheduleDay, usage, appliances, tariff, defaultCatalogue(), PowerLimitOptions{VALimit: 900, PowerFactor: 1, ConnectionFeePerVA: DefaultConnectionFeePerVA})
+
+	if analysis.PeakVA != 1400 || analysis.PeakHour != "19:00" {
+		t.Fatalf("expected peak 1400 VA at 19:00, got %v at %s", analysis.PeakVA, analysis.PeakHour)
+	}
+	if len(analysis.Overloads) != 1 || !analysis.Overloads[0].Resolved {
+		t.Fatalf("expected one resolved overload, got %+v", analysis.Overloads)
+	}
+	for _, suggestion := range analysis.Overloads[0].Stagger {
+		if suggestion.ApplianceID == 1 {
+			t.Fatalf("priority appliance should not be moved")
+		}
+		if suggestion.MoveTo != "18:00" {
+			t.Fatalf("expected %s to move to 18:00, got %q", suggestion.Name, suggestion.MoveTo)
+		}
+	}
+	if analysis.Upgrade == nil || analysis.Upgrade.Tariff != "R-1/2200" {
+		t.Fatalf("expected upgrade option R-1/2200, got %+v", analysis.Upgrade)
+	}
+	if analysis.Recommendation != entity.PowerLimitStagger {
+		t.Fatalf("expected stagger recommendation on a flat tariff, got %s", analysis.Recommendation)
+	}
+}
+
+func TestAnalyzePowerLimit_RecommendsUpgrade(t *testing.T) {
+	appliances := []entity.ApplianceResponse{
+		{ID: 1, Name: "Pompa Air", Power: 850, Priority: true},
+		{ID: 2, Name: "Kulkas", Power: 150, Priority: true},
+	}
+	usage := newHourlyUsage()
+	usage[6][1], usage[6][2] = 850, 150
+
+	analysis := AnalyzePowerLimit(scheduleDay, usage, appliances, catalogueSchedule("R-1/900"), defaultCatalogue(), PowerLimitOptions{VALimit: 900, ConnectionFeePerVA: DefaultConnectionFeePerVA})
+
+	// 1000 W / 0,85 ≈ 1176 VA dan kedua appliance prioritas sehingga tidak dapat dipindahkan
+	if len(analysis.Overloads) != 1 || analysis.Overloads[0].Resolved {
+		t.Fatalf("expected one unresolved overload, got %+v", analysis.Overloads)
+	}
+	if analysis.Recommendation != entity.PowerLimitUpgrade || analysis.Upgrade.Tariff != "R-1/1300" {
+		t.Fatalf("expected upgrade to R-1/1300, got %s %+v", analysis.Recommendation, analysis.Upgrade)
+	}
+	if analysis.Upgrade.ConnectionFee != 400*DefaultConnectionFeePerVA {
+		t.Fatalf("expected connection fee for 400 VA, got %v", analysis.Upgrade.ConnectionFee)
+	}
+}
+
+func TestAnalyzePowerLimit_WithinLimit(t *testing.T) {
+	usage := newHourlyUsage()
+	usage[12][1] = 300
+	analysis := AnalyzePowerLimit(scheduleDay, usage, nil, FlatTariff(1000), nil, PowerLimitOptions{VALimit: 900, PowerFactor: 1})
+
+	if analysis.Recommendation != entity.PowerLimitOK || len(analysis.Overloads) != 0 || analysis.Upgrade != nil {
+		t.Fatalf("expected no overloads, got %+v", analysis)
+	}
+}
+
+func TestAnalyzePowerLimit_UpgradeWithinVARange(t *testing.T) {
+	appliances := []entity.ApplianceResponse{{ID: 1, Name: "Oven", Power: 4500, Priority: true}}
+	usage := newHourlyUsage()
+	usage[12][1] = 4500
+
+	// Puncak 4500 VA melebihi 4400 VA sehingga R-2 (3500–5500 VA) dipasang pada 5500 VA, bukan R-3
+	analysis := AnalyzePowerLimit(scheduleDay, usage, appliances, catalogueSchedule("R-1/2200"), defaultCatalogue(), PowerLimitOptions{VALimit: 2200, PowerFactor: 1, ConnectionFeePerVA: DefaultConnectionFeePerVA})
+	if analysis.Upgrade == nil || analysis.Upgrade.Tariff != "R-2" || analysis.Upgrade.VALimit != 5500 {
+		t.Fatalf("expected upgrade to R-2 at 5500 VA, got %+v", analysis.Upgrade)
+	}
+	if analysis.Upgrade.ConnectionFee != 3300*DefaultConnectionFeePerVA {
+		t.Fatalf("expected connection fee for 3300 VA, got %v", analysis.Upgrade.ConnectionFee)
+	}
+
+	for va, want := range map[float64]int{3000: 3500, 3600: 4400, 5500: 5500} {
+		if got, ok := upgradeVA(catalogueSchedule("R-2")[0], va); !ok || got != want {
+			t.Errorf("upgradeVA(R-2, %v) = %v, %v; want %v", va, got, ok, want)
+		}
+	}
+	if _, ok := upgradeVA(catalogueSchedule("R-2")[0], 5600); ok {
+		t.Error("R-2 cannot hold more than 5500 VA")
+	}
+}
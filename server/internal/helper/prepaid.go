@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"math"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// DefaultForecastWindowDays adalah jumlah hari pemakaian terakhir yang dirata-rata untuk forecast prabayar
+const DefaultForecastWindowDays = 14
+
+// ForecastPrepaid menghitung sisa saldo kWh dari total token yang dibeli dikurangi konsumsi sejak pembelian pertama,
+// lalu memperkirakan kapan saldo habis dengan rata-rata pemakaian harian windowDays hari terakhir.
+// Perhitungan dimulai dari data terbaru (reading atau pembelian terakhir), karena konsumsi setelahnya belum diketahui.
+func ForecastPrepaid(purchases []entity.TokenPurchase, readings []entity.Reading, windowDays int, threshold *float64) entity.PrepaidForecast {
+	forecast := entity.PrepaidForecast{WindowDays: windowDays, AlertThresholdKWh: threshold}
+	if len(purchases) == 0 {
+		return forecast
+	}
+
+	firstPurchase := purchases[0].PurchasedAt
+	for _, purchase := range purchases {
+		forecast.CreditedKWh += purchase.KWh
+		if purchase.PurchasedAt.Before(firstPurchase) {
+			firstPurchase = purchase.PurchasedAt
+		}
+		if purchase.PurchasedAt.After(forecast.AsOf) {
+			forecast.AsOf = purchase.PurchasedAt
+		}
+	}
+
+	for _, reading := range readings {
+		if reading.Timestamp.Before(firstPurchase) {
+			continue
+		}
+		forecast.ConsumedKWh += reading.Energy
+		if reading.Timestamp.After(forecast.AsOf) {
+			forecast.AsOf = reading.Timestamp
+		}
+	}
+
+	forecast.BalanceKWh = forecast.CreditedKWh - forecast.ConsumedKWh
+	if forecast.BalanceKWh <= 0 {
+		forecast.BalanceKWh, forecast.Depleted = 0, true
+	}
+
+	// Konsumsi baru tercatat sejak pembelian pertama, sehingga window tidak dihitung sebelum tanggal itu (minimal satu hari)
+	windowStart := forecast.AsOf.AddDate(0, 0, -windowDays)
+	if firstPurchase.After(windowStart) {
+		windowStart = firstPurchase
+	}
+	var windowEnergy float64
+	for _, reading := range readings {
+		if !reading.Timestamp.Before(windowStart) {
+			windowEnergy += reading.Energy
+		}
+	}
+	spanDays := math.Max(forecast.AsOf.Sub(windowStart).Hours()/24, 1)
+	forecast.AverageDailyKWh = windowEnergy / spanDays
+
+	if threshold != nil {
+		forecast.Alert = forecast.BalanceKWh <= *threshold
+	}
+	if forecast.AverageDailyKWh <= 0 {
+		return forecast
+	}
+
+	days := forecast.BalanceKWh / forecast.AverageDailyKWh
+	depletion := forecast.AsOf.Add(time.Duration(days * 24 * float64(time.Hour)))
+	forecast.DaysRemaining, forecast.DepletionDate = &days, &depletion
+
+	if threshold != nil {
+		alertDays := math.Max((forecast.BalanceKWh-*threshold)/forecast.AverageDailyKWh, 0)
+		alertDate := forecast.AsOf.Add(time.Duration(alertDays * 24 * float64(time.Hour)))
+		forecast.AlertDate = &alertDate
+	}
+	return forecast
+}
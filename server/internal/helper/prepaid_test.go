@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestForecastPrepaid(t *testing.T) {
+	start := time.Date(2024, 6, 1, 8, 0, 0, 0, jakarta)
+	purchases := []entity.TokenPurchase{
+		{PurchasedAt: start, Amount: 100000, KWh: 60},
+		{PurchasedAt: start.AddDate(0, 0, 5), Amount: 50000, KWh: 30},
+	}
+
+	// 4 kWh per hari selama 10 hari, ditambah reading sebelum pembelian pertama yang harus diabaikan
+	readings := []entity.Reading{{Timestamp: start.Add(-time.Hour), Energy: 50}}
+	for day := 1; day <= 10; day++ {
+		readings = append(readings, entity.Reading{Timestamp: start.AddDate(0, 0, day), Energy: 4})
+	}
+
+	threshold := 10.0
+	forecast := ForecastPrepaid(purchases, readings, 14, &threshold)
+
+	if forecast.CreditedKWh != 90 || forecast.ConsumedKWh != 40 || forecast.BalanceKWh != 50 {
+		t.Fatalf("unexpected balance: %+v", forecast)
+	}
+	if !forecast.AsOf.Equal(start.AddDate(0, 0, 10)) {
+		t.Fatalf("expected forecast as of last reading, got %s", forecast.AsOf)
+	}
+	if math.Abs(forecast.AverageDailyKWh-4) > 1e-9 {
+		t.Fatalf("expected average 4 kWh/day, got %v", forecast.AverageDailyKWh)
+	}
+	if forecast.DaysRemaining == nil || math.Abs(*forecast.DaysRemaining-12.5) > 1e-9 {
+		t.Fatalf("expected 12.5 days remaining, got %v", forecast.DaysRemaining)
+	}
+	if want := forecast.AsOf.Add(300 * time.Hour); !forecast.DepletionDate.Equal(want) {
+		t.Fatalf("expected depletion at %s, got %s", want, forecast.DepletionDate)
+	}
+	if forecast.Alert || forecast.AlertDate == nil || !forecast.AlertDate.Equal(forecast.AsOf.AddDate(0, 0, 10)) {
+		t.Fatalf("expected alert 10 days from as_of, got alert=%v date=%v", forecast.Alert, forecast.AlertDate)
+	}
+}
+
+func TestForecastPrepaid_Depleted(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, jakarta)
+	purchases := []entity.TokenPurchase{{PurchasedAt: start, Amount: 20000, KWh: 10}}
+	readings := []entity.Reading{{Timestamp: start.Add(2 * time.Hour), Energy: 12}}
+
+	forecast := ForecastPrepaid(purchases, readings, 14, nil)
+	if !forecast.Depleted || forecast.BalanceKWh != 0 || *forecast.DaysRemaining != 0 {
+		t.Fatalf("expected depleted balance, got %+v", forecast)
+	}
+	if forecast.AlertDate != nil || forecast.Alert {
+		t.Fatalf("expected no alert without threshold, got %+v", forecast)
+	}
+}
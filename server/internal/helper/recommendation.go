@@ -0,0 +1,224 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// Bahasa teks rekomendasi; bahasa yang tidak dikenal memakai LanguageID
+const (
+	LanguageID = "id"
+	LanguageEN = "en"
+)
+
+// Message key rekomendasi
+const (
+	MessageDailyOveruse           = "daily.overuse"
+	MessageDailyWithinTarget      = "daily.within_target"
+	MessageDailyPriorityRemaining = "daily.priority_remaining"
+	MessageDailyReduce            = "daily.reduce"
+)
+
+// budgetMessageKey memakai alasan alokasi sebagai bagian message key, mis. "budget.reduced_budget"
+func budgetMessageKey(reason string) string {
+	return "budget." + reason
+}
+
+// recommendationMessages adalah template teks per bahasa; {param} diganti dengan nilai dari Params
+var recommendationMessages = map[string]map[string]string{
+	LanguageID: {
+		budgetMessageKey(entity.AllocationUnknownPower):    "Daya {name} tidak diketahui sehingga pemakaiannya tidak dihitung dalam anggaran.",
+		budgetMessageKey(entity.AllocationUnchanged):       "{name} dapat digunakan seperti biasa ({recommended_hours} jam/hari).",
+		budgetMessageKey(entity.AllocationBelowMinimum):    "Anggaran tidak cukup untuk pemakaian minimal {name} ({min_hours} jam/hari), dikurangi menjadi {recommended_hours} jam/hari.",
+		budgetMessageKey(entity.AllocationPriorityMinimum): "{name} adalah perangkat prioritas dan dipertahankan pada pemakaian minimal {recommended_hours} jam/hari.",
+		budgetMessageKey(entity.AllocationReducedBudget):   "Pemakaian {name} dikurangi dari {desired_hours} menjadi {recommended_hours} jam/hari agar sesuai anggaran.",
+		MessageDailyOveruse:                                "WARNING: {name} telah melebihi target harian!",
+		MessageDailyWithinTarget:                           "{name} dalam batas target harian. (Penggunaan: {usage} jam, Target: {target} jam)",
+		MessageDailyPriorityRemaining:                      "{name} adalah perangkat prioritas. Gunakan selama {hours_remaining} jam lagi hari ini tanpa melebihi target harian {target} jam (estimasi biaya IDR {cost}).",
+		MessageDailyReduce:                                 "Kurangi penggunaan {name} agar tidak melebihi target harian {target} jam. Potensi penghematan jika tidak digunakan lagi hari ini: IDR {savings}.",
+	},
+	LanguageEN: {
+		budgetMessageKey(entity.AllocationUnknownPower):    "The power rating of {name} is unknown, so its usage is not counted against the budget.",
+		budgetMessageKey(entity.AllocationUnchanged):       "{name} can be used as usual ({recommended_hours} hours/day).",
+		budgetMessageKey(entity.AllocationBelowMinimum):    "The budget cannot cover the minimum use of {name} ({min_hours} hours/day); reduced to {recommended_hours} hours/day.",
+		budgetMessageKey(entity.AllocationPriorityMinimum): "{name} is a priority appliance and is kept at its minimum use of {recommended_hours} hours/day.",
+		budgetMessageKey(entity.AllocationReducedBudget):   "Use of {name} is reduced from {desired_hours} to {recommended_hours} hours/day to fit the budget.",
+		MessageDailyOveruse:                                "WARNING: {name} has exceeded its daily target!",
+		MessageDailyWithinTarget:                           "{name} is within its daily target. (Usage: {usage} hours, Target: {target} hours)",
+		MessageDailyPriorityRemaining:                      "{name} is a priority appliance. Use it for {hours_remaining} more hours today without exceeding the {target} hour daily target (estimated cost IDR {cost}).",
+		MessageDailyReduce:                                 "Reduce use of {name} to stay within the {target} hour daily target. Potential savings if it is not used again today: IDR {savings}.",
+	},
+}
+
+// RenderRecommendation membentuk teks rekomendasi dalam bahasa lang. Message key tanpa template
+// dikembalikan apa adanya agar client tetap dapat menampilkan sesuatu.
+func RenderRecommendation(recommendation entity.Recommendation, lang string) string {
+	messages, ok := recommendationMessages[lang]
+	if !ok {
+		messages = recommendationMessages[LanguageID]
+	}
+	message, ok := messages[recommendation.MessageKey]
+	if !ok {
+		return recommendation.MessageKey
+	}
+	for key, value := range recommendation.Params {
+		message = strings.ReplaceAll(message, "{"+key+"}", formatParam(value))
+	}
+	return message
+}
+
+// RenderRecommendations mengisi Message setiap rekomendasi tanpa mengubah slice asalnya
+func RenderRecommendations(recommendations []entity.Recommendation, lang string) []entity.Recommendation {
+	rendered := make([]entity.Recommendation, len(recommendations))
+	for i, recommendation := range recommendations {
+		recommendation.Message = RenderRecommendation(recommendation, lang)
+		rendered[i] = recommendation
+	}
+	return rendered
+}
+
+// formatParam menulis angka dengan dua desimal seperti teks rekomendasi sebelumnya
+func formatParam(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// SortRecommendations mengurutkan rekomendasi dari severity tertinggi, lalu penghematan rupiah terbesar
+func SortRecommendations(recommendations []entity.Recommendation) {
+	rank := map[string]int{entity.SeverityCritical: 0, entity.SeverityWarning: 1, entity.SeverityInfo: 2}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if rank[a.Severity] != rank[b.Severity] {
+			return rank[a.Severity] < rank[b.Severity]
+		}
+		return a.SavingsIDR > b.SavingsIDR
+	})
+}
+
+// BudgetRecommendation mengubah satu alokasi anggaran bulanan menjadi rekomendasi terstruktur
+func BudgetRecommendation(allocation entity.BudgetAllocation, daysInMonth int, tarif float64) entity.Recommendation {
+	severity := entity.SeverityWarning
+	switch allocation.Reason {
+	case entity.AllocationUnchanged, entity.AllocationUnknownPower:
+		severity = entity.SeverityInfo
+	case entity.AllocationBelowMinimum:
+		severity = entity.SeverityCritical
+	}
+
+	savingsKWh := 0.0
+	if allocation.Reason != entity.AllocationUnknownPower {
+		savingsKWh = (allocation.DesiredHours - allocation.RecommendedHours) * allocation.KWhPerHour * float64(daysInMonth)
+	}
+	return entity.Recommendation{
+		Kind:          entity.RecommendationKindBudget,
+		ApplianceID:   allocation.ApplianceID,
+		ApplianceName: allocation.Name,
+		Severity:      severity,
+		SavingsKWh:    savingsKWh,
+		SavingsIDR:    savingsKWh * tarif,
+		Schedule:      allocation.Schedule,
+		MessageKey:    budgetMessageKey(allocation.Reason),
+		Params: map[string]interface{}{
+			"name":              allocation.Name,
+			"desired_hours":     allocation.DesiredHours,
+			"min_hours":         allocation.MinHours,
+			"recommended_hours": allocation.RecommendedHours,
+		},
+	}
+}
+
+// BudgetRecommendations membentuk rekomendasi untuk setiap alokasi hasil OptimizeMonthlyBudget
+func BudgetRecommendations(plan entity.BudgetPlan) []entity.Recommendation {
+	recommendations := make([]entity.Recommendation, 0, len(plan.Allocations))
+	for _, allocation := range plan.Allocations {
+		recommendations = append(recommendations, BudgetRecommendation(allocation, plan.DaysInMonth, plan.Tarif))
+	}
+	return recommendations
+}
+
+// DailyRecommendations membandingkan pemakaian hari ini dengan target harian setiap appliance. Setiap appliance
+// mendapat status target (daily_target); appliance yang masih memiliki sisa jam juga mendapat saran pemakaian
+// (daily_usage) dengan penghematan jika tidak dipakai lagi hari ini.
+func DailyRecommendations(appliances []entity.ApplianceResponse, tariff float64) []entity.Recommendation {
+	recommendations := []entity.Recommendation{}
+	for _, appliance := range appliances {
+		recommendations = append(recommendations, dailyTargetRecommendation(appliance, tariff))
+		if usage, ok := dailyUsageRecommendation(appliance, tariff); ok {
+			recommendations = append(recommendations, usage)
+		}
+	}
+	return recommendations
+}
+
+func dailyTargetRecommendation(appliance entity.ApplianceResponse, tariff float64) entity.Recommendation {
+	kWhPerHour := float64(appliance.Power) / 1000
+	recommendation := entity.Recommendation{
+		Kind:          entity.RecommendationKindDailyTarget,
+		ApplianceID:   appliance.ID,
+		ApplianceName: appliance.Name,
+		Severity:      entity.SeverityInfo,
+		MessageKey:    MessageDailyWithinTarget,
+		Params: map[string]interface{}{
+			"name":   appliance.Name,
+			"usage":  appliance.UsageToday,
+			"target": appliance.DailyUseTarget,
+			"cost":   kWhPerHour * appliance.UsageToday * tariff,
+		},
+	}
+	if appliance.UsageToday > appliance.DailyUseTarget {
+		recommendation.Severity = entity.SeverityCritical
+		recommendation.MessageKey = MessageDailyOveruse
+		recommendation.SavingsKWh = (appliance.UsageToday - appliance.DailyUseTarget) * kWhPerHour
+		recommendation.SavingsIDR = recommendation.SavingsKWh * tariff
+	}
+	return recommendation
+}
+
+// dailyUsageRecommendation bernilai false jika appliance tidak memiliki sisa jam terhadap target harian
+func dailyUsageRecommendation(appliance entity.ApplianceResponse, tariff float64) (entity.Recommendation, bool) {
+	hoursRemaining := appliance.DailyUseTarget - appliance.UsageToday
+	if hoursRemaining <= 0 {
+		return entity.Recommendation{}, false
+	}
+
+	kWhPerHour := float64(appliance.Power) / 1000
+	recommendation := entity.Recommendation{
+		Kind:          entity.RecommendationKindDailyUsage,
+		ApplianceID:   appliance.ID,
+		ApplianceName: appliance.Name,
+		Severity:      entity.SeverityInfo,
+		MessageKey:    MessageDailyPriorityRemaining,
+		Params: map[string]interface{}{
+			"name":            appliance.Name,
+			"hours_remaining": hoursRemaining,
+			"target":          appliance.DailyUseTarget,
+			"cost":            kWhPerHour * hoursRemaining * tariff,
+		},
+	}
+	if !appliance.Priority {
+		recommendation.Severity = entity.SeverityWarning
+		recommendation.MessageKey = MessageDailyReduce
+		recommendation.SavingsKWh = kWhPerHour * hoursRemaining
+		recommendation.SavingsIDR = recommendation.SavingsKWh * tariff
+		recommendation.Params["savings"] = recommendation.SavingsIDR
+	}
+	return recommendation, true
+}
+
+// runLabels menulis jadwal sebagai label rentang waktu, mis. "18:00–24:00"
+func runLabels(runs []entity.ScheduleRun) []string {
+	labels := make([]string, 0, len(runs))
+	for _, run := range runs {
+		labels = append(labels, run.Start+"–"+run.End)
+	}
+	return labels
+}
@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestDailyRecommendations(t *testing.T) {
+	appliances := []entity.ApplianceResponse{
+		{ID: 1, Name: "AC", Power: 1000, UsageToday: 6, DailyUseTarget: 4},
+		{ID: 2, Name: "TV", Power: 100, UsageToday: 1, DailyUseTarget: 3},
+		{ID: 3, Name: "Kulkas", Power: 100, UsageToday: 10, DailyUseTarget: 24, Priority: true},
+	}
+	recommendations := DailyRecommendations(appliances, 1000)
+
+	if len(recommendations) != 5 {
+		t.Fatalf("expected 3 target statuses and 2 usage suggestions, got %d", len(recommendations))
+	}
+	overuse := recommendations[0]
+	if overuse.Kind != entity.RecommendationKindDailyTarget || overuse.Severity != entity.SeverityCritical || overuse.SavingsKWh != 2 || overuse.SavingsIDR != 2000 {
+		t.Fatalf("unexpected overuse recommendation: %+v", overuse)
+	}
+	reduce := recommendations[2]
+	if reduce.Kind != entity.RecommendationKindDailyUsage || reduce.MessageKey != MessageDailyReduce || reduce.SavingsIDR != 200 {
+		t.Fatalf("unexpected reduce recommendation: %+v", reduce)
+	}
+	if priority := recommendations[4]; priority.MessageKey != MessageDailyPriorityRemaining || priority.SavingsIDR != 0 {
+		t.Fatalf("priority appliance should not be told to stop: %+v", priority)
+	}
+
+	SortRecommendations(recommendations)
+	if recommendations[0].ApplianceID != 1 || recommendations[1].ApplianceID != 2 {
+		t.Fatalf("expected critical first, then warnings: %+v", recommendations)
+	}
+}
+
+func TestRenderRecommendation_AfterPersisting(t *testing.T) {
+	recommendation := BudgetRecommendation(entity.BudgetAllocation{
+		ApplianceID:      1,
+		Name:             "AC",
+		KWhPerHour:       1,
+		DesiredHours:     8,
+		RecommendedHours: 5.5,
+		Reason:           entity.AllocationReducedBudget,
+		Schedule:         []entity.ScheduleRun{{Start: "00:00", End: "05:30", Hours: 5.5, Cost: 5500}},
+	}, 30, 1000)
+
+	if recommendation.SavingsKWh != 75 || recommendation.SavingsIDR != 75000 || recommendation.Severity != entity.SeverityWarning {
+		t.Fatalf("unexpected budget recommendation: %+v", recommendation)
+	}
+	if len(recommendation.Schedule) != 1 || recommendation.Schedule[0].Start != "00:00" || recommendation.Schedule[0].Hours != 5.5 {
+		t.Fatalf("unexpected schedule: %+v", recommendation.Schedule)
+	}
+
+	// Rekomendasi disimpan sebagai JSON sehingga teks harus tetap sama setelah dibaca ulang
+	data, err := json.Marshal(recommendation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored entity.Recommendation
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang, want string
+	}{
+		{LanguageID, "Pemakaian AC dikurangi dari 8.00 menjadi 5.50 jam/hari agar sesuai anggaran."},
+		{LanguageEN, "Use of AC is reduced from 8.00 to 5.50 hours/day to fit the budget."},
+		{"fr", "Pemakaian AC dikurangi dari 8.00 menjadi 5.50 jam/hari agar sesuai anggaran."},
+	}
+	for _, tt := range tests {
+		if got := RenderRecommendation(stored, tt.lang); got != tt.want {
+			t.Errorf("RenderRecommendation(%s) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
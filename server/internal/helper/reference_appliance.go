@@ -0,0 +1,160 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// MaxReplacementOptions membatasi jumlah produk pengganti yang disarankan per appliance
+const MaxReplacementOptions = 3
+
+// catalogueColumns adalah nama header yang dikenali untuk setiap kolom katalog (tidak peka huruf besar)
+var catalogueColumns = map[string][]string{
+	"type":        {"type", "jenis", "tipe"},
+	"brand":       {"brand", "merek", "merk"},
+	"model":       {"model"},
+	"power":       {"power", "watt", "daya"},
+	"star_rating": {"star_rating", "stars", "star", "bintang"},
+	"price":       {"price", "harga"},
+}
+
+// ValidateReferenceAppliance memeriksa satu produk katalog
+func ValidateReferenceAppliance(req entity.ReferenceApplianceRequest) error {
+	switch {
+	case strings.TrimSpace(req.Type) == "":
+		return errors.New("type is required")
+	case req.Power <= 0:
+		return errors.New("power must be greater than 0")
+	case req.StarRating < 0 || req.StarRating > 5:
+		return errors.New("star_rating must be between 1 and 5, or 0 if unrated")
+	case req.Price < 0:
+		return errors.New("price cannot be negative")
+	}
+	return nil
+}
+
+// ParseReferenceCatalogue membaca katalog dari CSV dengan kolom type, brand, model, power, star_rating, dan price.
+// Baris yang tidak valid dilaporkan sebagai RowError tanpa menggagalkan baris lainnya.
+func ParseReferenceCatalogue(file *CSVFile) ([]entity.ReferenceApplianceRequest, []entity.RowError, error) {
+	columns := make(map[string]int, len(catalogueColumns))
+	for key, names := range catalogueColumns {
+		columns[key] = -1
+		for i, header := range file.Header {
+			for _, name := range names {
+				if strings.EqualFold(strings.TrimSpace(header), name) {
+					columns[key] = i
+				}
+			}
+		}
+	}
+	for _, required := range []string{"type", "power", "price"} {
+		if columns[required] < 0 {
+			return nil, nil, fmt.Errorf("column %q is required", required)
+		}
+	}
+
+	var catalogue []entity.ReferenceApplianceRequest
+	rowErrors := append([]entity.RowError{}, file.RowErrors...)
+	for i, record := range file.Records {
+		line := i + 2
+		if i < len(file.Lines) {
+			line = file.Lines[i]
+		}
+		get := func(column string) string {
+			idx := columns[column]
+			if idx >= 0 && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		reject := func(column, message string) {
+			rowErrors = append(rowErrors, entity.RowError{Line: line, Column: file.Header[columns[column]], Message: message})
+		}
+
+		power, err := parseQuantity(get("power"), quantityPower, UnitW, "", "")
+		if err != nil {
+			reject("power", fmt.Sprintf("invalid power value %q", get("power")))
+			continue
+		}
+		price, err := parseCurrency(get("price"), "", "")
+		if err != nil {
+			reject("price", fmt.Sprintf("invalid price value %q", get("price")))
+			continue
+		}
+		stars := 0
+		if value := get("star_rating"); value != "" {
+			if stars, err = strconv.Atoi(strings.TrimRight(value, "*★ ")); err != nil {
+				reject("star_rating", fmt.Sprintf("invalid star rating %q", value))
+				continue
+			}
+		}
+
+		req := entity.ReferenceApplianceRequest{
+			Type:       get("type"),
+			Brand:      get("brand"),
+			Model:      get("model"),
+			Power:      int(math.Round(power)),
+			StarRating: stars,
+			Price:      price,
+		}
+		if err := ValidateReferenceAppliance(req); err != nil {
+			column := strings.SplitN(err.Error(), " ", 2)[0]
+			reject(column, err.Error())
+			continue
+		}
+		catalogue = append(catalogue, req)
+	}
+	return catalogue, rowErrors, nil
+}
+
+// ReplacementOptions membandingkan appliance dengan produk katalog sejenis yang dayanya lebih kecil.
+// Penghematan tahunan = jam pemakaian per hari × selisih daya × 365 hari × harga per kWh; balik modal
+// adalah harga produk dibagi penghematan tahunan. Hasil terurut dari balik modal tercepat.
+func ReplacementOptions(appliance entity.ApplianceResponse, hoursPerDay, pricePerKWh float64, catalogue []entity.ReferenceApplianceResponse) []entity.ReplacementOption {
+	options := []entity.ReplacementOption{}
+	for _, reference := range catalogue {
+		if !strings.EqualFold(strings.TrimSpace(reference.Type), strings.TrimSpace(appliance.Type)) || reference.Power >= appliance.Power {
+			continue
+		}
+
+		annualKWh := hoursPerDay * float64(reference.Power) / 1000 * 365
+		kWhSavings := hoursPerDay * float64(appliance.Power-reference.Power) / 1000 * 365
+		option := entity.ReplacementOption{
+			ReferenceID:      reference.ID,
+			Brand:            reference.Brand,
+			Model:            reference.Model,
+			Power:            reference.Power,
+			StarRating:       reference.StarRating,
+			Price:            reference.Price,
+			AnnualKWh:        annualKWh,
+			AnnualKWhSavings: kWhSavings,
+			AnnualSavings:    kWhSavings * pricePerKWh,
+		}
+		if option.AnnualSavings > 0 {
+			payback := reference.Price / option.AnnualSavings
+			option.PaybackYears = &payback
+		}
+		options = append(options, option)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i].PaybackYears, options[j].PaybackYears
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return options[i].AnnualSavings > options[j].AnnualSavings
+	})
+	if len(options) > MaxReplacementOptions {
+		options = options[:MaxReplacementOptions]
+	}
+	return options
+}
@@ -0,0 +1,73 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestParseReferenceCatalogue(t *testing.T) {
+	data := "Jenis;Merek;Model;Daya;Bintang;Harga\n" +
+		"AC;Daikin;FTKQ25;650 W;5;Rp 4.250.000\n" +
+		"AC;Sharp;AH-A5;0,9 kW;4;3.100.000\n" +
+		"Kulkas;LG;GN-B;abc;3;2.500.000\n" +
+		";Polytron;X;100;6;100\n" +
+		"Kipas;Cosmos;16-SDB;45 W;3;Rp 899.000\n"
+	file, err := ReadCSVFile(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	catalogue, rowErrors, err := ParseReferenceCatalogue(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalogue) != 3 {
+		t.Fatalf("expected 3 valid rows, got %+v", catalogue)
+	}
+	if catalogue[0].Power != 650 || catalogue[0].StarRating != 5 || catalogue[0].Price != 4250000 {
+		t.Errorf("unexpected first row: %+v", catalogue[0])
+	}
+	if catalogue[1].Power != 900 || catalogue[1].Price != 3100000 {
+		t.Errorf("unexpected second row: %+v", catalogue[1])
+	}
+	if catalogue[2].Price != 899000 {
+		t.Errorf("expected \"Rp 899.000\" to be read as 899000, got %v", catalogue[2].Price)
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Line != 4 || rowErrors[0].Column != "Daya" || rowErrors[1].Column != "Jenis" {
+		t.Errorf("unexpected row errors: %+v", rowErrors)
+	}
+
+	missing, _ := ReadCSVFile(strings.NewReader("type,brand\nAC,Daikin\n"))
+	if _, _, err := ParseReferenceCatalogue(missing); err == nil {
+		t.Error("expected error for catalogue without power and price columns")
+	}
+}
+
+func TestReplacementOptions(t *testing.T) {
+	appliance := entity.ApplianceResponse{ID: 1, Name: "AC Kamar", Type: "ac", Power: 1000}
+	catalogue := []entity.ReferenceApplianceResponse{
+		{ID: 1, Type: "AC", Brand: "A", Power: 600, Price: 5840000},
+		{ID: 2, Type: "AC", Brand: "B", Power: 800, Price: 1460000},
+		{ID: 3, Type: "AC", Brand: "C", Power: 1200, Price: 1000000},
+		{ID: 4, Type: "Kulkas", Brand: "D", Power: 100, Price: 1000000},
+	}
+
+	options := ReplacementOptions(appliance, 8, 1000, catalogue)
+	if len(options) != 2 {
+		t.Fatalf("expected only cheaper-to-run options of the same type, got %+v", options)
+	}
+	// B: 8 jam × 0,2 kW × 365 = 584 kWh = Rp584.000/tahun, balik modal 2,5 tahun
+	best := options[0]
+	if best.ReferenceID != 2 || best.AnnualKWhSavings != 584 || best.AnnualSavings != 584000 || best.PaybackYears == nil || *best.PaybackYears != 2.5 {
+		t.Fatalf("unexpected best option: %+v", best)
+	}
+	if options[1].ReferenceID != 1 || *options[1].PaybackYears != 5 {
+		t.Fatalf("unexpected second option: %+v", options[1])
+	}
+
+	if idle := ReplacementOptions(appliance, 0, 1000, catalogue); idle[0].PaybackYears != nil {
+		t.Errorf("unused appliance should have no payback, got %+v", idle[0])
+	}
+}
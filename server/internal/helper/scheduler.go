@@ -0,0 +1,281 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// DefaultPowerFactor adalah faktor daya rata-rata beban rumah tangga untuk mengubah VA menjadi watt
+	DefaultPowerFactor = 0.85
+	// DefaultScheduleResolution adalah panjang satu slot jadwal dalam menit
+	DefaultScheduleResolution = 15
+)
+
+// ScheduleTask adalah appliance yang akan dijadwalkan beserta batasannya. Hours dibulatkan ke atas
+// ke kelipatan slot; MinRunMinutes adalah lama minimal setiap kali appliance dinyalakan.
+type ScheduleTask struct {
+	Appliance     entity.ApplianceResponse
+	Hours         float64
+	Continuous    bool
+	MinRunMinutes int
+	AllowedHours  []entity.ScheduleWindow
+}
+
+// ScheduleOptions mengatur resolusi slot dan batas daya tersambung; VALimit 0 berarti tanpa batas
+type ScheduleOptions struct {
+	ResolutionMinutes int
+	VALimit           int
+	PowerFactor       float64
+}
+
+// ContractedVA memperkirakan daya tersambung dari tarif: batas bawah golongan, atau batas atas jika
+// golongan tidak memiliki batas bawah. Bernilai 0 jika tarif tidak membatasi daya.
+func ContractedVA(tariff entity.TariffResponse) int {
+	if tariff.MinVA > 0 {
+		return tariff.MinVA
+	}
+	return tariff.MaxVA
+}
+
+// BuildDaySchedule menyusun jadwal 24 jam (WIB) untuk tanggal day. Appliance prioritas dan yang harus
+// menyala tanpa jeda dijadwalkan lebih dulu, lalu yang berdaya besar. Setiap appliance mendapat slot
+// dengan harga termurah yang masih diizinkan dan tidak membuat beban total melewati VALimit × PowerFactor.
+// Harga per slot diambil dari model tarif sehingga window WBP pada tarif tou dihindari bila memungkinkan.
+// Slot yang sama murah dibagi merata (lihat preferredSlot) sehingga tarif flat tidak menumpuk jadwal di 00:00.
+func BuildDaySchedule(day time.Time, tasks []ScheduleTask, tariff TariffSchedule, options ScheduleOptions) entity.DaySchedule {
+	if options.ResolutionMinutes <= 0 {
+		options.ResolutionMinutes = DefaultScheduleResolution
+	}
+	if options.PowerFactor <= 0 {
+		options.PowerFactor = DefaultPowerFactor
+	}
+
+	day = day.In(jakarta)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, jakarta)
+	resolution := options.ResolutionMinutes
+	slotCount := 24 * 60 / resolution
+	slotHours := float64(resolution) / 60
+
+	result := entity.DaySchedule{
+		Date:              start.Format("2006-01-02"),
+		Tariff:            tariff.VersionAt(start).Code,
+		ResolutionMinutes: resolution,
+		VALimit:           options.VALimit,
+		PowerFactor:       options.PowerFactor,
+		Appliances:        make([]entity.ApplianceSchedule, len(tasks)),
+		Slots:             make([]entity.ScheduleSlot, slotCount),
+	}
+	if options.VALimit > 0 {
+		result.CapacityW = float64(options.VALimit) * options.PowerFactor
+	}
+
+	// Harga 1 kWh pada tiap slot; tarif block memakai harga blok pertama karena harganya sama sepanjang hari
+	prices := make([]float64, slotCount)
+	load := make([]float64, slotCount)
+	for i := range prices {
+		prices[i] = tariff.Cost(start.Add(time.Duration(i*resolution)*time.Minute), 1, 0)
+		result.Slots[i] = entity.ScheduleSlot{Start: slotLabel(i, resolution), PricePerKWh: prices[i], ApplianceIDs: []uint{}}
+	}
+
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := tasks[order[i]], tasks[order[j]]
+		if a.Appliance.Priority != b.Appliance.Priority {
+			return a.Appliance.Priority
+		}
+		if a.Continuous != b.Continuous {
+			return a.Continuous
+		}
+		return a.Appliance.Power > b.Appliance.Power
+	})
+
+	for rank, index := range order {
+		task := tasks[index]
+		appliance := task.Appliance
+		power := float64(appliance.Power)
+		hours := math.Min(math.Max(task.Hours, 0), 24)
+		schedule := entity.ApplianceSchedule{
+			ApplianceID:    appliance.ID,
+			Name:           appliance.Name,
+			Power:          appliance.Power,
+			Priority:       appliance.Priority,
+			Continuous:     task.Continuous,
+			RequestedHours: hours,
+			Runs:           []entity.ScheduleRun{},
+		}
+
+		switch {
+		case power <= 0:
+			schedule.Message = fmt.Sprintf("Daya %s tidak diketahui sehingga tidak dapat dijadwalkan.", appliance.Name)
+		case hours <= 0:
+			schedule.Message = fmt.Sprintf("%s tidak perlu dijadwalkan hari ini.", appliance.Name)
+		case result.CapacityW > 0 && power > result.CapacityW:
+			schedule.Message = fmt.Sprintf("Daya %s (%d W) melebihi daya tersambung (%.0f W).", appliance.Name, appliance.Power, result.CapacityW)
+		default:
+			need := int(math.Ceil(hours*60/float64(resolution) - 1e-9))
+			preferred := preferredSlot(task, rank, len(order), slotCount, resolution)
+			used := placeTask(task, need, resolution, preferred, prices, load, result.CapacityW)
+			scheduled := 0
+			for i, on := range used {
+				if !on {
+					continue
+				}
+				scheduled++
+				load[i] += power
+				result.Slots[i].ApplianceIDs = append(result.Slots[i].ApplianceIDs, appliance.ID)
+			}
+
+			kWhPerSlot := power / 1000 * slotHours
+			schedule.Runs = scheduleRuns(used, resolution, prices, kWhPerSlot)
+			schedule.ScheduledHours = float64(scheduled) * slotHours
+			schedule.EnergyKWh = float64(scheduled) * kWhPerSlot
+			for _, run := range schedule.Runs {
+				schedule.Cost += run.Cost
+			}
+
+			if scheduled < need {
+				schedule.Message = fmt.Sprintf("%s hanya dapat dijadwalkan %.2f dari %.2f jam karena batas jam, durasi nyala, atau daya tersambung.", appliance.Name, schedule.ScheduledHours, hours)
+			} else {
+				schedule.Message = fmt.Sprintf("%s dijadwalkan %.2f jam pada waktu dengan harga termurah.", appliance.Name, schedule.ScheduledHours)
+			}
+		}
+
+		result.TotalKWh += schedule.EnergyKWh
+		result.TotalCost += schedule.Cost
+		result.Appliances[index] = schedule
+	}
+
+	for i := range result.Slots {
+		result.Slots[i].LoadW = load[i]
+		result.PeakLoadW = math.Max(result.PeakLoadW, load[i])
+	}
+	return result
+}
+
+// preferredSlot adalah slot yang dituju saat beberapa blok sama murah: awal window pertama jika jam nyala
+// dibatasi, selain itu appliance ke-rank diberi titik awal yang tersebar merata sepanjang hari
+func preferredSlot(task ScheduleTask, rank, count, slotCount, resolution int) int {
+	if len(task.AllowedHours) > 0 {
+		return task.AllowedHours[0].StartHour * 60 / resolution
+	}
+	return rank * slotCount / count
+}
+
+// placeTask memilih slot untuk satu appliance. Appliance continuous ditempatkan sebagai satu blok;
+// appliance lain ditempatkan per blok sepanjang MinRunMinutes, dan sisa yang lebih pendek hanya boleh
+// menyambung blok yang sudah ada agar tidak ada nyala yang lebih singkat dari batas minimal.
+func placeTask(task ScheduleTask, need, resolution, preferred int, prices, load []float64, capacity float64) []bool {
+	slotCount := len(prices)
+	power := float64(task.Appliance.Power)
+	used := make([]bool, slotCount)
+
+	free := func(i int) bool {
+		if used[i] || (capacity > 0 && load[i]+power > capacity+1e-9) {
+			return false
+		}
+		if len(task.AllowedHours) == 0 {
+			return true
+		}
+		hour := i * resolution / 60
+		for _, window := range task.AllowedHours {
+			if hourInRange(window.StartHour, window.EndHour, hour) {
+				return true
+			}
+		}
+		return false
+	}
+
+	chunk := need
+	if !task.Continuous {
+		chunk = int(math.Ceil(float64(task.MinRunMinutes) / float64(resolution)))
+		if chunk < 1 {
+			chunk = 1
+		}
+	}
+
+	for assigned := 0; assigned < need; {
+		length := chunk
+		if need-assigned < length {
+			length = need - assigned
+		}
+		adjacentOnly := length < chunk
+
+		// Harga yang sama dipecah ke slot dengan beban tertinggi paling kecil agar beban tersebar, lalu ke blok
+		// yang paling dekat dengan preferred
+		best, bestCost, bestPeak := -1, 0.0, 0.0
+		for s := 0; s+length <= slotCount; s++ {
+			if adjacentOnly && !((s > 0 && used[s-1]) || (s+length < slotCount && used[s+length])) {
+				continue
+			}
+			cost, peak, ok := 0.0, 0.0, true
+			for i := s; i < s+length; i++ {
+				if !free(i) {
+					ok = false
+					break
+				}
+				cost += prices[i]
+				peak = math.Max(peak, load[i])
+			}
+			if !ok {
+				continue
+			}
+			better := best < 0 || cost < bestCost-1e-9
+			if !better && cost < bestCost+1e-9 {
+				better = peak < bestPeak || (peak == bestPeak && slotDistance(s, preferred) < slotDistance(best, preferred))
+			}
+			if better {
+				best, bestCost, bestPeak = s, cost, peak
+			}
+		}
+		if best < 0 {
+			break
+		}
+		for i := best; i < best+length; i++ {
+			used[i] = true
+		}
+		assigned += length
+	}
+	return used
+}
+
+// slotDistance adalah jarak dua indeks slot
+func slotDistance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// scheduleRuns menggabungkan slot yang berurutan menjadi rentang waktu menyala
+func scheduleRuns(used []bool, resolution int, prices []float64, kWhPerSlot float64) []entity.ScheduleRun {
+	runs := []entity.ScheduleRun{}
+	for i := 0; i < len(used); i++ {
+		if !used[i] {
+			continue
+		}
+		run := entity.ScheduleRun{Start: slotLabel(i, resolution)}
+		j := i
+		for ; j < len(used) && used[j]; j++ {
+			run.Cost += prices[j] * kWhPerSlot
+		}
+		run.End = slotLabel(j, resolution)
+		run.Hours = float64((j-i)*resolution) / 60
+		runs = append(runs, run)
+		i = j
+	}
+	return runs
+}
+
+// slotLabel mengubah indeks slot menjadi jam "HH:MM"; slot setelah slot terakhir menjadi "24:00"
+func slotLabel(slot, resolution int) string {
+	minutes := slot * resolution
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
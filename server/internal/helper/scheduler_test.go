@@ -0,0 +1,113 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// peakTariff memakai harga 1000 dengan WBP 17:00–22:00 seharga 2000
+var peakTariff = TariffSchedule{{
+	Code:        "TOU",
+	PricePerKWh: 1000,
+	Model:       entity.TariffModelTOU,
+	Windows:     []entity.TariffWindow{{Name: "WBP", StartHour: 17, EndHour: 22, PricePerKWh: 2000}},
+}}
+
+var scheduleDay = time.Date(2024, time.March, 1, 0, 0, 0, 0, jakarta)
+
+func TestBuildDaySchedule_AvoidsPeakWindow(t *testing.T) {
+	tasks := []ScheduleTask{{
+		Appliance:    entity.ApplianceResponse{ID: 1, Name: "Mesin Cuci", Power: 500},
+		Hours:        2,
+		Continuous:   true,
+		AllowedHours: []entity.ScheduleWindow{{StartHour: 16, EndHour: 23}},
+	}}
+	schedule := BuildDaySchedule(scheduleDay, tasks, peakTariff, ScheduleOptions{})
+
+	runs := schedule.Appliances[0].Runs
+	if len(runs) != 1 {
+		t.Fatalf("expected one continuous run, got %+v", runs)
+	}
+	// Dalam 16:00–23:00 setiap blok 2 jam memuat minimal 1 jam WBP; 16:00–18:00 dan 21:00–23:00 sama murah
+	// sehingga blok yang paling dekat dengan awal window dipilih
+	if runs[0].Start != "16:00" || runs[0].End != "18:00" {
+		t.Fatalf("expected run 16:00-18:00, got %s-%s", runs[0].Start, runs[0].End)
+	}
+	if cost := schedule.Appliances[0].Cost; cost != 1500 {
+		t.Fatalf("expected cost 1500, got %v", cost)
+	}
+}
+
+func TestBuildDaySchedule_RespectsVALimit(t *testing.T) {
+	tasks := []ScheduleTask{
+		{Appliance: entity.ApplianceResponse{ID: 1, Name: "Setrika", Power: 600}, Hours: 1},
+		{Appliance: entity.ApplianceResponse{ID: 2, Name: "Rice Cooker", Power: 400}, Hours: 1},
+		{Appliance: entity.ApplianceResponse{ID: 3, Name: "Pompa Air", Power: 1200}, Hours: 1},
+	}
+	schedule := BuildDaySchedule(scheduleDay, tasks, FlatTariff(1000), ScheduleOptions{ResolutionMinutes: 60, VALimit: 900, PowerFactor: 1})
+
+	if schedule.CapacityW != 900 {
+		t.Fatalf("expected capacity 900 W, got %v", schedule.CapacityW)
+	}
+	if schedule.PeakLoadW > 900 {
+		t.Fatalf("peak load %v exceeds the VA limit", schedule.PeakLoadW)
+	}
+	if schedule.Appliances[0].ScheduledHours != 1 || schedule.Appliances[1].ScheduledHours != 1 {
+		t.Fatalf("expected setrika and rice cooker to be scheduled: %+v", schedule.Appliances)
+	}
+	if schedule.Appliances[0].Runs[0].Start == schedule.Appliances[1].Runs[0].Start {
+		t.Fatalf("setrika and rice cooker should not run together above the limit")
+	}
+	if schedule.Appliances[2].ScheduledHours != 0 {
+		t.Fatalf("pompa air above the VA limit should not be scheduled: %+v", schedule.Appliances[2])
+	}
+}
+
+func TestBuildDaySchedule_MinRunMinutes(t *testing.T) {
+	tasks := []ScheduleTask{{
+		Appliance:     entity.ApplianceResponse{ID: 1, Name: "AC", Power: 800},
+		Hours:         1.5,
+		MinRunMinutes: 60,
+	}}
+	schedule := BuildDaySchedule(scheduleDay, tasks, peakTariff, ScheduleOptions{})
+
+	appliance := schedule.Appliances[0]
+	if appliance.ScheduledHours != 1.5 {
+		t.Fatalf("expected 1.5 hours, got %v", appliance.ScheduledHours)
+	}
+	for _, run := range appliance.Runs {
+		if run.Hours < 1 {
+			t.Fatalf("run %s-%s is shorter than the minimum run", run.Start, run.End)
+		}
+	}
+	for _, slot := range schedule.Slots {
+		if slot.LoadW > 0 && slot.PricePerKWh != 1000 {
+			t.Fatalf("slot %s scheduled during the peak window", slot.Start)
+		}
+	}
+}
+
+func TestBuildDaySchedule_SpreadsFlatTariff(t *testing.T) {
+	tasks := []ScheduleTask{
+		{Appliance: entity.ApplianceResponse{ID: 1, Name: "Mesin Cuci", Power: 500}, Hours: 2, Continuous: true},
+		{Appliance: entity.ApplianceResponse{ID: 2, Name: "Dispenser", Power: 300}, Hours: 2, Continuous: true},
+		{Appliance: entity.ApplianceResponse{ID: 3, Name: "Pompa Air", Power: 700}, Hours: 2, Continuous: true},
+	}
+	schedule := BuildDaySchedule(scheduleDay, tasks, FlatTariff(1000), ScheduleOptions{ResolutionMinutes: 60})
+
+	// Semua slot sama murah sehingga appliance disebar sesuai urutan daya, bukan ditumpuk sejak 00:00
+	want := map[string]string{"Pompa Air": "00:00", "Mesin Cuci": "08:00", "Dispenser": "16:00"}
+	for _, appliance := range schedule.Appliances {
+		if len(appliance.Runs) != 1 || appliance.Runs[0].Start != want[appliance.Name] {
+			t.Fatalf("expected %s to start at %s, got %+v", appliance.Name, want[appliance.Name], appliance.Runs)
+		}
+		if appliance.Cost != 2000*float64(appliance.Power)/1000 {
+			t.Fatalf("unexpected cost for %s: %v", appliance.Name, appliance.Cost)
+		}
+	}
+	if schedule.PeakLoadW != 700 {
+		t.Fatalf("expected no overlapping runs, got peak %v", schedule.PeakLoadW)
+	}
+}
@@ -0,0 +1,133 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// SimulateChanges menerapkan perubahan hipotetis pada appliance lalu membandingkan proyeksi konsumsi dan biaya
+// bulan month dengan kondisi saat ini. Energi dihitung seperti OptimizeMonthlyBudget (AverageUsage × kWh per jam
+// × jumlah hari) dan total bulanan dihargai dengan MonthlyCost, sama seperti anggaran rekomendasi bulanan:
+// tarif block dihitung per blok, tarif flat dan tou memakai harga dasar karena jam pemakaian tidak diketahui.
+func SimulateChanges(appliances []entity.ApplianceResponse, changes []entity.SimulationChange, tariff TariffSchedule, month time.Time) (entity.SimulationResult, error) {
+	start, _, err := BucketBounds(month, BucketMonth)
+	if err != nil {
+		return entity.SimulationResult{}, err
+	}
+	days := float64(start.AddDate(0, 1, -1).Day())
+
+	simulated := make([]entity.SimulatedAppliance, 0, len(appliances)+len(changes))
+	index := make(map[uint]int, len(appliances))
+	kWhPerHour := make(map[uint]float64, len(appliances))
+	for _, appliance := range appliances {
+		kWhPerHour[appliance.ID] = applianceKWhPerHour(appliance)
+		baseline := kWhPerHour[appliance.ID]
+		hours := math.Min(math.Max(appliance.AverageUsage, 0), maxHoursPerDay)
+		index[appliance.ID] = len(simulated)
+		simulated = append(simulated, entity.SimulatedAppliance{
+			ApplianceID:        appliance.ID,
+			Name:               appliance.Name,
+			Action:             entity.SimulationUnchanged,
+			BaselinePower:      int(math.Round(baseline * 1000)),
+			ScenarioPower:      int(math.Round(baseline * 1000)),
+			BaselineHours:      hours,
+			ScenarioHours:      hours,
+			BaselineMonthlyKWh: hours * baseline * days,
+			ScenarioMonthlyKWh: hours * baseline * days,
+		})
+	}
+
+	for _, change := range changes {
+		if change.Power != nil && *change.Power < 0 {
+			return entity.SimulationResult{}, errors.New("power cannot be negative")
+		}
+		if change.HoursPerDay != nil && (*change.HoursPerDay < 0 || *change.HoursPerDay > maxHoursPerDay) {
+			return entity.SimulationResult{}, errors.New("hours_per_day must be between 0 and 24")
+		}
+
+		action := strings.ToLower(strings.TrimSpace(change.Action))
+		switch action {
+		case entity.SimulationAdd:
+			name := strings.TrimSpace(change.Name)
+			if name == "" || change.Power == nil || change.HoursPerDay == nil {
+				return entity.SimulationResult{}, errors.New("add requires name, power, and hours_per_day")
+			}
+			simulated = append(simulated, entity.SimulatedAppliance{
+				Name:               name,
+				Action:             entity.SimulationAdd,
+				ScenarioPower:      *change.Power,
+				ScenarioHours:      *change.HoursPerDay,
+				ScenarioMonthlyKWh: float64(*change.Power) / 1000 * *change.HoursPerDay * days,
+			})
+		case entity.SimulationModify, entity.SimulationRemove:
+			i, ok := index[change.ApplianceID]
+			if !ok {
+				return entity.SimulationResult{}, fmt.Errorf("appliance %d not found", change.ApplianceID)
+			}
+			appliance := &simulated[i]
+			if appliance.Action != entity.SimulationUnchanged {
+				return entity.SimulationResult{}, fmt.Errorf("appliance %d is changed more than once", change.ApplianceID)
+			}
+			appliance.Action = action
+			scenario := kWhPerHour[change.ApplianceID]
+			if action == entity.SimulationRemove {
+				scenario, appliance.ScenarioPower, appliance.ScenarioHours = 0, 0, 0
+			}
+			if change.Power != nil && action == entity.SimulationModify {
+				scenario, appliance.ScenarioPower = float64(*change.Power)/1000, *change.Power
+			}
+			if change.HoursPerDay != nil && action == entity.SimulationModify {
+				appliance.ScenarioHours = *change.HoursPerDay
+			}
+			appliance.ScenarioMonthlyKWh = scenario * appliance.ScenarioHours * days
+		default:
+			return entity.SimulationResult{}, fmt.Errorf("unsupported action %q, use modify, remove or add", change.Action)
+		}
+	}
+
+	result := entity.SimulationResult{
+		Month:       start.Format("2006-01"),
+		DaysInMonth: int(days),
+		Tariff:      tariff.VersionAt(start).Code,
+		Appliances:  simulated,
+	}
+	for _, appliance := range simulated {
+		result.Baseline.MonthlyKWh += appliance.BaselineMonthlyKWh
+		result.Scenario.MonthlyKWh += appliance.ScenarioMonthlyKWh
+	}
+	result.Baseline = simulationTotals(result.Baseline.MonthlyKWh, days, tariff, start)
+	result.Scenario = simulationTotals(result.Scenario.MonthlyKWh, days, tariff, start)
+	result.Savings = entity.SimulationTotals{
+		DailyKWh:    result.Baseline.DailyKWh - result.Scenario.DailyKWh,
+		MonthlyKWh:  result.Baseline.MonthlyKWh - result.Scenario.MonthlyKWh,
+		DailyCost:   result.Baseline.DailyCost - result.Scenario.DailyCost,
+		MonthlyCost: result.Baseline.MonthlyCost - result.Scenario.MonthlyCost,
+	}
+
+	// Biaya per appliance adalah porsi energinya dari biaya total skenario yang sama
+	for i := range result.Appliances {
+		appliance := &result.Appliances[i]
+		if result.Baseline.MonthlyKWh > 0 {
+			appliance.BaselineMonthlyCost = appliance.BaselineMonthlyKWh / result.Baseline.MonthlyKWh * result.Baseline.MonthlyCost
+		}
+		if result.Scenario.MonthlyKWh > 0 {
+			appliance.ScenarioMonthlyCost = appliance.ScenarioMonthlyKWh / result.Scenario.MonthlyKWh * result.Scenario.MonthlyCost
+		}
+	}
+	return result, nil
+}
+
+func simulationTotals(monthlyKWh, days float64, tariff TariffSchedule, start time.Time) entity.SimulationTotals {
+	monthlyCost := tariff.MonthlyCost(start, monthlyKWh)
+	return entity.SimulationTotals{
+		DailyKWh:    monthlyKWh / days,
+		MonthlyKWh:  monthlyKWh,
+		DailyCost:   monthlyCost / days,
+		MonthlyCost: monthlyCost,
+	}
+}
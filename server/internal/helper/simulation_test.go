@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestSimulateChanges(t *testing.T) {
+	appliances := []entity.ApplianceResponse{
+		{ID: 1, Name: "AC", Power: 1000, AverageUsage: 8},
+		{ID: 2, Name: "Water Heater", Power: 500, AverageUsage: 3},
+		{ID: 3, Name: "TV", Power: 100, AverageUsage: 5},
+	}
+	power, hours := 600, 1.0
+	changes := []entity.SimulationChange{
+		{Action: "modify", ApplianceID: 1, Power: &power},
+		{Action: "modify", ApplianceID: 2, HoursPerDay: &hours},
+		{Action: "remove", ApplianceID: 3},
+	}
+	month := time.Date(2024, time.April, 15, 0, 0, 0, 0, jakarta)
+
+	result, err := SimulateChanges(appliances, changes, FlatTariff(1000), month)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Baseline 8 + 1,5 + 0,5 = 10 kWh/hari; skenario 4,8 + 0,5 = 5,3 kWh/hari selama 30 hari
+	if result.DaysInMonth != 30 || result.Baseline.DailyKWh != 10 || result.Scenario.MonthlyKWh != 159 {
+		t.Fatalf("unexpected totals: %+v", result)
+	}
+	if result.Savings.MonthlyCost != 141000 {
+		t.Fatalf("expected monthly savings 141000, got %v", result.Savings.MonthlyCost)
+	}
+	if result.Appliances[2].Action != entity.SimulationRemove || result.Appliances[2].ScenarioMonthlyKWh != 0 {
+		t.Fatalf("TV should be removed: %+v", result.Appliances[2])
+	}
+}
+
+func TestSimulateChanges_Invalid(t *testing.T) {
+	appliances := []entity.ApplianceResponse{{ID: 1, Name: "AC", Power: 1000, AverageUsage: 8}}
+	power := 800
+	tests := []struct {
+		name    string
+		changes []entity.SimulationChange
+	}{
+		{"unknown appliance", []entity.SimulationChange{{Action: "remove", ApplianceID: 9}}},
+		{"changed twice", []entity.SimulationChange{{Action: "remove", ApplianceID: 1}, {Action: "modify", ApplianceID: 1, Power: &power}}},
+		{"add without hours", []entity.SimulationChange{{Action: "add", Name: "Kulkas", Power: &power}}},
+		{"unknown action", []entity.SimulationChange{{Action: "replace", ApplianceID: 1}}},
+	}
+	for _, tt := range tests {
+		if _, err := SimulateChanges(appliances, tt.changes, FlatTariff(1000), time.Now()); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+const (
+	// DefaultPerformanceRatio memperhitungkan rugi suhu, inverter, kabel, dan kotoran pada panel
+	DefaultPerformanceRatio = 0.8
+	// DefaultCostPerKWp adalah perkiraan harga PLTS atap terpasang (panel, inverter, instalasi) per kWp
+	DefaultCostPerKWp = 15000000.0
+	// SolarLifetimeYears adalah umur sistem untuk menghitung manfaat bersih
+	SolarLifetimeYears = 25
+	// solarSizeStep adalah kelipatan ukuran sistem yang dibandingkan saat mencari rekomendasi
+	solarSizeStep = 0.5
+	// maxIrradiance membatasi nilai profil yang diunggah; iradiasi permukaan bumi jarang melebihi 1.200 W/m²
+	maxIrradiance = 1500.0
+)
+
+// DefaultIrradiance adalah rata-rata tahunan iradiasi global horizontal (W/m²) per jam untuk lintang
+// Indonesia (sekitar 6° LS), dengan total harian ±4,8 kWh/m²
+var DefaultIrradiance = [24]float64{
+	6: 30, 7: 150, 8: 320, 9: 480, 10: 600, 11: 670,
+	12: 680, 13: 640, 14: 540, 15: 400, 16: 230, 17: 90, 18: 10,
+}
+
+// SolarOptions adalah asumsi teknis dan finansial estimasi PLTS atap. KWp nol berarti hanya rekomendasi
+// ukuran. ExportCredit adalah porsi kWh ekspor yang mengurangi kWh tagihan: 1 untuk net-metering penuh,
+// 0 jika ekspor tidak diperhitungkan seperti pada aturan PLTS atap terbaru.
+type SolarOptions struct {
+	KWp              float64
+	VALimit          int
+	CostPerKWp       float64
+	PerformanceRatio float64
+	ExportCredit     float64
+}
+
+// NewIrradianceProfile melengkapi profil dengan total iradiasi harian
+func NewIrradianceProfile(source string, hours [24]float64) entity.IrradianceProfile {
+	profile := entity.IrradianceProfile{Source: source, Hours: hours}
+	for _, irradiance := range hours {
+		profile.DailyKWhPerM2 += irradiance / 1000
+	}
+	return profile
+}
+
+// ParseIrradianceProfile membaca profil iradiasi dari CSV dengan kolom hour/jam (0–23 atau "HH:00") dan
+// irradiance/ghi/iradiasi dalam W/m². Jam yang tidak dicantumkan dianggap 0.
+func ParseIrradianceProfile(file *CSVFile) ([24]float64, error) {
+	var profile [24]float64
+	if len(file.RowErrors) > 0 {
+		return profile, fmt.Errorf("line %d: %s", file.RowErrors[0].Line, file.RowErrors[0].Message)
+	}
+
+	hourColumn, irradianceColumn := -1, -1
+	for i, header := range file.Header {
+		switch strings.ToLower(strings.TrimSpace(header)) {
+		case "hour", "jam":
+			hourColumn = i
+		case "irradiance", "ghi", "iradiasi", "w_m2":
+			irradianceColumn = i
+		}
+	}
+	if hourColumn < 0 || irradianceColumn < 0 {
+		return profile, errors.New("columns hour and irradiance are required")
+	}
+
+	seen := make(map[int]bool)
+	var total float64
+	for i, record := range file.Records {
+		line := i + 2
+		if i < len(file.Lines) {
+			line = file.Lines[i]
+		}
+		if hourColumn >= len(record) || irradianceColumn >= len(record) {
+			return profile, fmt.Errorf("line %d: missing value", line)
+		}
+
+		hour, err := parseProfileHour(record[hourColumn])
+		if err != nil {
+			return profile, fmt.Errorf("line %d: %w", line, err)
+		}
+		if seen[hour] {
+			return profile, fmt.Errorf("line %d: hour %d is listed more than once", line, hour)
+		}
+		seen[hour] = true
+
+		irradiance, err := ParseNumber(record[irradianceColumn], "", "")
+		if err != nil || irradiance < 0 || irradiance > maxIrradiance {
+			return profile, fmt.Errorf("line %d: irradiance must be between 0 and %.0f W/m²", line, maxIrradiance)
+		}
+		profile[hour] = irradiance
+		total += irradiance
+	}
+	if total <= 0 {
+		return profile, errors.New("irradiance profile is empty")
+	}
+	return profile, nil
+}
+
+// parseProfileHour menerima jam sebagai angka 0–23 atau "HH:00"
+func parseProfileHour(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, ":") {
+		minutes, err := parseClock(value)
+		if err != nil || minutes%60 != 0 || minutes >= 24*60 {
+			return 0, fmt.Errorf("invalid hour %q", value)
+		}
+		return minutes / 60, nil
+	}
+	hour, err := strconv.Atoi(value)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour %q", value)
+	}
+	return hour, nil
+}
+
+// HourlyConsumptionProfile menghitung rata-rata konsumsi rumah (kWh) per jam dalam sehari dari reading.
+// Energi reading dibagi rata sepanjang durasinya mulai dari timestamp; hasilnya dibagi jumlah hari
+// yang memiliki reading.
+func HourlyConsumptionProfile(readings []entity.Reading) ([24]float64, int) {
+	var profile [24]float64
+	days := make(map[time.Time]bool)
+	for _, reading := range readings {
+		if reading.Energy <= 0 {
+			continue
+		}
+		start := reading.Timestamp.In(jakarta)
+		days[startOfDay(start)] = true
+		if reading.Duration <= 0 {
+			profile[start.Hour()] += reading.Energy
+			continue
+		}
+
+		end := start.Add(time.Duration(reading.Duration * float64(time.Hour)))
+		for at := start; at.Before(end); {
+			next := at.Truncate(time.Hour).Add(time.Hour)
+			if next.After(end) {
+				next = end
+			}
+			profile[at.Hour()] += reading.Energy * next.Sub(at).Hours() / reading.Duration
+			at = next
+		}
+	}
+
+	if len(days) > 0 {
+		for hour := range profile {
+			profile[hour] /= float64(len(days))
+		}
+	}
+	return profile, len(days)
+}
+
+// AverageConsumptionProfile membagi rata konsumsi harian appliance (AverageUsage) ke 24 jam; dipakai jika
+// belum ada reading sehingga pola jam pemakaian tidak diketahui
+func AverageConsumptionProfile(appliances []entity.ApplianceResponse) [24]float64 {
+	var daily float64
+	for _, appliance := range appliances {
+		daily += applianceKWhPerHour(appliance) * math.Min(math.Max(appliance.AverageUsage, 0), maxHoursPerDay)
+	}
+
+	var profile [24]float64
+	for hour := range profile {
+		profile[hour] = daily / 24
+	}
+	return profile
+}
+
+// SizeSolar memperkirakan produksi, konsumsi sendiri, ekspor, penurunan tagihan, dan balik modal PLTS atap
+// pada bulan month. Kapasitas dibatasi 100% daya tersambung (1 kWp per 1.000 VA). Ukuran yang
+// direkomendasikan adalah kelipatan 0,5 kWp dengan manfaat bersih terbesar selama umur sistem.
+func SizeSolar(consumption, irradiance [24]float64, tariff TariffSchedule, month time.Time, options SolarOptions) (entity.SolarSizing, error) {
+	switch {
+	case options.VALimit <= 0:
+		return entity.SolarSizing{}, errors.New("va_limit is required for this tariff")
+	case options.KWp < 0:
+		return entity.SolarSizing{}, errors.New("kwp cannot be negative")
+	case options.PerformanceRatio <= 0 || options.PerformanceRatio > 1:
+		return entity.SolarSizing{}, errors.New("performance_ratio must be between 0 and 1")
+	case options.CostPerKWp < 0:
+		return entity.SolarSizing{}, errors.New("cost_per_kwp cannot be negative")
+	case options.ExportCredit < 0 || options.ExportCredit > 1:
+		return entity.SolarSizing{}, errors.New("export_credit must be between 0 and 1")
+	}
+
+	maxKWp := float64(options.VALimit) / 1000
+	if options.KWp > maxKWp {
+		return entity.SolarSizing{}, fmt.Errorf("kwp cannot exceed %.2f kWp for a %d VA connection", maxKWp, options.VALimit)
+	}
+
+	start, _, err := BucketBounds(month, BucketMonth)
+	if err != nil {
+		return entity.SolarSizing{}, err
+	}
+	days := start.AddDate(0, 1, -1).Day()
+
+	sizing := entity.SolarSizing{
+		Month:            start.Format("2006-01"),
+		DaysInMonth:      days,
+		Tariff:           tariff.VersionAt(start).Code,
+		VALimit:          options.VALimit,
+		MaxKWp:           maxKWp,
+		CostPerKWp:       options.CostPerKWp,
+		PerformanceRatio: options.PerformanceRatio,
+		ExportCredit:     options.ExportCredit,
+		LifetimeYears:    SolarLifetimeYears,
+		Candidates:       []entity.SolarEstimate{},
+	}
+
+	sizes := []float64{}
+	for kWp := solarSizeStep; kWp <= maxKWp+1e-9; kWp += solarSizeStep {
+		sizes = append(sizes, kWp)
+	}
+	if len(sizes) == 0 || maxKWp-sizes[len(sizes)-1] > 1e-9 {
+		sizes = append(sizes, maxKWp)
+	}
+	for _, kWp := range sizes {
+		estimate, _ := estimateSolar(consumption, irradiance, kWp, tariff, start, days, options)
+		sizing.Candidates = append(sizing.Candidates, estimate)
+		if estimate.LifetimeNetBenefit > 0 && (sizing.Recommended == nil || estimate.LifetimeNetBenefit > sizing.Recommended.LifetimeNetBenefit) {
+			recommended := estimate
+			sizing.Recommended = &recommended
+		}
+	}
+
+	switch {
+	case options.KWp > 0:
+		estimate, hours := estimateSolar(consumption, irradiance, options.KWp, tariff, start, days, options)
+		sizing.Estimate, sizing.Hours = &estimate, hours
+	case sizing.Recommended != nil:
+		_, sizing.Hours = estimateSolar(consumption, irradiance, sizing.Recommended.KWp, tariff, start, days, options)
+	default:
+		_, sizing.Hours = estimateSolar(consumption, irradiance, 0, tariff, start, days, options)
+	}
+	return sizing, nil
+}
+
+// estimateSolar menghitung satu ukuran sistem. Produksi per jam = kWp × iradiasi/1000 × performance ratio;
+// produksi dipakai langsung sampai sebesar konsumsi jam itu dan sisanya diekspor ke jaringan. Ekspor yang
+// dikreditkan mengurangi kWh impor setiap jam secara proporsional, lalu tagihan sebelum dan sesudah dihitung
+// per jam dengan monthlyCost.
+func estimateSolar(consumption, irradiance [24]float64, kWp float64, tariff TariffSchedule, start time.Time, days int, options SolarOptions) (entity.SolarEstimate, []entity.SolarHour) {
+	hours := make([]entity.SolarHour, 24)
+	var imported [24]float64
+	var generation, consumed, selfConsumed, exported float64
+	for hour := range hours {
+		generated := kWp * irradiance[hour] / 1000 * options.PerformanceRatio
+		self := math.Min(generated, consumption[hour])
+		hours[hour] = entity.SolarHour{
+			Hour:            hour,
+			Irradiance:      irradiance[hour],
+			ConsumptionKWh:  consumption[hour],
+			GenerationKWh:   generated,
+			SelfConsumedKWh: self,
+			ExportedKWh:     generated - self,
+		}
+		imported[hour] = consumption[hour] - self
+		generation += generated
+		consumed += consumption[hour]
+		selfConsumed += self
+		exported += generated - self
+	}
+
+	month := float64(days)
+	estimate := entity.SolarEstimate{
+		KWp:                   kWp,
+		SystemCost:            kWp * options.CostPerKWp,
+		MonthlyGenerationKWh:  generation * month,
+		MonthlyConsumptionKWh: consumed * month,
+		SelfConsumedKWh:       selfConsumed * month,
+		ExportedKWh:           exported * month,
+		ImportedKWh:           (consumed - selfConsumed) * month,
+	}
+	estimate.BilledKWh = math.Max(estimate.ImportedKWh-estimate.ExportedKWh*options.ExportCredit, 0)
+	if estimate.MonthlyGenerationKWh > 0 {
+		estimate.SelfConsumptionRatio = estimate.SelfConsumedKWh / estimate.MonthlyGenerationKWh
+	}
+	if estimate.MonthlyConsumptionKWh > 0 {
+		estimate.SelfSufficiency = estimate.SelfConsumedKWh / estimate.MonthlyConsumptionKWh
+	}
+
+	if estimate.ImportedKWh > 0 {
+		billed := estimate.BilledKWh / estimate.ImportedKWh
+		for hour := range imported {
+			imported[hour] *= billed
+		}
+	}
+	estimate.BillBefore = monthlyCost(consumption, tariff, start, days)
+	estimate.BillAfter = monthlyCost(imported, tariff, start, days)
+	estimate.BillReduction = estimate.BillBefore - estimate.BillAfter
+	estimate.AnnualSavings = estimate.BillReduction * 12
+	if estimate.AnnualSavings > 0 {
+		payback := estimate.SystemCost / estimate.AnnualSavings
+		estimate.PaybackYears = &payback
+	}
+	estimate.LifetimeNetBenefit = estimate.AnnualSavings*SolarLifetimeYears - estimate.SystemCost
+	return estimate, hours
+}
+
+// monthlyCost menghargai profil beban per jam yang berulang setiap hari selama days hari mulai start. Setiap jam
+// memakai harga tarif pada jam itu (window tou) dan konsumsi bulan berjalan sebelumnya (blok tarif block).
+func monthlyCost(load [24]float64, tariff TariffSchedule, start time.Time, days int) float64 {
+	var cost, monthToDate float64
+	for day := 0; day < days; day++ {
+		midnight := start.AddDate(0, 0, day)
+		for hour, energy := range load {
+			cost += tariff.Cost(midnight.Add(time.Duration(hour)*time.Hour), energy, monthToDate)
+			monthToDate += energy
+		}
+	}
+	return cost
+}
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestParseIrradianceProfile(t *testing.T) {
+	file, err := ReadCSVFile(strings.NewReader("jam;iradiasi\n11:00;650,5\n12;700\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := ParseIrradianceProfile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile[11] != 650.5 || profile[12] != 700 || profile[0] != 0 {
+		t.Fatalf("unexpected profile: %v", profile)
+	}
+
+	for _, data := range []string{
+		"hour,irradiance\n12,700\n12,650\n",
+		"hour,irradiance\n24,700\n",
+		"hour,irradiance\n12,-1\n",
+		"hour,irradiance\n0,0\n",
+		"hour,ghi_kwh\n12,5\n",
+	} {
+		file, _ := ReadCSVFile(strings.NewReader(data))
+		if _, err := ParseIrradianceProfile(file); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
+
+func TestHourlyConsumptionProfile(t *testing.T) {
+	day := time.Date(2024, time.April, 1, 0, 0, 0, 0, jakarta)
+	readings := []entity.Reading{
+		{Timestamp: day.Add(10*time.Hour + 30*time.Minute), Energy: 2, Duration: 2},
+		{Timestamp: day.AddDate(0, 0, 1), Energy: 1},
+	}
+
+	profile, days := HourlyConsumptionProfile(readings)
+	if days != 2 {
+		t.Fatalf("expected 2 days, got %d", days)
+	}
+	if profile[0] != 0.5 || profile[10] != 0.25 || profile[11] != 0.5 || profile[12] != 0.25 {
+		t.Fatalf("energy should be spread over the reading duration: %v", profile)
+	}
+}
+
+func TestSizeSolar(t *testing.T) {
+	var consumption, irradiance [24]float64
+	consumption[12], consumption[19] = 1, 0.5
+	irradiance[12] = 1000
+	month := time.Date(2024, time.April, 15, 0, 0, 0, 0, jakarta)
+	options := SolarOptions{VALimit: 2200, CostPerKWp: 1000000, PerformanceRatio: 1}
+
+	sizing, err := SizeSolar(consumption, irradiance, FlatTariff(1000), month, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizing.MaxKWp != 2.2 || len(sizing.Candidates) != 5 || sizing.Candidates[4].KWp != 2.2 {
+		t.Fatalf("expected 0.5 kWp steps up to the 2.2 kWp limit, got %+v", sizing.Candidates)
+	}
+	// 1 kWp menutup seluruh beban siang: 30 kWh/bulan = Rp360.000/tahun; ukuran lebih besar hanya menambah ekspor
+	best := sizing.Recommended
+	if best == nil || best.KWp != 1 || best.AnnualSavings != 360000 || best.LifetimeNetBenefit != 8000000 || *best.PaybackYears != 1000000.0/360000 {
+		t.Fatalf("unexpected recommendation: %+v", best)
+	}
+	if oversized := sizing.Candidates[2]; oversized.ExportedKWh != 15 || oversized.SelfConsumptionRatio != 2.0/3 || oversized.AnnualSavings != 360000 {
+		t.Fatalf("unexpected 1.5 kWp estimate: %+v", oversized)
+	}
+
+	// Dengan net-metering penuh, ekspor 2 kWp menghapus sisa tagihan malam hari
+	credit := options
+	credit.KWp, credit.ExportCredit = 2, 1
+	sizing, err = SizeSolar(consumption, irradiance, FlatTariff(1000), month, credit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizing.Estimate == nil || sizing.Estimate.ImportedKWh != 15 || sizing.Estimate.BilledKWh != 0 || sizing.Estimate.BillAfter != 0 {
+		t.Fatalf("unexpected net-metering estimate: %+v", sizing.Estimate)
+	}
+	if sizing.Hours[12].GenerationKWh != 2 || sizing.Hours[12].ExportedKWh != 1 {
+		t.Fatalf("unexpected hourly profile: %+v", sizing.Hours[12])
+	}
+
+	tooLarge := options
+	tooLarge.KWp = 3
+	if _, err := SizeSolar(consumption, irradiance, FlatTariff(1000), month, tooLarge); err == nil {
+		t.Fatal("expected error for a system larger than the VA limit")
+	}
+}
+
+func TestSizeSolar_PricesEachHour(t *testing.T) {
+	var consumption, irradiance [24]float64
+	consumption[12], consumption[19] = 1, 0.5
+	irradiance[12] = 1000
+	month := time.Date(2024, time.April, 15, 0, 0, 0, 0, jakarta)
+	options := SolarOptions{VALimit: 2200, KWp: 1, CostPerKWp: 1000000, PerformanceRatio: 1}
+
+	// Beban malam berada pada WBP (2.000/kWh), beban siang pada harga dasar
+	sizing, err := SizeSolar(consumption, irradiance, peakTariff, month, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate := sizing.Estimate; !approxEqual(estimate.BillBefore, 60000) || !approxEqual(estimate.BillAfter, 30000) {
+		t.Fatalf("expected tou bill 60000 -> 30000, got %v -> %v", estimate.BillBefore, estimate.BillAfter)
+	}
+
+	// Kredit 25% dari ekspor 30 kWh mengurangi separuh impor 15 kWh, seluruhnya pada WBP
+	credit := options
+	credit.KWp, credit.ExportCredit = 2, 0.25
+	sizing, _ = SizeSolar(consumption, irradiance, peakTariff, month, credit)
+	if estimate := sizing.Estimate; !approxEqual(estimate.BilledKWh, 7.5) || !approxEqual(estimate.BillAfter, 15000) {
+		t.Fatalf("expected 7.5 kWh billed at the peak price, got %+v", estimate)
+	}
+
+	// 45 kWh/bulan: 20 kWh pertama 1.000/kWh, sisanya 2.000/kWh; setelah PLTS hanya 15 kWh di blok pertama
+	block := TariffSchedule{{Code: "BLOCK", PricePerKWh: 1000, Model: entity.TariffModelBlock, Blocks: []entity.TariffBlock{{UpToKWh: 20, PricePerKWh: 1000}, {PricePerKWh: 2000}}}}
+	sizing, _ = SizeSolar(consumption, irradiance, block, month, options)
+	if estimate := sizing.Estimate; !approxEqual(estimate.BillBefore, 70000) || !approxEqual(estimate.BillAfter, 15000) {
+		t.Fatalf("expected block bill 70000 -> 15000, got %v -> %v", estimate.BillBefore, estimate.BillAfter)
+	}
+}
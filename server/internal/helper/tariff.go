@@ -0,0 +1,323 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+// CurrencyIDR adalah mata uang default katalog tarif
+const CurrencyIDR = "IDR"
+
+// DefaultTariffs adalah katalog tarif PLN awal yang diisi ke database saat tabel tarif masih kosong.
+// Nama tarif sama dengan nama golongan lama sehingga client yang masih mengirim golongan tetap dikenali.
+func DefaultTariffs() []entity.TariffRequest {
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, jakarta)
+	tariff := func(code, name string, minVA, maxVA int, price float64) entity.TariffRequest {
+		return entity.TariffRequest{
+			Code:          code,
+			Name:          name,
+			MinVA:         minVA,
+			MaxVA:         maxVA,
+			PricePerKWh:   price,
+			Currency:      CurrencyIDR,
+			Model:         entity.TariffModelFlat,
+			EffectiveFrom: from,
+		}
+	}
+	// Golongan tegangan menengah membayar WBP (17:00–22:00) sebesar K × LWBP; katalog awal memakai K = 1,4
+	peak := func(req entity.TariffRequest) entity.TariffRequest {
+		req.Model = entity.TariffModelTOU
+		req.Windows = []entity.TariffWindow{{Name: "WBP", StartHour: 17, EndHour: 22, PricePerKWh: math.Round(req.PricePerKWh*1.4*100) / 100}}
+		return req
+	}
+
+	return []entity.TariffRequest{
+		tariff("R-1/450-S", "Subsidi daya 450 VA", 450, 450, 415.00),
+		tariff("R-1/900-S", "Subsidi daya 900 VA", 900, 900, 605.00),
+		tariff("R-1/900", "R-1/TR daya 900 VA", 900, 900, 1352.00),
+		tariff("R-1/1300", "R-1/TR daya 1300 VA", 1300, 1300, 1444.70),
+		tariff("R-1/2200", "R-1/TR daya 2200 VA", 2200, 2200, 1444.70),
+		tariff("R-2", "R-2/TR daya 3500 VA - 5500 VA", 3500, 5500, 1699.53),
+		tariff("R-3", "R-3/TR daya 6600 VA ke atas", 6600, 0, 1699.53),
+		tariff("B-2", "B-2/TR daya 6600 VA - 200 kVA", 6600, 200000, 1444.70),
+		peak(tariff("B-3", "B-3/TM daya di atas 200 kVA", 200001, 0, 1114.74)),
+		peak(tariff("I-3", "I-3/TM daya di atas 200 kVA", 200001, 0, 1114.74)),
+		tariff("I-4", "I-4/TT daya 30.000 kVA ke atas", 30000000, 0, 996.74),
+		tariff("P-1", "P-1/TR daya 6600 VA - 200 kVA", 6600, 200000, 1699.53),
+		peak(tariff("P-2", "P-2/TM daya di atas 200 kVA", 200001, 0, 1522.88)),
+		tariff("P-3", "P-3/TR penerangan jalan umum", 0, 0, 1699.53),
+		tariff("L/TR", "L/TR", 0, 0, 1644.00),
+		tariff("L/TM", "L/TM", 0, 0, 1644.00),
+		tariff("L/TT", "L/TT", 0, 0, 1644.00),
+	}
+}
+
+// TariffSchedule adalah seluruh versi satu kode tarif, terurut menurut tanggal mulai berlaku
+type TariffSchedule []entity.TariffResponse
+
+// NewTariffSchedule mengurutkan versi tarif dari yang paling lama berlaku
+func NewTariffSchedule(versions []entity.TariffResponse) TariffSchedule {
+	schedule := append(TariffSchedule{}, versions...)
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].EffectiveFrom.Before(schedule[j].EffectiveFrom)
+	})
+	return schedule
+}
+
+// FlatTariff membuat jadwal dengan satu harga yang berlaku sepanjang waktu
+func FlatTariff(rate float64) TariffSchedule {
+	return TariffSchedule{{PricePerKWh: rate, Currency: CurrencyIDR, Model: entity.TariffModelFlat}}
+}
+
+// VersionAt mengembalikan versi tarif yang berlaku pada waktu t. Waktu sebelum versi pertama, setelah
+// EffectiveTo, atau di sela dua versi tidak memiliki harga dan menghasilkan versi kosong (lihat ActiveAt).
+func (s TariffSchedule) VersionAt(t time.Time) entity.TariffResponse {
+	version, _ := s.ActiveAt(t)
+	return version
+}
+
+// ActiveAt seperti VersionAt, dengan ok bernilai false jika tidak ada versi yang berlaku pada waktu t
+func (s TariffSchedule) ActiveAt(t time.Time) (entity.TariffResponse, bool) {
+	if len(s) == 0 {
+		return entity.TariffResponse{}, false
+	}
+	for i := len(s) - 1; i >= 0; i-- {
+		if t.Before(s[i].EffectiveFrom) {
+			continue
+		}
+		if s[i].EffectiveTo != nil && !t.Before(*s[i].EffectiveTo) {
+			return entity.TariffResponse{}, false
+		}
+		return s[i], true
+	}
+	return entity.TariffResponse{}, false
+}
+
+// RateAt mengembalikan harga acuan per kWh yang berlaku pada waktu t
+func (s TariffSchedule) RateAt(t time.Time) float64 {
+	return s.VersionAt(t).PricePerKWh
+}
+
+// Cost menghitung biaya energy kWh yang dipakai pada waktu at dengan model tarif yang berlaku saat itu;
+// monthToDate adalah konsumsi bulan berjalan sebelum reading ini (dipakai model block)
+func (s TariffSchedule) Cost(at time.Time, energy, monthToDate float64) float64 {
+	return ModelFor(s.VersionAt(at)).Cost(at, energy, monthToDate)
+}
+
+// MonthlyCost menghitung biaya energi bulanan yang jam pemakaiannya tidak diketahui dengan versi tarif yang
+// berlaku pada awal bulan month. Tarif block dihitung per blok dari awal bulan; tarif flat dan tou memakai
+// harga dasar karena window tou tidak dapat dipilih tanpa jam pemakaian.
+func (s TariffSchedule) MonthlyCost(month time.Time, energy float64) float64 {
+	start, _, _ := BucketBounds(month, BucketMonth)
+	version := s.VersionAt(start)
+	if version.Model == entity.TariffModelBlock && len(version.Blocks) > 0 {
+		return blockModel(version.Blocks).Cost(start, energy, 0)
+	}
+	return energy * version.PricePerKWh
+}
+
+// MonthlyEnergy adalah kebalikan MonthlyCost: energi bulanan yang biayanya sama dengan cost. Bernilai 0 jika
+// tidak ada versi tarif yang berlaku pada awal bulan.
+func (s TariffSchedule) MonthlyEnergy(month time.Time, cost float64) float64 {
+	start, _, _ := BucketBounds(month, BucketMonth)
+	version := s.VersionAt(start)
+	if cost <= 0 {
+		return 0
+	}
+	if version.Model != entity.TariffModelBlock || len(version.Blocks) == 0 {
+		if version.PricePerKWh <= 0 {
+			return 0
+		}
+		return cost / version.PricePerKWh
+	}
+
+	used := 0.0
+	for _, block := range version.Blocks {
+		if block.UpToKWh > 0 && cost > (block.UpToKWh-used)*block.PricePerKWh {
+			cost -= (block.UpToKWh - used) * block.PricePerKWh
+			used = block.UpToKWh
+			continue
+		}
+		return used + cost/block.PricePerKWh
+	}
+	// Blok terakhir yang berbatas tetap dipakai untuk kelebihan konsumsi, seperti blockModel
+	return used + cost/version.Blocks[len(version.Blocks)-1].PricePerKWh
+}
+
+// MonthlyRate adalah harga rata-rata per kWh jika energi bulanan energy dihargai dengan MonthlyCost; energi
+// kosong dihargai sebagai kWh pertama. Bernilai 0 jika tidak ada versi tarif yang berlaku pada awal bulan.
+func (s TariffSchedule) MonthlyRate(month time.Time, energy float64) float64 {
+	start, _, _ := BucketBounds(month, BucketMonth)
+	if _, ok := s.ActiveAt(start); !ok {
+		return 0
+	}
+	if energy <= 0 {
+		energy = 1
+	}
+	return s.MonthlyCost(month, energy) / energy
+}
+
+// TariffModel menghitung biaya konsumsi energi menurut struktur harga sebuah tarif
+type TariffModel interface {
+	Cost(at time.Time, energy, monthToDate float64) float64
+}
+
+// ModelFor memilih model harga sesuai jenis tarif; model yang tidak dikenal dihitung flat
+func ModelFor(tariff entity.TariffResponse) TariffModel {
+	switch tariff.Model {
+	case entity.TariffModelTOU:
+		return touModel{base: tariff.PricePerKWh, windows: tariff.Windows}
+	case entity.TariffModelBlock:
+		if len(tariff.Blocks) > 0 {
+			return blockModel(tariff.Blocks)
+		}
+	}
+	return flatModel(tariff.PricePerKWh)
+}
+
+type flatModel float64
+
+func (m flatModel) Cost(at time.Time, energy, monthToDate float64) float64 {
+	return energy * float64(m)
+}
+
+// touModel memakai harga window yang memuat jam reading (WIB), selain itu harga dasar
+type touModel struct {
+	base    float64
+	windows []entity.TariffWindow
+}
+
+func (m touModel) Cost(at time.Time, energy, monthToDate float64) float64 {
+	hour := at.In(jakarta).Hour()
+	for _, window := range m.windows {
+		if windowContains(window, hour) {
+			return energy * window.PricePerKWh
+		}
+	}
+	return energy * m.base
+}
+
+func windowContains(window entity.TariffWindow, hour int) bool {
+	return hourInRange(window.StartHour, window.EndHour, hour)
+}
+
+// hourInRange bernilai true jika hour berada di [start, end); start lebih besar dari end melewati tengah malam
+func hourInRange(start, end, hour int) bool {
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// blockModel membagi energi reading ke blok-blok konsumsi bulanan mulai dari posisi monthToDate
+type blockModel []entity.TariffBlock
+
+func (m blockModel) Cost(at time.Time, energy, monthToDate float64) float64 {
+	cost, used, remaining := 0.0, monthToDate, energy
+	for _, block := range m {
+		if remaining <= 0 {
+			break
+		}
+		if block.UpToKWh > 0 && used >= block.UpToKWh {
+			continue
+		}
+		take := remaining
+		if block.UpToKWh > 0 {
+			take = math.Min(remaining, block.UpToKWh-used)
+		}
+		cost += take * block.PricePerKWh
+		used += take
+		remaining -= take
+	}
+	// Blok terakhir yang berbatas tetap dipakai untuk kelebihan konsumsi
+	if remaining > 0 {
+		cost += remaining * m[len(m)-1].PricePerKWh
+	}
+	return cost
+}
+
+// ValidateTariffModel memastikan window dan blok sesuai dengan model tarif
+func ValidateTariffModel(model string, windows []entity.TariffWindow, blocks []entity.TariffBlock) error {
+	switch model {
+	case entity.TariffModelFlat:
+		if len(windows) > 0 || len(blocks) > 0 {
+			return errors.New("flat tariff cannot have windows or blocks")
+		}
+	case entity.TariffModelTOU:
+		if len(windows) == 0 || len(blocks) > 0 {
+			return errors.New("tou tariff needs windows and no blocks")
+		}
+		var covered [24]bool
+		for _, window := range windows {
+			if window.StartHour < 0 || window.StartHour > 23 || window.EndHour < 0 || window.EndHour > 24 || window.StartHour == window.EndHour {
+				return fmt.Errorf("invalid window %q: hours must be within 0-24 and start must differ from end", window.Name)
+			}
+			if window.PricePerKWh <= 0 {
+				return fmt.Errorf("window %q price_per_kwh must be greater than 0", window.Name)
+			}
+			for hour := 0; hour < 24; hour++ {
+				if !windowContains(window, hour) {
+					continue
+				}
+				if covered[hour] {
+					return fmt.Errorf("window %q overlaps another window at %02d:00", window.Name, hour)
+				}
+				covered[hour] = true
+			}
+		}
+	case entity.TariffModelBlock:
+		if len(blocks) == 0 || len(windows) > 0 {
+			return errors.New("block tariff needs blocks and no windows")
+		}
+		for i, block := range blocks {
+			if block.PricePerKWh <= 0 {
+				return fmt.Errorf("block %d price_per_kwh must be greater than 0", i+1)
+			}
+			last := i == len(blocks)-1
+			if last && block.UpToKWh != 0 {
+				return errors.New("last block must have up_to_kwh 0 (no limit)")
+			}
+			if !last && (block.UpToKWh <= 0 || (i > 0 && block.UpToKWh <= blocks[i-1].UpToKWh)) {
+				return fmt.Errorf("block %d up_to_kwh must be greater than the previous block", i+1)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported tariff model %q, use flat, tou or block", model)
+	}
+	return nil
+}
+
+// PricedReading adalah reading beserta biayanya menurut tarif; Unpriced bernilai true jika tidak ada versi
+// tarif yang berlaku pada timestamp reading sehingga biayanya 0
+type PricedReading struct {
+	entity.Reading
+	Cost     float64
+	Unpriced bool
+}
+
+// PriceReadings menghitung biaya setiap reading berurutan waktu. Konsumsi bulan berjalan (WIB) diakumulasi
+// dari reading yang diberikan, sehingga untuk tarif block reading harus dimulai dari awal bulan.
+func PriceReadings(readings []entity.Reading, tariff TariffSchedule) []PricedReading {
+	priced := make([]PricedReading, 0, len(readings))
+	for _, reading := range readings {
+		priced = append(priced, PricedReading{Reading: reading})
+	}
+	sort.SliceStable(priced, func(i, j int) bool {
+		return priced[i].Timestamp.Before(priced[j].Timestamp)
+	})
+
+	monthToDate := make(map[time.Time]float64)
+	for i := range priced {
+		month, _, _ := BucketBounds(priced[i].Timestamp, BucketMonth)
+		version, ok := tariff.ActiveAt(priced[i].Timestamp)
+		priced[i].Cost = ModelFor(version).Cost(priced[i].Timestamp, priced[i].Energy, monthToDate[month])
+		priced[i].Unpriced = !ok
+		monthToDate[month] += priced[i].Energy
+	}
+	return priced
+}
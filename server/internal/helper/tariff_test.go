@@ -0,0 +1,156 @@
+package helper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+func TestPriceReadings_TimeOfUse(t *testing.T) {
+	tariff := TariffSchedule{{
+		Code:        "B-3",
+		Model:       entity.TariffModelTOU,
+		PricePerKWh: 1000,
+		Windows:     []entity.TariffWindow{{Name: "WBP", StartHour: 17, EndHour: 22, PricePerKWh: 1400}},
+	}}
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, jakarta)
+	readings := []entity.Reading{
+		{Timestamp: day.Add(16 * time.Hour), Energy: 1},
+		{Timestamp: day.Add(17 * time.Hour), Energy: 1},
+		{Timestamp: day.Add(21*time.Hour + 59*time.Minute), Energy: 1},
+		{Timestamp: day.Add(22 * time.Hour), Energy: 1},
+	}
+
+	priced := PriceReadings(readings, tariff)
+	want := []float64{1000, 1400, 1400, 1000}
+	for i, reading := range priced {
+		if reading.Cost != want[i] {
+			t.Fatalf("reading at %s: expected cost %v, got %v", reading.Timestamp.Format("15:04"), want[i], reading.Cost)
+		}
+	}
+}
+
+func TestPriceReadings_BlocksResetMonthly(t *testing.T) {
+	tariff := TariffSchedule{{
+		Model:       entity.TariffModelBlock,
+		PricePerKWh: 100,
+		Blocks:      []entity.TariffBlock{{UpToKWh: 10, PricePerKWh: 100}, {UpToKWh: 0, PricePerKWh: 200}},
+	}}
+	may := time.Date(2024, 5, 1, 0, 0, 0, 0, jakarta)
+	readings := []entity.Reading{
+		{Timestamp: may.Add(time.Hour), Energy: 8},
+		{Timestamp: may.Add(2 * time.Hour), Energy: 4}, // 2 kWh di blok pertama, 2 kWh di blok kedua
+		{Timestamp: may.Add(3 * time.Hour), Energy: 1},
+		{Timestamp: may.AddDate(0, 1, 0), Energy: 1}, // bulan baru kembali ke blok pertama
+	}
+
+	priced := PriceReadings(readings, tariff)
+	want := []float64{800, 600, 200, 100}
+	for i, reading := range priced {
+		if math.Abs(reading.Cost-want[i]) > 1e-9 {
+			t.Fatalf("reading %d: expected cost %v, got %v", i, want[i], reading.Cost)
+		}
+	}
+}
+
+func TestValidateTariffModel(t *testing.T) {
+	cases := []struct {
+		name    string
+		model   string
+		windows []entity.TariffWindow
+		blocks  []entity.TariffBlock
+		valid   bool
+	}{
+		{name: "flat", model: entity.TariffModelFlat, valid: true},
+		{name: "overnight window", model: entity.TariffModelTOU, windows: []entity.TariffWindow{{StartHour: 22, EndHour: 6, PricePerKWh: 800}}, valid: true},
+		{name: "overlapping windows", model: entity.TariffModelTOU, windows: []entity.TariffWindow{{StartHour: 17, EndHour: 22, PricePerKWh: 1}, {StartHour: 21, EndHour: 23, PricePerKWh: 1}}},
+		{name: "bounded last block", model: entity.TariffModelBlock, blocks: []entity.TariffBlock{{UpToKWh: 10, PricePerKWh: 1}}},
+		{name: "unsorted blocks", model: entity.TariffModelBlock, blocks: []entity.TariffBlock{{UpToKWh: 10, PricePerKWh: 1}, {UpToKWh: 5, PricePerKWh: 1}, {PricePerKWh: 1}}},
+		{name: "flat with blocks", model: entity.TariffModelFlat, blocks: []entity.TariffBlock{{PricePerKWh: 1}}},
+		{name: "unknown model", model: "seasonal"},
+	}
+
+	for _, tc := range cases {
+		err := ValidateTariffModel(tc.model, tc.windows, tc.blocks)
+		if (err == nil) != tc.valid {
+			t.Fatalf("%s: expected valid=%v, got err=%v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+func TestVersionAt_HonorsEffectiveTo(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, jakarta)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, jakarta)
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, jakarta)
+	july := time.Date(2024, 7, 1, 0, 0, 0, 0, jakarta)
+	tariff := NewTariffSchedule([]entity.TariffResponse{
+		{Code: "R-1", PricePerKWh: 1000, EffectiveFrom: march, EffectiveTo: &april},
+		{Code: "R-1", PricePerKWh: 1500, EffectiveFrom: june, EffectiveTo: &july},
+	})
+
+	tests := []struct {
+		at     time.Time
+		rate   float64
+		active bool
+	}{
+		{march.AddDate(0, 0, -1), 0, false},
+		{march.AddDate(0, 0, 10), 1000, true},
+		{april.AddDate(0, 0, 10), 0, false},
+		{june, 1500, true},
+		{july, 0, false},
+	}
+	for _, tt := range tests {
+		version, ok := tariff.ActiveAt(tt.at)
+		if ok != tt.active || version.PricePerKWh != tt.rate || tariff.RateAt(tt.at) != tt.rate {
+			t.Errorf("at %s: got %v (active %v), want %v (active %v)", tt.at.Format("2006-01-02"), version.PricePerKWh, ok, tt.rate, tt.active)
+		}
+	}
+}
+
+func TestMonthlyCost_InvertsToMonthlyEnergy(t *testing.T) {
+	month := time.Date(2024, time.March, 10, 0, 0, 0, 0, jakarta)
+	block := TariffSchedule{{PricePerKWh: 1000, Model: entity.TariffModelBlock, Blocks: []entity.TariffBlock{{UpToKWh: 100, PricePerKWh: 1000}, {PricePerKWh: 2000}}}}
+
+	tests := []struct {
+		name   string
+		tariff TariffSchedule
+		energy float64
+		cost   float64
+	}{
+		// Tarif tou memakai harga dasar, bukan harga window yang memuat pukul 00:00
+		{"tou", TariffSchedule{{PricePerKWh: 1000, Model: entity.TariffModelTOU, Windows: []entity.TariffWindow{{StartHour: 22, EndHour: 6, PricePerKWh: 500}}}}, 150, 150000},
+		{"block first", block, 80, 80000},
+		{"block second", block, 150, 200000},
+	}
+	for _, tt := range tests {
+		if cost := tt.tariff.MonthlyCost(month, tt.energy); !approxEqual(cost, tt.cost) {
+			t.Errorf("%s: MonthlyCost(%v) = %v, want %v", tt.name, tt.energy, cost, tt.cost)
+		}
+		if energy := tt.tariff.MonthlyEnergy(month, tt.cost); !approxEqual(energy, tt.energy) {
+			t.Errorf("%s: MonthlyEnergy(%v) = %v, want %v", tt.name, tt.cost, energy, tt.energy)
+		}
+	}
+	if energy := (TariffSchedule{}).MonthlyEnergy(month, 1000); energy != 0 {
+		t.Errorf("expected no energy without a tariff version, got %v", energy)
+	}
+}
+
+func TestMonthlyRate_AveragesBlocks(t *testing.T) {
+	month := time.Date(2024, time.March, 10, 0, 0, 0, 0, jakarta)
+	block := TariffSchedule{{PricePerKWh: 1000, Model: entity.TariffModelBlock, Blocks: []entity.TariffBlock{{UpToKWh: 100, PricePerKWh: 1000}, {PricePerKWh: 2000}}}}
+
+	if rate := block.MonthlyRate(month, 200); !approxEqual(rate, 1500) {
+		t.Errorf("MonthlyRate(200) = %v, want 1500", rate)
+	}
+	if rate := block.MonthlyRate(month, 0); !approxEqual(rate, 1000) {
+		t.Errorf("MonthlyRate(0) = %v, want first block price 1000", rate)
+	}
+
+	ended := time.Date(2024, time.January, 1, 0, 0, 0, 0, jakarta)
+	expired := TariffSchedule{{PricePerKWh: 1000, EffectiveFrom: ended.AddDate(-1, 0, 0), EffectiveTo: &ended}}
+	if rate := expired.MonthlyRate(month, 100); rate != 0 {
+		t.Errorf("expected no rate without a tariff in effect, got %v", rate)
+	}
+}
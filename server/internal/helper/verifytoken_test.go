@@ -70,3 +70,34 @@ func TestVerifyToken_ExpiredToken(t *testing.T) {
 		t.Fatalf("expected error for expired token, got nil")
 	}
 }
+
+func TestGetUserID_FromGeneratedToken(t *testing.T) {
+	os.Setenv("JWT_SECRET", "testsecret123")
+
+	tok, err := GenerateToken(42, "budi", "budi@example.com", false, false)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	claims, err := VerifyToken(tok)
+	if err != nil {
+		t.Fatalf("expected valid token, got error: %v", err)
+	}
+
+	id, err := GetUserID(claims)
+	if err != nil {
+		t.Fatalf("expected user id, got error: %v", err)
+	}
+	if id != 42 {
+		t.Fatalf("expected user id 42, got %d", id)
+	}
+}
+
+func TestGetUserID_MissingClaim(t *testing.T) {
+	if _, err := GetUserID(jwt.MapClaims{"email": "budi@example.com"}); err == nil {
+		t.Fatalf("expected error for claims without id, got nil")
+	}
+	if _, err := GetUserID(nil); err == nil {
+		t.Fatalf("expected error for nil user data, got nil")
+	}
+}
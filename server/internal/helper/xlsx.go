@@ -0,0 +1,357 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidWorkbook = errors.New("file is not a valid xlsx workbook")
+
+// maxWorkbookPartSize membatasi ukuran tiap file XML di dalam workbook setelah didekompresi
+const maxWorkbookPartSize = 8 * MaxUploadSize
+
+// maxWorkbookColumns adalah jumlah kolom maksimum sheet Excel (kolom XFD)
+const maxWorkbookColumns = 16384
+
+// excelEpoch adalah tanggal dasar nomor seri tanggal Excel (sistem 1900)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxImporter membaca sheet pertama workbook Excel; baris pertama menjadi header
+type xlsxImporter struct{}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Style  int      `xml:"s,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Jenis format angka yang berupa tanggal/waktu
+const (
+	cellNumber = iota
+	cellDate
+	cellTime
+	cellDateTime
+)
+
+func (xlsxImporter) Read(r io.Reader) (*CSVFile, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	var shared xlsxSharedStrings
+	if err := decodeWorkbookPart(parts, "xl/sharedStrings.xml", &shared, false); err != nil {
+		return nil, err
+	}
+	var styles xlsxStyles
+	if err := decodeWorkbookPart(parts, "xl/styles.xml", &styles, false); err != nil {
+		return nil, err
+	}
+	sheetPath, err := firstSheetPath(parts)
+	if err != nil {
+		return nil, err
+	}
+	var sheet xlsxSheet
+	if err := decodeWorkbookPart(parts, sheetPath, &sheet, true); err != nil {
+		return nil, err
+	}
+
+	styleKinds := cellStyleKinds(styles)
+	var header []string
+	var records [][]string
+	var lines []int
+	for i, row := range sheet.Rows {
+		line := row.Index
+		if line == 0 {
+			line = i + 1
+		}
+
+		var record []string
+		for j, cell := range row.Cells {
+			column := j
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if column >= maxWorkbookColumns {
+				return nil, fmt.Errorf("%w: too many columns in row %d", ErrInvalidWorkbook, line)
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("%w: invalid shared string in cell %s", ErrInvalidWorkbook, cell.Ref)
+				}
+				value = shared.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strconv.FormatBool(value == "1")
+			case "", "n":
+				if cell.Style >= 0 && cell.Style < len(styleKinds) {
+					value = formatExcelSerial(value, styleKinds[cell.Style])
+				}
+			}
+			record[column] = strings.TrimSpace(value)
+		}
+
+		if header == nil {
+			header = record
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	return NewTableFile(header, records, lines)
+}
+
+func decodeWorkbookPart(parts map[string]*zip.File, name string, v interface{}, required bool) error {
+	part, ok := parts[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("%w: missing %s", ErrInvalidWorkbook, name)
+		}
+		return nil
+	}
+
+	reader, err := part.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(LimitSize(reader, maxWorkbookPartSize)).Decode(v); err != nil {
+		if errors.Is(err, ErrFileTooLarge) {
+			return err
+		}
+		return fmt.Errorf("%w: %s: %v", ErrInvalidWorkbook, name, err)
+	}
+	return nil
+}
+
+// firstSheetPath mencari file XML sheet pertama melalui workbook.xml dan relasinya
+func firstSheetPath(parts map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	if err := decodeWorkbookPart(parts, "xl/workbook.xml", &workbook, false); err != nil {
+		return "", err
+	}
+	if err := decodeWorkbookPart(parts, "xl/_rels/workbook.xml.rels", &rels, false); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return fallback, nil
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// cellStyleKinds menentukan untuk setiap style sel apakah angkanya berupa tanggal/waktu
+func cellStyleKinds(styles xlsxStyles) []int {
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, numFmt := range styles.NumFmts {
+		custom[numFmt.ID] = numFmt.Code
+	}
+
+	kinds := make([]int, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		switch id := xf.NumFmtID; {
+		case id >= 14 && id <= 17:
+			kinds[i] = cellDate
+		case id >= 18 && id <= 21, id >= 45 && id <= 47:
+			kinds[i] = cellTime
+		case id == 22:
+			kinds[i] = cellDateTime
+		default:
+			if code, ok := custom[id]; ok {
+				kinds[i] = numberFormatKind(code)
+			}
+		}
+	}
+	return kinds
+}
+
+// numberFormatKind mengenali format kustom tanggal/waktu dari kode formatnya (mis. "yyyy-mm-dd hh:mm").
+// Teks dalam tanda kutip, karakter yang di-escape, dan blok [..] seperti kode locale diabaikan.
+func numberFormatKind(code string) int {
+	var b strings.Builder
+	inQuote, inBracket, escaped := false, false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && !inQuote:
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+			// Waktu kumulatif seperti [h]:mm tetap dihitung sebagai waktu
+			if r == 'h' || r == 's' {
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	stripped := b.String()
+
+	hasDate := strings.ContainsAny(stripped, "yd")
+	hasTime := strings.ContainsAny(stripped, "hs")
+	switch {
+	case hasDate && hasTime:
+		return cellDateTime
+	case hasDate:
+		return cellDate
+	case hasTime:
+		return cellTime
+	default:
+		return cellNumber
+	}
+}
+
+// formatExcelSerial mengubah nomor seri tanggal Excel menjadi teks yang dikenali ParseTimestamp
+func formatExcelSerial(value string, kind int) string {
+	if kind == cellNumber {
+		return value
+	}
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	seconds := math.Round(serial * 24 * 60 * 60)
+	t := excelEpoch.Add(time.Duration(seconds) * time.Second)
+	switch kind {
+	case cellDate:
+		return t.Format("2006-01-02")
+	case cellTime:
+		return t.Format("15:04:05")
+	default:
+		return t.Format("2006-01-02 15:04:05")
+	}
+}
+
+// columnIndex mengubah referensi sel (mis. "C12") menjadi indeks kolom berbasis nol.
+// Referensi tanpa huruf kolom, dengan lebih dari 3 huruf, atau melewati kolom XFD ditolak.
+func columnIndex(ref string) (int, error) {
+	index, letters := 0, 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		letters++
+		if letters > 3 {
+			return 0, fmt.Errorf("%w: invalid cell reference %q", ErrInvalidWorkbook, ref)
+		}
+		index = index*26 + int(r-'A'+1)
+	}
+	if letters == 0 || index > maxWorkbookColumns {
+		return 0, fmt.Errorf("%w: invalid cell reference %q", ErrInvalidWorkbook, ref)
+	}
+	return index - 1, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
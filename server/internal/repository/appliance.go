@@ -4,16 +4,22 @@ import (
 	"smart-home-energy-management-server/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// applianceImportColumns adalah kolom yang diperbarui Upsert; target harian tidak termasuk agar tidak tertimpa import
+var applianceImportColumns = []string{"updated_at", "type", "location", "power", "usage_today", "energy", "cost", "status", "connectivity", "average_usage", "priority"}
+
 type ApplianceRepository interface {
 	Create(appliance *entity.Appliance) (*entity.Appliance, error)
-	FindAll() ([]entity.Appliance, error)
-	FindByID(id uint) (*entity.Appliance, error)
-	FindByName(name string) (*entity.Appliance, error)
-	UpdateByID(id uint, appliance *entity.Appliance) (*entity.Appliance, error)
-	DeleteByID(id uint) error
-	Truncate() error
+	FindAll(userID uint) ([]entity.Appliance, error)
+	FindByID(userID, id uint) (*entity.Appliance, error)
+	FindByName(userID uint, name string) (*entity.Appliance, error)
+	Upsert(appliance *entity.Appliance) (*entity.Appliance, error)
+	UpdateByID(userID, id uint, appliance *entity.Appliance) (*entity.Appliance, error)
+	Update(appliance *entity.Appliance) error
+	DeleteByID(userID, id uint) error
+	DeleteByUserID(userID uint) error
 }
 
 type applianceRepository struct {
@@ -31,46 +37,65 @@ func (r *applianceRepository) Create(appliance *entity.Appliance) (*entity.Appli
 	return appliance, nil
 }
 
-func (r *applianceRepository) FindAll() ([]entity.Appliance, error) {
+func (r *applianceRepository) FindAll(userID uint) ([]entity.Appliance, error) {
 	var appliances []entity.Appliance
-	if err := r.db.Find(&appliances).Error; err != nil {
+	if err := r.db.Where("user_id = ?", userID).Find(&appliances).Error; err != nil {
 		return nil, err
 	}
 	return appliances, nil
 }
 
-func (r *applianceRepository) FindByID(id uint) (*entity.Appliance, error) {
+func (r *applianceRepository) FindByID(userID, id uint) (*entity.Appliance, error) {
 	var appliance entity.Appliance
-	if err := r.db.First(&appliance, id).Error; err != nil {
+	if err := r.db.Where("user_id = ?", userID).First(&appliance, id).Error; err != nil {
 		return nil, err
 	}
 	return &appliance, nil
 }
 
-func (r *applianceRepository) FindByName(name string) (*entity.Appliance, error) {
+func (r *applianceRepository) FindByName(userID uint, name string) (*entity.Appliance, error) {
 	var appliance entity.Appliance
-	if err := r.db.Where("name = ?", name).First(&appliance).Error; err != nil {
+	if err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&appliance).Error; err != nil {
 		return nil, err
 	}
 	return &appliance, nil
 }
 
-func (r *applianceRepository) UpdateByID(id uint, appliance *entity.Appliance) (*entity.Appliance, error) {
-	if err := r.db.Model(&entity.Appliance{}).Where("id = ?", id).Updates(appliance).Error; err != nil {
+// Upsert membuat appliance, atau memperbarui kolom import appliance aktif milik user dengan nama yang sama
+func (r *applianceRepository) Upsert(appliance *entity.Appliance) (*entity.Appliance, error) {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns(applianceImportColumns),
+	}).Create(appliance).Error
+	if err != nil {
+		return nil, err
+	}
+	return appliance, nil
+}
+
+func (r *applianceRepository) UpdateByID(userID, id uint, appliance *entity.Appliance) (*entity.Appliance, error) {
+	if err := r.db.Model(&entity.Appliance{}).Where("user_id = ? AND id = ?", userID, id).Updates(appliance).Error; err != nil {
 		return nil, err
 	}
 	return appliance, nil
 }
 
-func (r *applianceRepository) DeleteByID(id uint) error {
-	if err := r.db.Delete(&entity.Appliance{}, id).Error; err != nil {
+// Update menyimpan seluruh kolom appliance, termasuk nilai nol
+func (r *applianceRepository) Update(appliance *entity.Appliance) error {
+	return r.db.Save(appliance).Error
+}
+
+func (r *applianceRepository) DeleteByID(userID, id uint) error {
+	if err := r.db.Where("user_id = ?", userID).Delete(&entity.Appliance{}, id).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *applianceRepository) Truncate() error {
-	if err := r.db.Exec("TRUNCATE TABLE appliances").Error; err != nil {
+// DeleteByUserID menghapus permanen seluruh appliance milik satu user (pengganti TRUNCATE global)
+func (r *applianceRepository) DeleteByUserID(userID uint) error {
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Delete(&entity.Appliance{}).Error; err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	Create(job *entity.ImportJob) (*entity.ImportJob, error)
+	FindByID(userID, id uint) (*entity.ImportJob, error)
+	// Claim memindahkan job queued ke status running dan mengembalikannya; nil berarti job sudah diambil
+	// worker lain, dibatalkan, atau selesai
+	Claim(id uint) (*entity.ImportJob, error)
+	Update(job *entity.ImportJob) (bool, error)
+	Cancel(userID, id uint) (bool, error)
+	// Heartbeat memperpanjang lease job yang sedang berjalan; false berarti job sudah tidak berjalan
+	Heartbeat(id uint) (bool, error)
+	// FindStale mengembalikan job berjalan yang heartbeat terakhirnya sebelum waktu before, serta job queued
+	// yang belum diambil worker sejak sebelum waktu before
+	FindStale(before time.Time) ([]entity.ImportJob, error)
+	// Requeue mengembalikan job yang masih stale ke status queued dan memperbarui updated_at; false berarti
+	// job sudah diambil alih
+	Requeue(id uint, before time.Time) (bool, error)
+}
+
+// activeImportJob adalah status job yang sedang dipegang worker
+var activeImportJob = []string{entity.ImportJobRunning, entity.ImportJobCommitting}
+
+// staleImportJob memilih job berjalan tanpa heartbeat atau job antri yang pesannya mungkin hilang dari antrian
+const staleImportJob = "((status IN ? AND COALESCE(heartbeat_at, started_at) < ?) OR (status = ? AND updated_at < ?))"
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+func (r *importJobRepository) Create(job *entity.ImportJob) (*entity.ImportJob, error) {
+	if err := r.db.Create(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *importJobRepository) FindByID(userID, id uint) (*entity.ImportJob, error) {
+	var job entity.ImportJob
+	if err := r.db.Where("user_id = ?", userID).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importJobRepository) Claim(id uint) (*entity.ImportJob, error) {
+	now := time.Now()
+	result := r.db.Model(&entity.ImportJob{}).
+		Where("id = ? AND status = ?", id, entity.ImportJobQueued).
+		Updates(map[string]interface{}{"status": entity.ImportJobRunning, "started_at": now, "heartbeat_at": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var job entity.ImportJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update menyimpan seluruh kolom job kecuali job sudah dibatalkan; false berarti job tidak lagi diperbarui.
+// heartbeat_at hanya ditulis oleh Heartbeat.
+func (r *importJobRepository) Update(job *entity.ImportJob) (bool, error) {
+	result := r.db.Model(&entity.ImportJob{}).
+		Where("id = ? AND status <> ?", job.ID, entity.ImportJobCanceled).
+		Select("*").Omit("id", "created_at", "heartbeat_at").
+		Updates(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Cancel menandai job yang masih antri atau berjalan sebagai dibatalkan
+func (r *importJobRepository) Cancel(userID, id uint) (bool, error) {
+	result := r.db.Model(&entity.ImportJob{}).
+		Where("id = ? AND user_id = ? AND status IN ?", id, userID, []string{entity.ImportJobQueued, entity.ImportJobRunning}).
+		Updates(map[string]interface{}{"status": entity.ImportJobCanceled, "finished_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *importJobRepository) Heartbeat(id uint) (bool, error) {
+	result := r.db.Model(&entity.ImportJob{}).
+		Where("id = ? AND status IN ?", id, activeImportJob).
+		Update("heartbeat_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *importJobRepository) FindStale(before time.Time) ([]entity.ImportJob, error) {
+	var jobs []entity.ImportJob
+	err := r.db.Where(staleImportJob, activeImportJob, before, entity.ImportJobQueued, before).
+		Order("id").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *importJobRepository) Requeue(id uint, before time.Time) (bool, error) {
+	result := r.db.Model(&entity.ImportJob{}).
+		Where("id = ?", id).Where(staleImportJob, activeImportJob, before, entity.ImportJobQueued, before).
+		Updates(map[string]interface{}{"status": entity.ImportJobQueued, "progress": 0, "started_at": nil, "heartbeat_at": nil})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// importQueueKey adalah list redis bersama untuk antrian import job
+const importQueueKey = "import:queue"
+
+var ErrImportQueueFull = errors.New("import queue is full")
+
+// ImportQueue adalah antrian ID import job yang dikonsumsi oleh worker pool. Isi job (file atau URL) hanya
+// disimpan di database; job yang hilang dari antrian diantrikan ulang oleh sweep ImportJobService.
+type ImportQueue interface {
+	Enqueue(ctx context.Context, jobID uint) error
+	// Dequeue menunggu sampai ada job atau ctx dibatalkan
+	Dequeue(ctx context.Context) (uint, error)
+}
+
+type memoryImportQueue struct {
+	jobs chan uint
+}
+
+// NewMemoryImportQueue membuat antrian di memori proses, cocok untuk satu instance server dan pengujian
+func NewMemoryImportQueue(size int) ImportQueue {
+	return &memoryImportQueue{jobs: make(chan uint, size)}
+}
+
+func (q *memoryImportQueue) Enqueue(ctx context.Context, jobID uint) error {
+	select {
+	case q.jobs <- jobID:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return ErrImportQueueFull
+	}
+}
+
+func (q *memoryImportQueue) Dequeue(ctx context.Context) (uint, error) {
+	select {
+	case jobID := <-q.jobs:
+		return jobID, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+type redisImportQueue struct {
+	redis *redis.Client
+}
+
+// NewRedisImportQueue membuat antrian di redis sehingga job dapat diproses oleh instance server mana pun
+func NewRedisImportQueue(redis *redis.Client) ImportQueue {
+	return &redisImportQueue{redis: redis}
+}
+
+func (q *redisImportQueue) Enqueue(ctx context.Context, jobID uint) error {
+	return q.redis.LPush(ctx, importQueueKey, jobID).Err()
+}
+
+func (q *redisImportQueue) Dequeue(ctx context.Context) (uint, error) {
+	for {
+		// Timeout pendek agar pembatalan ctx tetap diperiksa secara berkala
+		values, err := q.redis.BRPop(ctx, 5*time.Second, importQueueKey).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, err
+		}
+
+		jobID, err := strconv.ParseUint(values[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return uint(jobID), nil
+	}
+}
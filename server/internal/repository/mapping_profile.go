@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type MappingProfileRepository interface {
+	Create(profile *entity.MappingProfile) (*entity.MappingProfile, error)
+	FindAll(userID uint) ([]entity.MappingProfile, error)
+	FindByID(userID, id uint) (*entity.MappingProfile, error)
+	Update(profile *entity.MappingProfile) (*entity.MappingProfile, error)
+	DeleteByID(userID, id uint) error
+}
+
+type mappingProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewMappingProfileRepository(db *gorm.DB) MappingProfileRepository {
+	return &mappingProfileRepository{db: db}
+}
+
+func (r *mappingProfileRepository) Create(profile *entity.MappingProfile) (*entity.MappingProfile, error) {
+	if err := r.db.Create(profile).Error; err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *mappingProfileRepository) FindAll(userID uint) ([]entity.MappingProfile, error) {
+	var profiles []entity.MappingProfile
+	if err := r.db.Where("user_id = ?", userID).Order("name").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *mappingProfileRepository) FindByID(userID, id uint) (*entity.MappingProfile, error) {
+	var profile entity.MappingProfile
+	if err := r.db.Where("user_id = ?", userID).First(&profile, id).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *mappingProfileRepository) Update(profile *entity.MappingProfile) (*entity.MappingProfile, error) {
+	if err := r.db.Save(profile).Error; err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *mappingProfileRepository) DeleteByID(userID, id uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.MappingProfile{}, id).Error
+}
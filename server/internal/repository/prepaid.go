@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type TokenPurchaseRepository interface {
+	Create(purchase *entity.TokenPurchase) (*entity.TokenPurchase, error)
+	FindAll(userID uint) ([]entity.TokenPurchase, error)
+	FindByID(userID, id uint) (*entity.TokenPurchase, error)
+	DeleteByID(userID, id uint) error
+}
+
+type tokenPurchaseRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenPurchaseRepository(db *gorm.DB) TokenPurchaseRepository {
+	return &tokenPurchaseRepository{db: db}
+}
+
+func (r *tokenPurchaseRepository) Create(purchase *entity.TokenPurchase) (*entity.TokenPurchase, error) {
+	if err := r.db.Create(purchase).Error; err != nil {
+		return nil, err
+	}
+	return purchase, nil
+}
+
+func (r *tokenPurchaseRepository) FindAll(userID uint) ([]entity.TokenPurchase, error) {
+	var purchases []entity.TokenPurchase
+	if err := r.db.Where("user_id = ?", userID).Order("purchased_at").Find(&purchases).Error; err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
+
+func (r *tokenPurchaseRepository) FindByID(userID, id uint) (*entity.TokenPurchase, error) {
+	var purchase entity.TokenPurchase
+	if err := r.db.Where("user_id = ?", userID).First(&purchase, id).Error; err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+func (r *tokenPurchaseRepository) DeleteByID(userID, id uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.TokenPurchase{}, id).Error
+}
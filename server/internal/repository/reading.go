@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type ReadingRepository interface {
+	CreateBatch(readings []entity.Reading) error
+	FindByAppliance(userID, applianceID uint, from, to time.Time) ([]entity.Reading, error)
+	FindByUser(userID uint, from, to time.Time) ([]entity.Reading, error)
+	LatestTimestamp(userID uint) (time.Time, error)
+	DeleteByUserID(userID uint) error
+}
+
+type readingRepository struct {
+	db *gorm.DB
+}
+
+func NewReadingRepository(db *gorm.DB) ReadingRepository {
+	return &readingRepository{db: db}
+}
+
+func (r *readingRepository) CreateBatch(readings []entity.Reading) error {
+	if len(readings) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(readings, 500).Error
+}
+
+// FindByAppliance mengambil reading appliance dalam rentang [from, to); waktu nol berarti tanpa batas
+func (r *readingRepository) FindByAppliance(userID, applianceID uint, from, to time.Time) ([]entity.Reading, error) {
+	var readings []entity.Reading
+	query := withTimeRange(r.db.Where("user_id = ? AND appliance_id = ?", userID, applianceID), from, to)
+	if err := query.Order("timestamp").Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
+
+func (r *readingRepository) FindByUser(userID uint, from, to time.Time) ([]entity.Reading, error) {
+	var readings []entity.Reading
+	query := withTimeRange(r.db.Where("user_id = ?", userID), from, to)
+	if err := query.Order("timestamp").Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
+
+// LatestTimestamp mengembalikan timestamp reading terbaru milik user; waktu nol jika belum ada reading
+func (r *readingRepository) LatestTimestamp(userID uint) (time.Time, error) {
+	var latest *time.Time
+	if err := r.db.Model(&entity.Reading{}).Where("user_id = ?", userID).Select("MAX(timestamp)").Scan(&latest).Error; err != nil {
+		return time.Time{}, err
+	}
+	if latest == nil {
+		return time.Time{}, nil
+	}
+	return *latest, nil
+}
+
+func (r *readingRepository) DeleteByUserID(userID uint) error {
+	return r.db.Unscoped().Where("user_id = ?", userID).Delete(&entity.Reading{}).Error
+}
+
+func withTimeRange(query *gorm.DB, from, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		query = query.Where("timestamp >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("timestamp < ?", to)
+	}
+	return query
+}
@@ -2,15 +2,18 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// RedisRepository menyimpan data cache per user; setiap key diberi prefix ID pemiliknya
 type RedisRepository interface {
-	Save(key, value string) error
-	SaveList(key string, values []string) error
-	Get(key string) (string, error)
-	GetList(key string) ([]string, error)
+	Save(owner uint, key, value string) error
+	SaveList(owner uint, key string, values []string) error
+	Get(owner uint, key string) (string, error)
+	GetList(owner uint, key string) ([]string, error)
+	Delete(owner uint, key string) error
 }
 
 type redisRepository struct {
@@ -21,23 +24,31 @@ func NewRedisRepository(redis *redis.Client) RedisRepository {
 	return &redisRepository{redis}
 }
 
-func (r *redisRepository) Save(key, value string) error {
-	return r.redis.Set(context.Background(), key, value, 0).Err()
+func ownerKey(owner uint, key string) string {
+	return fmt.Sprintf("user:%d:%s", owner, key)
 }
 
-func (r *redisRepository) SaveList(key string, values []string) error {
+func (r *redisRepository) Save(owner uint, key, value string) error {
+	return r.redis.Set(context.Background(), ownerKey(owner, key), value, 0).Err()
+}
+
+func (r *redisRepository) SaveList(owner uint, key string, values []string) error {
 	for _, value := range values {
-		if err := r.redis.LPush(context.Background(), key, value).Err(); err != nil {
+		if err := r.redis.LPush(context.Background(), ownerKey(owner, key), value).Err(); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (r *redisRepository) Get(key string) (string, error) {
-	return r.redis.Get(context.Background(), key).Result()
+func (r *redisRepository) Get(owner uint, key string) (string, error) {
+	return r.redis.Get(context.Background(), ownerKey(owner, key)).Result()
+}
+
+func (r *redisRepository) GetList(owner uint, key string) ([]string, error) {
+	return r.redis.LRange(context.Background(), ownerKey(owner, key), 0, -1).Result()
 }
 
-func (r *redisRepository) GetList(key string) ([]string, error) {
-	return r.redis.LRange(context.Background(), key, 0, -1).Result()
+func (r *redisRepository) Delete(owner uint, key string) error {
+	return r.redis.Del(context.Background(), ownerKey(owner, key)).Err()
 }
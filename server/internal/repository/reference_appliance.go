@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// ReferenceApplianceRepository menyimpan katalog produk hemat energi yang sama untuk seluruh user
+type ReferenceApplianceRepository interface {
+	Create(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error)
+	FindAll() ([]entity.ReferenceAppliance, error)
+	FindByID(id uint) (*entity.ReferenceAppliance, error)
+	Update(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error)
+	DeleteByID(id uint) error
+}
+
+type referenceApplianceRepository struct {
+	db *gorm.DB
+}
+
+func NewReferenceApplianceRepository(db *gorm.DB) ReferenceApplianceRepository {
+	return &referenceApplianceRepository{db: db}
+}
+
+func (r *referenceApplianceRepository) Create(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error) {
+	if err := r.db.Create(reference).Error; err != nil {
+		return nil, err
+	}
+	return reference, nil
+}
+
+func (r *referenceApplianceRepository) FindAll() ([]entity.ReferenceAppliance, error) {
+	var references []entity.ReferenceAppliance
+	if err := r.db.Order("type").Order("power").Find(&references).Error; err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+func (r *referenceApplianceRepository) FindByID(id uint) (*entity.ReferenceAppliance, error) {
+	var reference entity.ReferenceAppliance
+	if err := r.db.First(&reference, id).Error; err != nil {
+		return nil, err
+	}
+	return &reference, nil
+}
+
+func (r *referenceApplianceRepository) Update(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error) {
+	if err := r.db.Save(reference).Error; err != nil {
+		return nil, err
+	}
+	return reference, nil
+}
+
+func (r *referenceApplianceRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&entity.ReferenceAppliance{}, id).Error
+}
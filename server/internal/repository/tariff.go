@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"smart-home-energy-management-server/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// TariffRepository menyimpan katalog tarif yang sama untuk seluruh user
+type TariffRepository interface {
+	Create(tariff *entity.Tariff) (*entity.Tariff, error)
+	FindAll() ([]entity.Tariff, error)
+	FindByID(id uint) (*entity.Tariff, error)
+	Update(tariff *entity.Tariff) (*entity.Tariff, error)
+	DeleteByID(id uint) error
+	Count() (int64, error)
+}
+
+type tariffRepository struct {
+	db *gorm.DB
+}
+
+func NewTariffRepository(db *gorm.DB) TariffRepository {
+	return &tariffRepository{db: db}
+}
+
+func (r *tariffRepository) Create(tariff *entity.Tariff) (*entity.Tariff, error) {
+	if err := r.db.Create(tariff).Error; err != nil {
+		return nil, err
+	}
+	return tariff, nil
+}
+
+func (r *tariffRepository) FindAll() ([]entity.Tariff, error) {
+	var tariffs []entity.Tariff
+	if err := r.db.Order("code").Order("effective_from").Find(&tariffs).Error; err != nil {
+		return nil, err
+	}
+	return tariffs, nil
+}
+
+func (r *tariffRepository) FindByID(id uint) (*entity.Tariff, error) {
+	var tariff entity.Tariff
+	if err := r.db.First(&tariff, id).Error; err != nil {
+		return nil, err
+	}
+	return &tariff, nil
+}
+
+func (r *tariffRepository) Update(tariff *entity.Tariff) (*entity.Tariff, error) {
+	if err := r.db.Save(tariff).Error; err != nil {
+		return nil, err
+	}
+	return tariff, nil
+}
+
+func (r *tariffRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&entity.Tariff{}, id).Error
+}
+
+func (r *tariffRepository) Count() (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.Tariff{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TxRepositories adalah repository yang seluruh operasinya berjalan di dalam satu transaksi
+type TxRepositories struct {
+	Appliances ApplianceRepository
+	Readings   ReadingRepository
+}
+
+type Transactor interface {
+	// WithinTransaction menjalankan fn dalam satu transaksi; error dari fn atau pembatalan ctx
+	// membatalkan seluruh perubahan
+	WithinTransaction(ctx context.Context, fn func(repos TxRepositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(repos TxRepositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(TxRepositories{
+			Appliances: NewApplianceRepository(tx),
+			Readings:   NewReadingRepository(tx),
+		})
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 
 	"smart-home-energy-management-server/internal/entity"
 
@@ -48,8 +49,12 @@ func (user_repo *usersRepository) GetUserByID(id string) (entity.Users, error) {
 func (user_repo *usersRepository) GetUserByEmail(email string) (entity.Users, error) {
 	var user entity.Users
 	err := user_repo.db.First(&user, "email = ?", email).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// DIBUNGKUS AGAR PEMANGGIL DAPAT MEMBEDAKAN USER TIDAK ADA DARI KEGAGALAN DATABASE
+		return entity.Users{}, fmt.Errorf("user not found: %w", err)
+	}
 	if err != nil {
-		return entity.Users{}, errors.New("user not found")
+		return entity.Users{}, err
 	}
 
 	return user, nil
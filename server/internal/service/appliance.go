@@ -9,15 +9,15 @@ import (
 )
 
 type ApplianceService interface {
-	CreateAppliance(appliance *entity.ApplianceRequest) (*entity.Appliance, error)
-	GetAllAppliances() ([]entity.ApplianceResponse, error)
-	GetApplianceByID(id uint) (*entity.Appliance, error)
-	UpdateApplianceByID(id uint, appliance *entity.Appliance) (*entity.Appliance, error)
-	DeleteApplianceByID(id uint) error
-	TruncateAppliances() error
-	SetDailyTarget([]helper.DailyTarget) ([]entity.Appliance, error)
-	SaveDailyTarget(data, email string) error
-	GetDailyTarget(email string) (string, error)
+	CreateAppliance(userID uint, appliance *entity.ApplianceRequest) (*entity.Appliance, error)
+	GetAllAppliances(userID uint) ([]entity.ApplianceResponse, error)
+	GetApplianceByID(userID, id uint) (*entity.Appliance, error)
+	UpdateApplianceByID(userID, id uint, appliance *entity.Appliance) (*entity.Appliance, error)
+	DeleteApplianceByID(userID, id uint) error
+	DeleteAllAppliances(userID uint) error
+	SetDailyTarget(userID uint, dailyTargets []helper.DailyTarget) ([]entity.Appliance, error)
+	SaveDailyTarget(userID uint, data string) error
+	GetDailyTarget(userID uint) (string, error)
 }
 
 type applianceService struct {
@@ -29,8 +29,14 @@ func NewApplianceService(applianceRepo repository.ApplianceRepository, redisRepo
 	return &applianceService{applianceRepo: applianceRepo, RedisRepository: redisRepository}
 }
 
-func (s *applianceService) CreateAppliance(applianceReq *entity.ApplianceRequest) (*entity.Appliance, error) {
-	appliance := &entity.Appliance{
+func (s *applianceService) CreateAppliance(userID uint, applianceReq *entity.ApplianceRequest) (*entity.Appliance, error) {
+	return s.applianceRepo.Create(newAppliance(userID, applianceReq))
+}
+
+// newAppliance membentuk entity appliance milik user dari request
+func newAppliance(userID uint, applianceReq *entity.ApplianceRequest) *entity.Appliance {
+	return &entity.Appliance{
+		UserID:       userID,
 		Name:         applianceReq.Name,
 		Type:         applianceReq.Type,
 		Location:     applianceReq.Location,
@@ -43,11 +49,10 @@ func (s *applianceService) CreateAppliance(applianceReq *entity.ApplianceRequest
 		UsageToday:   applianceReq.UsageToday,
 		AverageUsage: applianceReq.AverageUsage,
 	}
-	return s.applianceRepo.Create(appliance)
 }
 
-func (s *applianceService) GetAllAppliances() ([]entity.ApplianceResponse, error) {
-	appliances, err := s.applianceRepo.FindAll()
+func (s *applianceService) GetAllAppliances(userID uint) ([]entity.ApplianceResponse, error) {
+	appliances, err := s.applianceRepo.FindAll(userID)
 	if err != nil {
 		return nil, err
 	}
@@ -74,45 +79,45 @@ func (s *applianceService) GetAllAppliances() ([]entity.ApplianceResponse, error
 	return result, nil
 }
 
-func (s *applianceService) GetApplianceByID(id uint) (*entity.Appliance, error) {
-	return s.applianceRepo.FindByID(id)
+func (s *applianceService) GetApplianceByID(userID, id uint) (*entity.Appliance, error) {
+	return s.applianceRepo.FindByID(userID, id)
 }
 
-func (s *applianceService) UpdateApplianceByID(id uint, appliance *entity.Appliance) (*entity.Appliance, error) {
-	return s.applianceRepo.UpdateByID(id, appliance)
+func (s *applianceService) UpdateApplianceByID(userID, id uint, appliance *entity.Appliance) (*entity.Appliance, error) {
+	return s.applianceRepo.UpdateByID(userID, id, appliance)
 }
 
-func (s *applianceService) DeleteApplianceByID(id uint) error {
-	return s.applianceRepo.DeleteByID(id)
+func (s *applianceService) DeleteApplianceByID(userID, id uint) error {
+	return s.applianceRepo.DeleteByID(userID, id)
 }
 
-func (s *applianceService) TruncateAppliances() error {
-	return s.applianceRepo.Truncate()
+func (s *applianceService) DeleteAllAppliances(userID uint) error {
+	return s.applianceRepo.DeleteByUserID(userID)
 }
 
-func (s *applianceService) SetDailyTarget(dailyTargets []helper.DailyTarget) ([]entity.Appliance, error) {
+func (s *applianceService) SetDailyTarget(userID uint, dailyTargets []helper.DailyTarget) ([]entity.Appliance, error) {
 	// Update daily use target for each appliance use Goroutine
 	wg := sync.WaitGroup{}
 	for _, dailyTarget := range dailyTargets {
 		wg.Add(1)
 		go func(dailyTarget helper.DailyTarget) {
 			defer wg.Done()
-			appliance, err := s.applianceRepo.FindByName(dailyTarget.Name)
+			appliance, err := s.applianceRepo.FindByName(userID, dailyTarget.Name)
 			if err != nil {
 				return
 			}
 			appliance.DailyUseTarget = float64(dailyTarget.Target)
-			_, _ = s.applianceRepo.UpdateByID(appliance.ID, appliance)
+			_, _ = s.applianceRepo.UpdateByID(userID, appliance.ID, appliance)
 		}(dailyTarget)
 	}
 	wg.Wait()
-	return s.applianceRepo.FindAll()
+	return s.applianceRepo.FindAll(userID)
 }
 
-func (s *applianceService) SaveDailyTarget(data, email string) error {
-	return s.RedisRepository.Save("daily-target-"+email, data)
+func (s *applianceService) SaveDailyTarget(userID uint, data string) error {
+	return s.RedisRepository.Save(userID, "daily-target", data)
 }
 
-func (s *applianceService) GetDailyTarget(email string) (string, error) {
-	return s.RedisRepository.Get("daily-target-" + email)
+func (s *applianceService) GetDailyTarget(userID uint) (string, error) {
+	return s.RedisRepository.Get(userID, "daily-target")
 }
@@ -8,10 +8,13 @@ import (
 	"smart-home-energy-management-server/internal/repository"
 )
 
+// tableKey adalah key redis untuk snapshot tabel upload terakhir
+const tableKey = "table"
+
 type FileService interface {
-	SaveTable(table string) error
-	GetTable() (map[string][]string, error)
-	GetRawTable() (string, error)
+	SaveTable(userID uint, table string) error
+	GetTable(userID uint) (map[string][]string, error)
+	GetRawTable(userID uint) (string, error)
 }
 
 type fileService struct {
@@ -22,22 +25,22 @@ func NewFileService(redisRepository repository.RedisRepository) FileService {
 	return &fileService{redisRepository}
 }
 
-func (s *fileService) SaveTable(table string) error {
+func (s *fileService) SaveTable(userID uint, table string) error {
 	if table == "" {
 		return errors.New("table is empty")
 	}
 
 	// log for debugging
 	log.Printf("debug: saving table to redis, len=%d", len(table))
-	if err := s.RedisRepository.Save("table", table); err != nil {
+	if err := s.RedisRepository.Save(userID, tableKey, table); err != nil {
 		log.Printf("error: failed save table to redis: %v", err)
 		return err
 	}
 	return nil
 }
 
-func (s *fileService) GetTable() (map[string][]string, error) {
-	table, err := s.RedisRepository.Get("table")
+func (s *fileService) GetTable(userID uint) (map[string][]string, error) {
+	table, err := s.RedisRepository.Get(userID, tableKey)
 	if err != nil {
 		log.Printf("error: redis get table: %v", err)
 		return nil, err
@@ -53,8 +56,8 @@ func (s *fileService) GetTable() (map[string][]string, error) {
 	return result, nil
 }
 
-func (s *fileService) GetRawTable() (string, error) {
-	table, err := s.RedisRepository.Get("table")
+func (s *fileService) GetRawTable(userID uint) (string, error) {
+	table, err := s.RedisRepository.Get(userID, tableKey)
 	if err != nil {
 		log.Printf("error: redis get raw table: %v", err)
 		return "", err
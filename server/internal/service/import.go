@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ImportService menyimpan hasil upload secara atomik: appliance dan reading di Postgres
+// serta snapshot tabel di redis ikut tersimpan semua atau tidak sama sekali.
+type ImportService interface {
+	ReplaceImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error)
+	MergeImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error)
+}
+
+type importService struct {
+	transactor      repository.Transactor
+	RedisRepository repository.RedisRepository
+}
+
+func NewImportService(transactor repository.Transactor, redisRepository repository.RedisRepository) ImportService {
+	return &importService{transactor: transactor, RedisRepository: redisRepository}
+}
+
+// ReplaceImport mengganti seluruh appliance dan reading milik user dengan isi file
+func (s *importService) ReplaceImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error) {
+	if len(appliances) == 0 {
+		return entity.ImportResult{}, errors.New("no appliances parsed from csv")
+	}
+	result := entity.ImportResult{Mode: entity.ImportModeReplace}
+
+	err := s.commit(ctx, userID, table, func(repos repository.TxRepositories) error {
+		if err := repos.Readings.DeleteByUserID(userID); err != nil {
+			return fmt.Errorf("failed delete readings: %w", err)
+		}
+		if err := repos.Appliances.DeleteByUserID(userID); err != nil {
+			return fmt.Errorf("failed delete appliances: %w", err)
+		}
+
+		applianceIDs := make(map[string]uint, len(appliances))
+		for _, req := range appliances {
+			created, err := repos.Appliances.Upsert(newAppliance(userID, &req))
+			if err != nil {
+				return fmt.Errorf("failed create appliance %s: %w", req.Name, err)
+			}
+			applianceIDs[req.Name] = created.ID
+			result.AppliancesCreated++
+		}
+
+		added, duplicated, err := appendReadings(repos.Readings, userID, applianceIDs, readings)
+		if err != nil {
+			return fmt.Errorf("failed save readings: %w", err)
+		}
+		result.ReadingsAdded, result.ReadingsDuplicated = added, duplicated
+		return nil
+	})
+	if err != nil {
+		return entity.ImportResult{}, err
+	}
+	return result, nil
+}
+
+// MergeImport meng-upsert appliance berdasarkan (user, nama) dan menambahkan reading baru tanpa duplikasi timestamp
+func (s *importService) MergeImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error) {
+	result := entity.ImportResult{Mode: entity.ImportModeMerge}
+
+	err := s.commit(ctx, userID, table, func(repos repository.TxRepositories) error {
+		existing, err := repos.Appliances.FindAll(userID)
+		if err != nil {
+			return err
+		}
+		byName := make(map[string]*entity.Appliance, len(existing))
+		for i := range existing {
+			byName[existing[i].Name] = &existing[i]
+		}
+
+		// Buat appliance baru terlebih dahulu agar reading-nya mendapat ID
+		applianceIDs := make(map[string]uint, len(appliances))
+		for _, req := range appliances {
+			if current, ok := byName[req.Name]; ok {
+				applianceIDs[req.Name] = current.ID
+				continue
+			}
+			created, err := repos.Appliances.Upsert(newAppliance(userID, &req))
+			if err != nil {
+				return err
+			}
+			applianceIDs[req.Name] = created.ID
+			result.AppliancesCreated++
+		}
+
+		added, duplicated, err := appendReadings(repos.Readings, userID, applianceIDs, readings)
+		if err != nil {
+			return err
+		}
+		result.ReadingsAdded, result.ReadingsDuplicated = added, duplicated
+
+		// Perbarui appliance lama hanya jika ada nilai yang berubah
+		for _, req := range appliances {
+			current, ok := byName[req.Name]
+			if !ok {
+				continue
+			}
+			history, err := repos.Readings.FindByAppliance(userID, current.ID, time.Time{}, time.Time{})
+			if err != nil {
+				return err
+			}
+
+			merged := mergeAppliance(*current, req, history)
+			if merged == *current {
+				result.AppliancesUnchanged++
+				continue
+			}
+			if err := repos.Appliances.Update(&merged); err != nil {
+				return err
+			}
+			result.AppliancesUpdated++
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.ImportResult{}, err
+	}
+	return result, nil
+}
+
+// commit menjalankan fn dalam transaksi dan menulis tabel ke key aktif sebagai langkah terakhir di dalam
+// transaksi. Jika commit Postgres gagal setelah tabel ditulis, tabel sebelumnya dikembalikan, sehingga data
+// Postgres dan tabel redis tetap berpasangan dan import yang sudah ter-commit tidak pernah dilaporkan gagal.
+// Kegagalan mengembalikan tabel dicatat di log dan digabung dengan error commit.
+func (s *importService) commit(ctx context.Context, userID uint, table string, fn func(repos repository.TxRepositories) error) error {
+	if table == "" {
+		return errors.New("table is empty")
+	}
+
+	var previous string
+	hadPrevious, published := false, false
+	err := s.transactor.WithinTransaction(ctx, func(repos repository.TxRepositories) error {
+		if err := fn(repos); err != nil {
+			return err
+		}
+
+		current, err := s.RedisRepository.Get(userID, tableKey)
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return fmt.Errorf("failed read table: %w", err)
+		default:
+			previous, hadPrevious = current, true
+		}
+		if err := s.RedisRepository.Save(userID, tableKey, table); err != nil {
+			return fmt.Errorf("failed publish table: %w", err)
+		}
+		published = true
+		return nil
+	})
+	if err != nil && published {
+		var restoreErr error
+		if hadPrevious {
+			restoreErr = s.RedisRepository.Save(userID, tableKey, previous)
+		} else {
+			restoreErr = s.RedisRepository.Delete(userID, tableKey)
+		}
+		if restoreErr != nil {
+			// Tabel redis tidak lagi sesuai dengan data Postgres sampai user mengunggah ulang
+			log.Printf("error: restore table for user %d after failed import: %v", userID, restoreErr)
+			return errors.Join(err, fmt.Errorf("failed restore table: %w", restoreErr))
+		}
+	}
+	return err
+}
+
+// appendReadings menyimpan reading yang timestamp-nya belum ada untuk appliance tersebut,
+// termasuk duplikat di dalam file yang sama
+func appendReadings(readingRepo repository.ReadingRepository, userID uint, applianceIDs map[string]uint, readings []entity.ReadingRequest) (int, int, error) {
+	byAppliance := make(map[uint][]entity.ReadingRequest)
+	for _, reading := range readings {
+		applianceID, ok := applianceIDs[reading.ApplianceName]
+		if !ok {
+			continue
+		}
+		byAppliance[applianceID] = append(byAppliance[applianceID], reading)
+	}
+
+	var rows []entity.Reading
+	duplicated := 0
+	for applianceID, group := range byAppliance {
+		from, to := group[0].Timestamp, group[0].Timestamp
+		for _, reading := range group {
+			if reading.Timestamp.Before(from) {
+				from = reading.Timestamp
+			}
+			if reading.Timestamp.After(to) {
+				to = reading.Timestamp
+			}
+		}
+
+		stored, err := readingRepo.FindByAppliance(userID, applianceID, from, to.Add(time.Nanosecond))
+		if err != nil {
+			return 0, 0, err
+		}
+		seen := make(map[int64]bool, len(stored)+len(group))
+		for _, reading := range stored {
+			seen[reading.Timestamp.UnixNano()] = true
+		}
+
+		for _, reading := range group {
+			key := reading.Timestamp.UnixNano()
+			if seen[key] {
+				duplicated++
+				continue
+			}
+			seen[key] = true
+			rows = append(rows, entity.Reading{
+				UserID:      userID,
+				ApplianceID: applianceID,
+				Timestamp:   reading.Timestamp,
+				Energy:      reading.Energy,
+				Power:       reading.Power,
+				Duration:    reading.Duration,
+			})
+		}
+	}
+
+	if err := readingRepo.CreateBatch(rows); err != nil {
+		return 0, 0, err
+	}
+	return len(rows), duplicated, nil
+}
+
+// mergeAppliance menerapkan nilai dari file ke appliance lama. Nilai kosong di file tidak menimpa data
+// yang sudah ada, dan target harian tidak pernah diubah oleh import.
+func mergeAppliance(current entity.Appliance, req entity.ApplianceRequest, history []entity.Reading) entity.Appliance {
+	merged := current
+	if req.Type != "" {
+		merged.Type = req.Type
+	}
+	if req.Location != "" {
+		merged.Location = req.Location
+	}
+	if req.Status != "" {
+		merged.Status = req.Status
+	}
+	if req.Connectivity != "" {
+		merged.Connectivity = req.Connectivity
+	}
+	if req.Power > 0 {
+		merged.Power = req.Power
+		merged.Priority = req.Priority
+	}
+	if req.Cost > 0 {
+		merged.Cost = req.Cost
+	}
+
+	// Statistik penggunaan dihitung ulang dari seluruh riwayat setelah reading baru ditambahkan
+	if len(history) > 0 {
+		summary := helper.SummarizeReadings(history)
+		merged.UsageToday = summary.DailyHours
+		merged.AverageUsage = summary.AverageHours
+		merged.Energy = summary.DailyEnergy
+	} else {
+		merged.UsageToday = req.UsageToday
+		merged.AverageUsage = req.AverageUsage
+		merged.Energy = req.Energy
+	}
+	return merged
+}
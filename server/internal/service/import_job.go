@@ -0,0 +1,410 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+var (
+	ErrImportJobNotFound   = errors.New("import job not found")
+	ErrImportJobFinished   = errors.New("import job has already finished")
+	ErrImportJobCommitting = errors.New("import job is already saving its data and can no longer be canceled")
+)
+
+const (
+	// importJobHeartbeat adalah jeda worker memperpanjang lease dan memeriksa pembatalan dari instance lain
+	importJobHeartbeat = 10 * time.Second
+	// importJobLease adalah batas job berjalan tanpa heartbeat, atau job antri yang belum diambil worker,
+	// sebelum dianggap ditinggalkan
+	importJobLease = time.Minute
+	// importJobSweep adalah jeda pemeriksaan job yang ditinggalkan
+	importJobSweep = importJobLease / 2
+)
+
+// Progres job per tahap pemrosesan
+const (
+	progressStarted  = 5
+	progressRead     = 40
+	progressParsed   = 60
+	progressImported = 100
+)
+
+// ImportJobService menjalankan upload di background melalui antrian dan worker pool
+type ImportJobService interface {
+	Submit(userID uint, source string, task entity.ImportTask) (entity.ImportJobResponse, error)
+	GetJob(userID, id uint) (entity.ImportJobResponse, error)
+	CancelJob(userID, id uint) (entity.ImportJobResponse, error)
+	// Start menjalankan sejumlah worker dan sweep berkala yang mengantrikan ulang job yang ditinggalkan
+	// sampai ctx dibatalkan
+	Start(ctx context.Context, workers int)
+}
+
+type importJobService struct {
+	jobRepo               repository.ImportJobRepository
+	queue                 repository.ImportQueue
+	importService         ImportService
+	mappingProfileService MappingProfileService
+	applianceService      ApplianceService
+	fetcher               *helper.Fetcher
+	heartbeat             time.Duration
+
+	mu      sync.Mutex
+	running map[uint]context.CancelFunc
+}
+
+func NewImportJobService(jobRepo repository.ImportJobRepository, queue repository.ImportQueue, importService ImportService, mappingProfileService MappingProfileService, applianceService ApplianceService, fetcher *helper.Fetcher) ImportJobService {
+	return &importJobService{
+		jobRepo:               jobRepo,
+		queue:                 queue,
+		importService:         importService,
+		mappingProfileService: mappingProfileService,
+		applianceService:      applianceService,
+		fetcher:               fetcher,
+		heartbeat:             importJobHeartbeat,
+		running:               make(map[uint]context.CancelFunc),
+	}
+}
+
+// Submit mencatat job baru beserta isinya dengan status queued lalu memasukkan ID-nya ke antrian
+func (s *importJobService) Submit(userID uint, source string, task entity.ImportTask) (entity.ImportJobResponse, error) {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return entity.ImportJobResponse{}, err
+	}
+	job, err := s.jobRepo.Create(&entity.ImportJob{
+		UserID:  userID,
+		Status:  entity.ImportJobQueued,
+		Source:  source,
+		Profile: task.Profile,
+		Mode:    task.Mode,
+		DryRun:  task.DryRun,
+		Payload: payload,
+	})
+	if err != nil {
+		return entity.ImportJobResponse{}, err
+	}
+
+	if err := s.queue.Enqueue(context.Background(), job.ID); err != nil {
+		job.Status, job.Message = entity.ImportJobFailed, "failed enqueue job: "+err.Error()
+		s.jobRepo.Update(job)
+		return entity.ImportJobResponse{}, err
+	}
+	return importJobResponse(job), nil
+}
+
+func (s *importJobService) GetJob(userID, id uint) (entity.ImportJobResponse, error) {
+	job, err := s.jobRepo.FindByID(userID, id)
+	if err != nil {
+		return entity.ImportJobResponse{}, ErrImportJobNotFound
+	}
+	return importJobResponse(job), nil
+}
+
+// CancelJob membatalkan job yang masih antri atau sedang berjalan sebelum datanya disimpan. Status canceled
+// di database dibaca worker pada instance mana pun saat heartbeat atau pergantian tahap; worker di instance
+// ini langsung dihentikan.
+func (s *importJobService) CancelJob(userID, id uint) (entity.ImportJobResponse, error) {
+	if _, err := s.jobRepo.FindByID(userID, id); err != nil {
+		return entity.ImportJobResponse{}, ErrImportJobNotFound
+	}
+
+	canceled, err := s.jobRepo.Cancel(userID, id)
+	if err != nil {
+		return entity.ImportJobResponse{}, err
+	}
+	if !canceled {
+		job, err := s.jobRepo.FindByID(userID, id)
+		if err != nil {
+			return entity.ImportJobResponse{}, ErrImportJobNotFound
+		}
+		if job.Status == entity.ImportJobCommitting {
+			return importJobResponse(job), ErrImportJobCommitting
+		}
+		return importJobResponse(job), ErrImportJobFinished
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	return s.GetJob(userID, id)
+}
+
+func (s *importJobService) Start(ctx context.Context, workers int) {
+	s.requeueStale(ctx)
+	go s.sweep(ctx)
+	for i := 0; i < workers; i++ {
+		go s.work(ctx)
+	}
+}
+
+func (s *importJobService) work(ctx context.Context) {
+	for {
+		id, err := s.queue.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("error: dequeue import job: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.process(ctx, id)
+	}
+}
+
+func (s *importJobService) sweep(ctx context.Context) {
+	ticker := time.NewTicker(importJobSweep)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.requeueStale(ctx)
+		}
+	}
+}
+
+// requeueStale mengembalikan job yang heartbeat-nya melewati importJobLease ke antrian, begitu pula job antri
+// yang ID-nya mungkin hilang karena worker mati setelah mengambilnya dari antrian. ID ganda di antrian aman
+// karena hanya satu worker yang berhasil Claim. Import bersifat idempoten (replace mengganti seluruh data,
+// merge melewati reading dengan timestamp yang sama), sehingga job yang terputus saat menyimpan aman diulang.
+func (s *importJobService) requeueStale(ctx context.Context) {
+	before := time.Now().Add(-importJobLease)
+	jobs, err := s.jobRepo.FindStale(before)
+	if err != nil {
+		log.Printf("error: find stale import jobs: %v", err)
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		var task entity.ImportTask
+		if err := json.Unmarshal(job.Payload, &task); err != nil {
+			s.finish(job, entity.ImportJobFailed, "import was interrupted, please upload the file again")
+			continue
+		}
+
+		requeued, err := s.jobRepo.Requeue(job.ID, before)
+		if err != nil {
+			log.Printf("error: requeue import job %d: %v", job.ID, err)
+			continue
+		}
+		if !requeued {
+			continue
+		}
+		if err := s.queue.Enqueue(ctx, job.ID); err != nil {
+			s.finish(job, entity.ImportJobFailed, "failed enqueue job: "+err.Error())
+		}
+	}
+}
+
+// process menjalankan satu job: membaca file, memetakan kolom, lalu menyimpan hasilnya secara atomik
+func (s *importJobService) process(ctx context.Context, id uint) {
+	job, err := s.jobRepo.Claim(id)
+	if err != nil {
+		log.Printf("error: claim import job %d: %v", id, err)
+		return
+	}
+	if job == nil {
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	var task entity.ImportTask
+	if err := json.Unmarshal(job.Payload, &task); err != nil {
+		s.finish(job, entity.ImportJobFailed, "import was interrupted, please upload the file again")
+		return
+	}
+	job.Progress = progressStarted
+	if !s.save(job) {
+		return
+	}
+	go s.keepAlive(jobCtx, cancel, job.ID)
+
+	if err := s.run(jobCtx, job, task); err != nil {
+		if jobCtx.Err() != nil {
+			// Job dibatalkan (status canceled sudah ditulis oleh CancelJob) atau server berhenti;
+			// job yang tertinggal berjalan diantrikan ulang oleh sweep
+			return
+		}
+		s.finish(job, entity.ImportJobFailed, err.Error())
+		return
+	}
+	s.finish(job, entity.ImportJobSucceeded, "")
+}
+
+func (s *importJobService) run(ctx context.Context, job *entity.ImportJob, task entity.ImportTask) error {
+	var source io.ReadCloser = io.NopCloser(bytes.NewReader(task.Content))
+	name := task.Filename
+	if task.URL != "" {
+		name = task.URL
+		body, err := s.fetcher.Fetch(ctx, task.URL)
+		if err != nil {
+			return err
+		}
+		source = body
+	}
+	defer source.Close()
+
+	file, err := helper.ReadUpload(source, task.ContentType, name)
+	if err != nil {
+		return err
+	}
+	job.Progress = progressRead
+	if !s.save(job) {
+		return context.Canceled
+	}
+
+	profile, err := s.mappingProfileService.ResolveProfile(job.UserID, task.Profile, file.Header)
+	if err != nil {
+		return err
+	}
+	parsed, err := file.Parse(profile.ColumnMapping)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.applianceService.GetAllAppliances(job.UserID)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(existing))
+	for _, appliance := range existing {
+		names = append(names, appliance.Name)
+	}
+	report := helper.BuildImportReport(parsed, profile.Name, names, task.DryRun)
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	job.Profile = profile.Name
+	job.RowsRead, job.RowsAccepted, job.RowsRejected = report.RowsRead, report.RowsAccepted, report.RowsRejected
+	job.Report, job.Progress = string(reportJSON), progressParsed
+	if !s.save(job) {
+		return context.Canceled
+	}
+	if task.DryRun {
+		return nil
+	}
+	if len(parsed.Appliances) == 0 {
+		return errors.New("no appliances parsed from csv")
+	}
+
+	table, err := json.Marshal(parsed.Table)
+	if err != nil {
+		return err
+	}
+	importFile := s.importService.ReplaceImport
+	if task.Mode == entity.ImportModeMerge {
+		importFile = s.importService.MergeImport
+	}
+
+	// Setelah status committing tersimpan, CancelJob tidak lagi dapat membatalkan job
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	job.Status = entity.ImportJobCommitting
+	if !s.save(job) {
+		return context.Canceled
+	}
+	result, err := importFile(ctx, job.UserID, string(table), parsed.Appliances, parsed.Readings)
+	if err != nil {
+		return err
+	}
+	job.Result = result
+	return nil
+}
+
+// keepAlive memperbarui heartbeat job secara berkala dan menghentikan job jika statusnya sudah tidak berjalan,
+// mis. dibatalkan lewat instance server lain
+func (s *importJobService) keepAlive(ctx context.Context, cancel context.CancelFunc, id uint) {
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			alive, err := s.jobRepo.Heartbeat(id)
+			if err != nil {
+				log.Printf("error: heartbeat import job %d: %v", id, err)
+				continue
+			}
+			if !alive {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// save memperbarui job dan mengembalikan false jika job sudah dibatalkan
+func (s *importJobService) save(job *entity.ImportJob) bool {
+	updated, err := s.jobRepo.Update(job)
+	if err != nil {
+		log.Printf("error: update import job %d: %v", job.ID, err)
+		return true
+	}
+	return updated
+}
+
+func (s *importJobService) finish(job *entity.ImportJob, status, message string) {
+	finishedAt := time.Now()
+	job.Status, job.Message, job.FinishedAt, job.Payload = status, message, &finishedAt, nil
+	if status == entity.ImportJobSucceeded {
+		job.Progress = progressImported
+	}
+	s.save(job)
+}
+
+func importJobResponse(job *entity.ImportJob) entity.ImportJobResponse {
+	response := entity.ImportJobResponse{
+		ID:           job.ID,
+		Status:       job.Status,
+		Source:       job.Source,
+		Profile:      job.Profile,
+		Mode:         job.Mode,
+		DryRun:       job.DryRun,
+		Progress:     job.Progress,
+		RowsRead:     job.RowsRead,
+		RowsAccepted: job.RowsAccepted,
+		RowsRejected: job.RowsRejected,
+		Result:       job.Result,
+		Message:      job.Message,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+	if job.Report != "" {
+		var report entity.ImportReport
+		if err := json.Unmarshal([]byte(job.Report), &report); err == nil {
+			response.Report = &report
+		}
+	}
+	return response
+}
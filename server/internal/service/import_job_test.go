@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+const jobCSV = `Appliance,Date,Time,Energy_Consumption
+Fridge,2024-01-01,00:00,0.5
+Fridge,2024-01-01,01:00,abc
+TV,2024-01-01,02:00,0.2
+`
+
+type memoryJobRepository struct {
+	mu   sync.Mutex
+	jobs map[uint]entity.ImportJob
+}
+
+func (r *memoryJobRepository) Create(job *entity.ImportJob) (*entity.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.ID = uint(len(r.jobs) + 1)
+	job.UpdatedAt = time.Now()
+	r.jobs[job.ID] = *job
+	return job, nil
+}
+
+func (r *memoryJobRepository) FindByID(userID, id uint) (*entity.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.UserID != userID {
+		return nil, errors.New("record not found")
+	}
+	return &job, nil
+}
+
+func (r *memoryJobRepository) Claim(id uint) (*entity.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != entity.ImportJobQueued {
+		return nil, nil
+	}
+	now := time.Now()
+	job.Status, job.StartedAt, job.HeartbeatAt, job.UpdatedAt = entity.ImportJobRunning, &now, &now, now
+	r.jobs[id] = job
+	return &job, nil
+}
+
+func (r *memoryJobRepository) Update(job *entity.ImportJob) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.jobs[job.ID]
+	if stored.Status == entity.ImportJobCanceled {
+		return false, nil
+	}
+	updated := *job
+	updated.HeartbeatAt, updated.UpdatedAt = stored.HeartbeatAt, time.Now()
+	r.jobs[job.ID] = updated
+	return true, nil
+}
+
+func (r *memoryJobRepository) Cancel(userID, id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	if job.Status != entity.ImportJobQueued && job.Status != entity.ImportJobRunning {
+		return false, nil
+	}
+	job.Status = entity.ImportJobCanceled
+	r.jobs[id] = job
+	return true, nil
+}
+
+func (r *memoryJobRepository) Heartbeat(id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	if job.Status != entity.ImportJobRunning && job.Status != entity.ImportJobCommitting {
+		return false, nil
+	}
+	now := time.Now()
+	job.HeartbeatAt = &now
+	r.jobs[id] = job
+	return true, nil
+}
+
+func (r *memoryJobRepository) stale(job entity.ImportJob, before time.Time) bool {
+	if job.Status == entity.ImportJobQueued {
+		return job.UpdatedAt.Before(before)
+	}
+	if job.Status != entity.ImportJobRunning && job.Status != entity.ImportJobCommitting {
+		return false
+	}
+	last := job.StartedAt
+	if job.HeartbeatAt != nil {
+		last = job.HeartbeatAt
+	}
+	return last != nil && last.Before(before)
+}
+
+func (r *memoryJobRepository) FindStale(before time.Time) ([]entity.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []entity.ImportJob
+	for _, job := range r.jobs {
+		if r.stale(job, before) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memoryJobRepository) Requeue(id uint, before time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	if !r.stale(job, before) {
+		return false, nil
+	}
+	job.Status, job.Progress, job.StartedAt, job.HeartbeatAt, job.UpdatedAt = entity.ImportJobQueued, 0, nil, nil, time.Now()
+	r.jobs[id] = job
+	return true, nil
+}
+
+// stubImportService mencatat import yang dijalankan; jika release diisi, import menunggu sampai release
+// ditutup atau ctx dibatalkan
+type stubImportService struct {
+	started chan struct{}
+	release chan struct{}
+	calls   chan int
+}
+
+func (s *stubImportService) ReplaceImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error) {
+	close(s.started)
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return entity.ImportResult{}, ctx.Err()
+		}
+	}
+	s.calls <- len(appliances)
+	return entity.ImportResult{Mode: entity.ImportModeReplace, AppliancesCreated: len(appliances), ReadingsAdded: len(readings)}, nil
+}
+
+func (s *stubImportService) MergeImport(ctx context.Context, userID uint, table string, appliances []entity.ApplianceRequest, readings []entity.ReadingRequest) (entity.ImportResult, error) {
+	return s.ReplaceImport(ctx, userID, table, appliances, readings)
+}
+
+// stubProfileService memakai deteksi otomatis; jika release diisi, pemetaan menunggu sampai release ditutup
+type stubProfileService struct {
+	MappingProfileService
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s stubProfileService) ResolveProfile(userID uint, requested string, header []string) (entity.MappingProfileResponse, error) {
+	if s.release != nil {
+		close(s.started)
+		<-s.release
+	}
+	return entity.MappingProfileResponse{Name: helper.AutoMappingProfile, ColumnMapping: helper.DetectMapping(header)}, nil
+}
+
+type stubApplianceService struct{ ApplianceService }
+
+func (stubApplianceService) GetAllAppliances(userID uint) ([]entity.ApplianceResponse, error) {
+	return nil, nil
+}
+
+func newMemoryJobRepository() *memoryJobRepository {
+	return &memoryJobRepository{jobs: make(map[uint]entity.ImportJob)}
+}
+
+func newTestJobService(repo *memoryJobRepository, queue repository.ImportQueue, importService ImportService, profileService MappingProfileService) ImportJobService {
+	return NewImportJobService(repo, queue, importService, profileService, stubApplianceService{}, nil)
+}
+
+func waitFor(t *testing.T, done <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s did not happen", what)
+	}
+}
+
+func waitForStatus(t *testing.T, service ImportJobService, id uint, status string) entity.ImportJobResponse {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := service.GetJob(1, id)
+		if err != nil {
+			t.Fatalf("GetJob returned error: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not reach status %s", id, status)
+	return entity.ImportJobResponse{}
+}
+
+func TestImportJob_ProcessedByWorker(t *testing.T) {
+	importService := &stubImportService{started: make(chan struct{}), calls: make(chan int, 1)}
+	service := newTestJobService(newMemoryJobRepository(), repository.NewMemoryImportQueue(10), importService, stubProfileService{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Start(ctx, 2)
+
+	submitted, err := service.Submit(1, "usage.csv", entity.ImportTask{Content: []byte(jobCSV), Mode: entity.ImportModeReplace})
+	if err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	if submitted.Status != entity.ImportJobQueued {
+		t.Fatalf("expected queued job, got %s", submitted.Status)
+	}
+
+	job := waitForStatus(t, service, submitted.ID, entity.ImportJobSucceeded)
+	if job.Progress != 100 || job.RowsRead != 3 || job.RowsAccepted != 2 || job.RowsRejected != 1 {
+		t.Fatalf("unexpected job counts: %+v", job)
+	}
+	if job.Report == nil || len(job.Report.Errors) != 1 || job.Report.Errors[0].Line != 3 {
+		t.Fatalf("expected row error on line 3, got %+v", job.Report)
+	}
+	if job.Result.AppliancesCreated != 2 || <-importService.calls != 2 {
+		t.Fatalf("expected import of 2 appliances, got %+v", job.Result)
+	}
+}
+
+func TestImportJob_CancelRunningFromAnotherInstance(t *testing.T) {
+	repo, queue := newMemoryJobRepository(), repository.NewMemoryImportQueue(10)
+	importService := &stubImportService{started: make(chan struct{}), calls: make(chan int, 1)}
+	profileService := stubProfileService{started: make(chan struct{}), release: make(chan struct{})}
+	worker := newTestJobService(repo, queue, importService, profileService)
+	api := newTestJobService(repo, queue, importService, profileService)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker.Start(ctx, 1)
+
+	submitted, err := api.Submit(1, "usage.csv", entity.ImportTask{Content: []byte(jobCSV)})
+	if err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	waitFor(t, profileService.started, "column mapping")
+
+	// Pembatalan lewat instance lain hanya terlihat oleh worker melalui status di database
+	job, err := api.CancelJob(1, submitted.ID)
+	if err != nil {
+		t.Fatalf("CancelJob returned error: %v", err)
+	}
+	if job.Status != entity.ImportJobCanceled {
+		t.Fatalf("expected canceled job, got %s", job.Status)
+	}
+	close(profileService.release)
+
+	// Worker tidak boleh menyimpan data maupun menimpa status canceled
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-importService.started:
+		t.Fatal("canceled job must not be committed")
+	default:
+	}
+	if job, _ := worker.GetJob(1, submitted.ID); job.Status != entity.ImportJobCanceled {
+		t.Fatalf("expected job to stay canceled, got %s", job.Status)
+	}
+	if _, err := api.CancelJob(1, submitted.ID); !errors.Is(err, ErrImportJobFinished) {
+		t.Fatalf("expected ErrImportJobFinished on second cancel, got %v", err)
+	}
+}
+
+func TestImportJob_CancelRefusedWhileCommitting(t *testing.T) {
+	importService := &stubImportService{started: make(chan struct{}), release: make(chan struct{}), calls: make(chan int, 1)}
+	service := newTestJobService(newMemoryJobRepository(), repository.NewMemoryImportQueue(10), importService, stubProfileService{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Start(ctx, 1)
+
+	submitted, err := service.Submit(1, "usage.csv", entity.ImportTask{Content: []byte(jobCSV)})
+	if err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	waitFor(t, importService.started, "import commit")
+
+	job, err := service.CancelJob(1, submitted.ID)
+	if !errors.Is(err, ErrImportJobCommitting) || job.Status != entity.ImportJobCommitting {
+		t.Fatalf("expected ErrImportJobCommitting for committing job, got %s / %v", job.Status, err)
+	}
+	close(importService.release)
+	waitForStatus(t, service, submitted.ID, entity.ImportJobSucceeded)
+}
+
+func TestImportJob_HeartbeatStopsCanceledJob(t *testing.T) {
+	repo := newMemoryJobRepository()
+	service := newTestJobService(repo, repository.NewMemoryImportQueue(10), nil, stubProfileService{}).(*importJobService)
+	service.heartbeat = time.Millisecond
+	repo.jobs[1] = entity.ImportJob{UserID: 1, Status: entity.ImportJobCanceled}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.keepAlive(ctx, cancel, 1)
+	waitFor(t, ctx.Done(), "job cancellation")
+}
+
+func TestImportJob_RequeuesStaleJobOnStart(t *testing.T) {
+	repo := newMemoryJobRepository()
+	payload, _ := json.Marshal(entity.ImportTask{Content: []byte(jobCSV), Mode: entity.ImportModeReplace})
+	crashed := time.Now().Add(-2 * importJobLease)
+	alive := time.Now()
+	repo.jobs[1] = entity.ImportJob{UserID: 1, Status: entity.ImportJobRunning, StartedAt: &crashed, Payload: payload}
+	repo.jobs[2] = entity.ImportJob{UserID: 1, Status: entity.ImportJobRunning, StartedAt: &crashed, HeartbeatAt: &alive, Payload: payload}
+	repo.jobs[3] = entity.ImportJob{UserID: 1, Status: entity.ImportJobCommitting, StartedAt: &crashed}
+	for id, job := range repo.jobs {
+		job.ID = id
+		repo.jobs[id] = job
+	}
+
+	importService := &stubImportService{started: make(chan struct{}), calls: make(chan int, 1)}
+	service := newTestJobService(repo, repository.NewMemoryImportQueue(10), importService, stubProfileService{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Start(ctx, 1)
+
+	job := waitForStatus(t, service, 1, entity.ImportJobSucceeded)
+	if job.Result.AppliancesCreated != 2 {
+		t.Fatalf("expected requeued job to import 2 appliances, got %+v", job.Result)
+	}
+	if job, _ := service.GetJob(1, 2); job.Status != entity.ImportJobRunning {
+		t.Fatalf("job with a recent heartbeat must not be requeued, got %s", job.Status)
+	}
+	if job, _ := service.GetJob(1, 3); job.Status != entity.ImportJobFailed {
+		t.Fatalf("stale job without payload must fail, got %s", job.Status)
+	}
+}
+
+func TestImportJob_RequeuesQueuedJobLostFromQueue(t *testing.T) {
+	repo := newMemoryJobRepository()
+	payload, _ := json.Marshal(entity.ImportTask{Content: []byte(jobCSV), Mode: entity.ImportModeReplace})
+	lost := entity.ImportJob{UserID: 1, Status: entity.ImportJobQueued, Payload: payload}
+	lost.ID, lost.UpdatedAt = 1, time.Now().Add(-2*importJobLease)
+	repo.jobs[1] = lost
+
+	// ID job tidak ada di antrian, seperti setelah worker mati tepat sesudah Dequeue
+	importService := &stubImportService{started: make(chan struct{}), calls: make(chan int, 1)}
+	service := newTestJobService(repo, repository.NewMemoryImportQueue(10), importService, stubProfileService{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Start(ctx, 1)
+
+	job := waitForStatus(t, service, 1, entity.ImportJobSucceeded)
+	if job.Result.AppliancesCreated != 2 {
+		t.Fatalf("expected recovered job to import 2 appliances, got %+v", job.Result)
+	}
+}
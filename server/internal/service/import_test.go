@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type memoryRedisRepository struct {
+	repository.RedisRepository
+	values map[string]string
+	// failSave membuat Save gagal untuk nilai tersebut
+	failSave string
+}
+
+func (r *memoryRedisRepository) Save(owner uint, key, value string) error {
+	if value == r.failSave {
+		return errors.New("redis unavailable")
+	}
+	r.values[key] = value
+	return nil
+}
+
+func (r *memoryRedisRepository) Get(owner uint, key string) (string, error) {
+	value, ok := r.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (r *memoryRedisRepository) Delete(owner uint, key string) error {
+	delete(r.values, key)
+	return nil
+}
+
+type mergeApplianceRepository struct {
+	repository.ApplianceRepository
+	appliances map[uint]entity.Appliance
+	updated    []uint
+}
+
+func (r *mergeApplianceRepository) FindAll(userID uint) ([]entity.Appliance, error) {
+	var appliances []entity.Appliance
+	for id := uint(1); id <= uint(len(r.appliances)); id++ {
+		if appliance, ok := r.appliances[id]; ok && appliance.UserID == userID {
+			appliances = append(appliances, appliance)
+		}
+	}
+	return appliances, nil
+}
+
+func (r *mergeApplianceRepository) Upsert(appliance *entity.Appliance) (*entity.Appliance, error) {
+	appliance.ID = uint(len(r.appliances) + 1)
+	r.appliances[appliance.ID] = *appliance
+	return appliance, nil
+}
+
+func (r *mergeApplianceRepository) Update(appliance *entity.Appliance) error {
+	r.appliances[appliance.ID] = *appliance
+	r.updated = append(r.updated, appliance.ID)
+	return nil
+}
+
+type mergeReadingRepository struct {
+	repository.ReadingRepository
+	readings []entity.Reading
+}
+
+func (r *mergeReadingRepository) CreateBatch(readings []entity.Reading) error {
+	r.readings = append(r.readings, readings...)
+	return nil
+}
+
+func (r *mergeReadingRepository) FindByAppliance(userID, applianceID uint, from, to time.Time) ([]entity.Reading, error) {
+	var readings []entity.Reading
+	for _, reading := range r.readings {
+		if reading.UserID != userID || reading.ApplianceID != applianceID {
+			continue
+		}
+		if (!from.IsZero() && reading.Timestamp.Before(from)) || (!to.IsZero() && !reading.Timestamp.Before(to)) {
+			continue
+		}
+		readings = append(readings, reading)
+	}
+	return readings, nil
+}
+
+// stubTransactor menjalankan fn lalu mengembalikan commitErr seolah-olah commit Postgres gagal
+type stubTransactor struct {
+	repos     repository.TxRepositories
+	commitErr error
+}
+
+func (t stubTransactor) WithinTransaction(ctx context.Context, fn func(repos repository.TxRepositories) error) error {
+	if err := fn(t.repos); err != nil {
+		return err
+	}
+	return t.commitErr
+}
+
+func noChanges(repos repository.TxRepositories) error { return nil }
+
+func TestImportCommit_PublishesTable(t *testing.T) {
+	redisRepo := &memoryRedisRepository{values: map[string]string{tableKey: "old"}}
+	service := &importService{transactor: stubTransactor{}, RedisRepository: redisRepo}
+
+	if err := service.commit(context.Background(), 1, "new", noChanges); err != nil {
+		t.Fatalf("commit returned error: %v", err)
+	}
+	if redisRepo.values[tableKey] != "new" || len(redisRepo.values) != 1 {
+		t.Fatalf("expected only the published table, got %+v", redisRepo.values)
+	}
+}
+
+func TestImportCommit_RestoresTableWhenCommitFails(t *testing.T) {
+	commitErr := errors.New("commit failed")
+
+	redisRepo := &memoryRedisRepository{values: map[string]string{tableKey: "old"}}
+	service := &importService{transactor: stubTransactor{commitErr: commitErr}, RedisRepository: redisRepo}
+	if err := service.commit(context.Background(), 1, "new", noChanges); !errors.Is(err, commitErr) {
+		t.Fatalf("expected commit error, got %v", err)
+	}
+	if redisRepo.values[tableKey] != "old" {
+		t.Fatalf("expected previous table to be restored, got %q", redisRepo.values[tableKey])
+	}
+
+	redisRepo = &memoryRedisRepository{values: map[string]string{}}
+	service = &importService{transactor: stubTransactor{commitErr: commitErr}, RedisRepository: redisRepo}
+	service.commit(context.Background(), 1, "new", noChanges)
+	if _, ok := redisRepo.values[tableKey]; ok {
+		t.Fatalf("expected no table after failed first import, got %+v", redisRepo.values)
+	}
+}
+
+func TestImportCommit_ReportsFailedRestore(t *testing.T) {
+	commitErr := errors.New("commit failed")
+	redisRepo := &memoryRedisRepository{values: map[string]string{tableKey: "old"}, failSave: "old"}
+	service := &importService{transactor: stubTransactor{commitErr: commitErr}, RedisRepository: redisRepo}
+
+	err := service.commit(context.Background(), 1, "new", noChanges)
+	if !errors.Is(err, commitErr) || err.Error() == commitErr.Error() {
+		t.Fatalf("expected commit error joined with the restore error, got %v", err)
+	}
+}
+
+func TestMergeImport(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	fridge := entity.ApplianceRequest{Name: "Fridge", Type: "Kulkas", Location: "Dapur", Power: 100}
+	tv := entity.ApplianceRequest{Name: "TV", Type: "Hiburan", Location: "Ruang Tamu", Power: 80, AverageUsage: 4}
+	heater := entity.ApplianceRequest{Name: "Heater", Power: 350}
+
+	tests := []struct {
+		name       string
+		appliances []entity.ApplianceRequest
+		readings   []entity.ReadingRequest
+		want       entity.ImportResult
+		wantStored int
+	}{
+		{
+			name:       "skips readings already stored",
+			appliances: []entity.ApplianceRequest{fridge},
+			readings:   []entity.ReadingRequest{{ApplianceName: "Fridge", Timestamp: t0, Energy: 1}, {ApplianceName: "Fridge", Timestamp: t1, Energy: 2}},
+			want:       entity.ImportResult{AppliancesUpdated: 1, ReadingsAdded: 1, ReadingsDuplicated: 1},
+			wantStored: 2,
+		},
+		{
+			name:       "dedupes repeated timestamps in one file",
+			appliances: []entity.ApplianceRequest{heater},
+			readings:   []entity.ReadingRequest{{ApplianceName: "Heater", Timestamp: t1, Energy: 1}, {ApplianceName: "Heater", Timestamp: t1, Energy: 1}},
+			want:       entity.ImportResult{AppliancesCreated: 1, ReadingsAdded: 1, ReadingsDuplicated: 1},
+			wantStored: 2,
+		},
+		{
+			name:       "counts created, updated and unchanged appliances",
+			appliances: []entity.ApplianceRequest{{Name: "Fridge", Location: "Garasi"}, tv, heater},
+			want:       entity.ImportResult{AppliancesCreated: 1, AppliancesUpdated: 1, AppliancesUnchanged: 1},
+			wantStored: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lamp := entity.Appliance{UserID: 1, Name: "Lamp", Type: "Lampu", Power: 10, AverageUsage: 6, DailyUseTarget: 5}
+			appliances := &mergeApplianceRepository{appliances: map[uint]entity.Appliance{}}
+			for _, existing := range []entity.Appliance{*newAppliance(1, &fridge), *newAppliance(1, &tv), lamp} {
+				appliances.Upsert(&existing)
+			}
+			readings := &mergeReadingRepository{readings: []entity.Reading{{UserID: 1, ApplianceID: 1, Timestamp: t0, Energy: 1}}}
+			service := &importService{
+				transactor:      stubTransactor{repos: repository.TxRepositories{Appliances: appliances, Readings: readings}},
+				RedisRepository: &memoryRedisRepository{values: map[string]string{}},
+			}
+
+			got, err := service.MergeImport(context.Background(), 1, "table", tt.appliances, tt.readings)
+			if err != nil {
+				t.Fatalf("MergeImport returned error: %v", err)
+			}
+			tt.want.Mode = entity.ImportModeMerge
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			if len(readings.readings) != tt.wantStored {
+				t.Fatalf("expected %d stored readings, got %+v", tt.wantStored, readings.readings)
+			}
+			// Appliance yang tidak ada di file tidak boleh disentuh
+			stored := appliances.appliances[3]
+			stored.ID = 0
+			if stored != lamp {
+				t.Fatalf("appliance absent from the file was changed: %+v", appliances.appliances[3])
+			}
+			for _, id := range appliances.updated {
+				if id == 3 {
+					t.Fatalf("appliance absent from the file was updated")
+				}
+			}
+		})
+	}
+}
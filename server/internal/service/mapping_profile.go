@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+var (
+	ErrMappingProfileNotFound = errors.New("mapping profile not found")
+	ErrMappingProfileExists   = errors.New("mapping profile name already exists")
+)
+
+type MappingProfileService interface {
+	GetProfiles(userID uint) ([]entity.MappingProfileResponse, error)
+	CreateProfile(userID uint, req entity.MappingProfileRequest) (entity.MappingProfileResponse, error)
+	UpdateProfile(userID, id uint, req entity.MappingProfileRequest) (entity.MappingProfileResponse, error)
+	DeleteProfile(userID, id uint) error
+	ResolveProfile(userID uint, requested string, header []string) (entity.MappingProfileResponse, error)
+}
+
+type mappingProfileService struct {
+	profileRepo repository.MappingProfileRepository
+}
+
+func NewMappingProfileService(profileRepo repository.MappingProfileRepository) MappingProfileService {
+	return &mappingProfileService{profileRepo: profileRepo}
+}
+
+func toMappingProfileResponse(profile entity.MappingProfile) entity.MappingProfileResponse {
+	return entity.MappingProfileResponse{
+		ID:            profile.ID,
+		Name:          profile.Name,
+		Description:   profile.Description,
+		ColumnMapping: profile.ColumnMapping,
+	}
+}
+
+// GetProfiles mengembalikan profil bawaan diikuti profil custom milik user
+func (s *mappingProfileService) GetProfiles(userID uint) ([]entity.MappingProfileResponse, error) {
+	profiles, err := s.profileRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := helper.BuiltinMappingProfiles()
+	for _, profile := range profiles {
+		result = append(result, toMappingProfileResponse(profile))
+	}
+	return result, nil
+}
+
+func (s *mappingProfileService) validate(userID, id uint, req entity.MappingProfileRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.EqualFold(req.Name, helper.AutoMappingProfile) {
+		return ErrMappingProfileExists
+	}
+	if err := helper.ValidateMapping(req.ColumnMapping); err != nil {
+		return err
+	}
+
+	profiles, err := s.GetProfiles(userID)
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, req.Name) && (profile.BuiltIn || profile.ID != id) {
+			return ErrMappingProfileExists
+		}
+	}
+	return nil
+}
+
+func (s *mappingProfileService) CreateProfile(userID uint, req entity.MappingProfileRequest) (entity.MappingProfileResponse, error) {
+	if err := s.validate(userID, 0, req); err != nil {
+		return entity.MappingProfileResponse{}, err
+	}
+
+	profile, err := s.profileRepo.Create(&entity.MappingProfile{
+		UserID:        userID,
+		Name:          strings.TrimSpace(req.Name),
+		Description:   req.Description,
+		ColumnMapping: req.ColumnMapping,
+	})
+	if err != nil {
+		return entity.MappingProfileResponse{}, err
+	}
+	return toMappingProfileResponse(*profile), nil
+}
+
+func (s *mappingProfileService) UpdateProfile(userID, id uint, req entity.MappingProfileRequest) (entity.MappingProfileResponse, error) {
+	profile, err := s.profileRepo.FindByID(userID, id)
+	if err != nil {
+		return entity.MappingProfileResponse{}, ErrMappingProfileNotFound
+	}
+	if err := s.validate(userID, id, req); err != nil {
+		return entity.MappingProfileResponse{}, err
+	}
+
+	profile.Name = strings.TrimSpace(req.Name)
+	profile.Description = req.Description
+	profile.ColumnMapping = req.ColumnMapping
+
+	profile, err = s.profileRepo.Update(profile)
+	if err != nil {
+		return entity.MappingProfileResponse{}, err
+	}
+	return toMappingProfileResponse(*profile), nil
+}
+
+func (s *mappingProfileService) DeleteProfile(userID, id uint) error {
+	if _, err := s.profileRepo.FindByID(userID, id); err != nil {
+		return ErrMappingProfileNotFound
+	}
+	return s.profileRepo.DeleteByID(userID, id)
+}
+
+// ResolveProfile memilih profil berdasarkan ID atau nama; kosong berarti disarankan dari header, "auto" berarti deteksi kolom
+func (s *mappingProfileService) ResolveProfile(userID uint, requested string, header []string) (entity.MappingProfileResponse, error) {
+	requested = strings.TrimSpace(requested)
+
+	if strings.EqualFold(requested, helper.AutoMappingProfile) {
+		return helper.SuggestMappingProfile(header, nil), nil
+	}
+
+	profiles, err := s.GetProfiles(userID)
+	if err != nil {
+		return entity.MappingProfileResponse{}, err
+	}
+
+	if requested == "" {
+		return helper.SuggestMappingProfile(header, profiles), nil
+	}
+
+	id, idErr := strconv.ParseUint(requested, 10, 64)
+	for _, profile := range profiles {
+		if (idErr == nil && !profile.BuiltIn && profile.ID == uint(id)) || strings.EqualFold(profile.Name, requested) {
+			return profile, nil
+		}
+	}
+
+	return entity.MappingProfileResponse{}, ErrMappingProfileNotFound
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+// PowerLimitService memeriksa beban serentak appliance terhadap daya tersambung (VA) rumah
+type PowerLimitService interface {
+	Analyze(userID uint, req entity.PowerLimitRequest, tariff helper.TariffSchedule) (entity.PowerLimitAnalysis, error)
+}
+
+type powerLimitService struct {
+	applianceService ApplianceService
+	readingRepo      repository.ReadingRepository
+	tariffService    TariffService
+}
+
+func NewPowerLimitService(applianceService ApplianceService, readingRepo repository.ReadingRepository, tariffService TariffService) PowerLimitService {
+	return &powerLimitService{applianceService: applianceService, readingRepo: readingRepo, tariffService: tariffService}
+}
+
+// Analyze memakai rencana nyala dari request, atau reading pada tanggal tersebut jika rencana kosong.
+// Tanggal kosong berarti hari reading terakhir (atau hari ini untuk rencana).
+func (s *powerLimitService) Analyze(userID uint, req entity.PowerLimitRequest, tariff helper.TariffSchedule) (entity.PowerLimitAnalysis, error) {
+	options := helper.PowerLimitOptions{
+		VALimit:            req.VALimit,
+		PowerFactor:        req.PowerFactor,
+		ConnectionFeePerVA: helper.DefaultConnectionFeePerVA,
+	}
+	if req.ConnectionFeePerVA != nil {
+		options.ConnectionFeePerVA = *req.ConnectionFeePerVA
+	}
+	switch {
+	case options.VALimit < 0:
+		return entity.PowerLimitAnalysis{}, invalidRequest("va_limit cannot be negative")
+	case options.PowerFactor < 0 || options.PowerFactor > 1:
+		return entity.PowerLimitAnalysis{}, invalidRequest("power_factor must be between 0 and 1")
+	case options.ConnectionFeePerVA < 0:
+		return entity.PowerLimitAnalysis{}, invalidRequest("connection_fee_per_va cannot be negative")
+	}
+
+	day := time.Now()
+	if req.Date != "" {
+		parsed, err := helper.ParseTimestamp(req.Date)
+		if err != nil {
+			return entity.PowerLimitAnalysis{}, invalidRequest("invalid date: %w", err)
+		}
+		day = parsed
+	} else if len(req.Planned) == 0 {
+		latest, err := s.readingRepo.LatestTimestamp(userID)
+		if err != nil {
+			return entity.PowerLimitAnalysis{}, err
+		}
+		if !latest.IsZero() {
+			day = latest
+		}
+	}
+	if options.VALimit == 0 {
+		options.VALimit = helper.ContractedVA(tariff.VersionAt(day))
+	}
+
+	appliances, err := s.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return entity.PowerLimitAnalysis{}, err
+	}
+
+	source := entity.PowerLimitPlanned
+	var usage helper.HourlyUsage
+	if len(req.Planned) > 0 {
+		if usage, err = helper.PlannedHourlyUsage(req.Planned, appliances); err != nil {
+			return entity.PowerLimitAnalysis{}, ValidationError{Err: err}
+		}
+	} else {
+		source = entity.PowerLimitObserved
+		from, to, _ := helper.BucketBounds(day, helper.BucketDay)
+		readings, err := s.readingRepo.FindByUser(userID, from, to)
+		if err != nil {
+			return entity.PowerLimitAnalysis{}, err
+		}
+		usage = helper.ObservedHourlyUsage(readings, appliances)
+	}
+
+	catalogue, err := s.tariffService.GetTariffs(day)
+	if err != nil {
+		return entity.PowerLimitAnalysis{}, err
+	}
+
+	analysis := helper.AnalyzePowerLimit(day, usage, appliances, tariff, catalogue, options)
+	analysis.Source = source
+	return analysis, nil
+}
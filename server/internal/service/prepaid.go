@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+var (
+	ErrTokenPurchaseNotFound = errors.New("token purchase not found")
+	ErrNoTokenPurchases      = errors.New("no token purchases recorded")
+)
+
+// PrepaidService mencatat pembelian token listrik dan memperkirakan kapan saldo kWh habis
+type PrepaidService interface {
+	CreatePurchase(userID uint, req entity.TokenPurchaseRequest) (entity.TokenPurchaseResponse, error)
+	GetPurchases(userID uint) ([]entity.TokenPurchaseResponse, error)
+	DeletePurchase(userID, id uint) error
+	GetForecast(userID uint, windowDays int, threshold *float64) (entity.PrepaidForecast, error)
+}
+
+type prepaidService struct {
+	purchaseRepo repository.TokenPurchaseRepository
+	readingRepo  repository.ReadingRepository
+}
+
+func NewPrepaidService(purchaseRepo repository.TokenPurchaseRepository, readingRepo repository.ReadingRepository) PrepaidService {
+	return &prepaidService{purchaseRepo: purchaseRepo, readingRepo: readingRepo}
+}
+
+func toTokenPurchaseResponse(purchase entity.TokenPurchase) entity.TokenPurchaseResponse {
+	return entity.TokenPurchaseResponse{
+		ID:          purchase.ID,
+		PurchasedAt: purchase.PurchasedAt,
+		Amount:      purchase.Amount,
+		KWh:         purchase.KWh,
+		Token:       purchase.Token,
+	}
+}
+
+// CreatePurchase mencatat pembelian token; purchased_at kosong berarti dibeli sekarang
+func (s *prepaidService) CreatePurchase(userID uint, req entity.TokenPurchaseRequest) (entity.TokenPurchaseResponse, error) {
+	if req.PurchasedAt.IsZero() {
+		req.PurchasedAt = time.Now()
+	}
+	switch {
+	case req.Amount <= 0:
+		return entity.TokenPurchaseResponse{}, errors.New("amount must be greater than 0")
+	case req.KWh <= 0:
+		return entity.TokenPurchaseResponse{}, errors.New("kwh must be greater than 0")
+	case req.PurchasedAt.After(time.Now()):
+		return entity.TokenPurchaseResponse{}, errors.New("purchased_at cannot be in the future")
+	}
+
+	purchase, err := s.purchaseRepo.Create(&entity.TokenPurchase{
+		UserID:      userID,
+		PurchasedAt: req.PurchasedAt,
+		Amount:      req.Amount,
+		KWh:         req.KWh,
+		Token:       strings.TrimSpace(req.Token),
+	})
+	if err != nil {
+		return entity.TokenPurchaseResponse{}, err
+	}
+	return toTokenPurchaseResponse(*purchase), nil
+}
+
+func (s *prepaidService) GetPurchases(userID uint) ([]entity.TokenPurchaseResponse, error) {
+	purchases, err := s.purchaseRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entity.TokenPurchaseResponse{}
+	for _, purchase := range purchases {
+		result = append(result, toTokenPurchaseResponse(purchase))
+	}
+	return result, nil
+}
+
+func (s *prepaidService) DeletePurchase(userID, id uint) error {
+	if _, err := s.purchaseRepo.FindByID(userID, id); err != nil {
+		return ErrTokenPurchaseNotFound
+	}
+	return s.purchaseRepo.DeleteByID(userID, id)
+}
+
+// GetForecast membandingkan token yang dibeli dengan reading yang sudah di-import sejak pembelian pertama
+func (s *prepaidService) GetForecast(userID uint, windowDays int, threshold *float64) (entity.PrepaidForecast, error) {
+	purchases, err := s.purchaseRepo.FindAll(userID)
+	if err != nil {
+		return entity.PrepaidForecast{}, err
+	}
+	if len(purchases) == 0 {
+		return entity.PrepaidForecast{}, ErrNoTokenPurchases
+	}
+
+	readings, err := s.readingRepo.FindByUser(userID, purchases[0].PurchasedAt, time.Time{})
+	if err != nil {
+		return entity.PrepaidForecast{}, err
+	}
+	return helper.ForecastPrepaid(purchases, readings, windowDays, threshold), nil
+}
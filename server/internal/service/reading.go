@@ -0,0 +1,124 @@
+package service
+
+import (
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+type ReadingService interface {
+	GetReadings(userID, applianceID uint, from, to time.Time) ([]entity.ReadingResponse, error)
+	GetUsageSummary(userID, applianceID uint) (entity.UsageSummary, error)
+	GetApplianceUsage(userID, applianceID uint, from, to time.Time, bucket string, tariff helper.TariffSchedule) (entity.UsageReport, error)
+	GetHouseholdUsage(userID uint, from, to time.Time, bucket string, tariff helper.TariffSchedule) (entity.UsageReport, error)
+	// GetLastReadingAt mengembalikan waktu reading terbaru user; waktu nol jika belum ada reading
+	GetLastReadingAt(userID uint) (time.Time, error)
+	// ForecastMonth memperkirakan konsumsi dan tagihan akhir bulan asOf dari riwayat reading rumah
+	ForecastMonth(userID uint, asOf time.Time, tariff helper.TariffSchedule) (entity.MonthForecast, error)
+}
+
+type readingService struct {
+	readingRepo repository.ReadingRepository
+}
+
+func NewReadingService(readingRepo repository.ReadingRepository) ReadingService {
+	return &readingService{readingRepo: readingRepo}
+}
+
+func (s *readingService) GetReadings(userID, applianceID uint, from, to time.Time) ([]entity.ReadingResponse, error) {
+	readings, err := s.readingRepo.FindByAppliance(userID, applianceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entity.ReadingResponse{}
+	for _, reading := range readings {
+		result = append(result, entity.ReadingResponse{
+			ID:          reading.ID,
+			ApplianceID: reading.ApplianceID,
+			Timestamp:   reading.Timestamp,
+			Energy:      reading.Energy,
+			Power:       reading.Power,
+			Duration:    reading.Duration,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *readingService) GetUsageSummary(userID, applianceID uint) (entity.UsageSummary, error) {
+	readings, err := s.readingRepo.FindByAppliance(userID, applianceID, time.Time{}, time.Time{})
+	if err != nil {
+		return entity.UsageSummary{}, err
+	}
+	return helper.SummarizeReadings(readings), nil
+}
+
+// GetApplianceUsage menghitung biaya appliance dari reading seluruh rumah sejak awal bulan, karena harga
+// tarif block bergantung pada total konsumsi rumah di bulan tersebut
+func (s *readingService) GetApplianceUsage(userID, applianceID uint, from, to time.Time, bucket string, tariff helper.TariffSchedule) (entity.UsageReport, error) {
+	priced, err := s.priceHousehold(userID, from, to, tariff)
+	if err != nil {
+		return entity.UsageReport{}, err
+	}
+
+	var readings []helper.PricedReading
+	for _, reading := range priced {
+		if reading.ApplianceID == applianceID {
+			readings = append(readings, reading)
+		}
+	}
+	return helper.AggregatePricedReadings(readings, bucket, tariff)
+}
+
+func (s *readingService) GetHouseholdUsage(userID uint, from, to time.Time, bucket string, tariff helper.TariffSchedule) (entity.UsageReport, error) {
+	priced, err := s.priceHousehold(userID, from, to, tariff)
+	if err != nil {
+		return entity.UsageReport{}, err
+	}
+	return helper.AggregatePricedReadings(priced, bucket, tariff)
+}
+
+// priceHousehold menghitung biaya reading user dalam [from, to) dengan konsumsi bulan berjalan dihitung dari awal bulan from
+func (s *readingService) priceHousehold(userID uint, from, to time.Time, tariff helper.TariffSchedule) ([]helper.PricedReading, error) {
+	monthStart := from
+	if !from.IsZero() {
+		monthStart, _, _ = helper.BucketBounds(from, helper.BucketMonth)
+	}
+	readings, err := s.readingRepo.FindByUser(userID, monthStart, to)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := helper.PriceReadings(readings, tariff)
+	for i := range priced {
+		if !priced[i].Timestamp.Before(from) {
+			return priced[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *readingService) GetLastReadingAt(userID uint) (time.Time, error) {
+	return s.readingRepo.LatestTimestamp(userID)
+}
+
+func (s *readingService) ForecastMonth(userID uint, asOf time.Time, tariff helper.TariffSchedule) (entity.MonthForecast, error) {
+	monthStart, _, err := helper.BucketBounds(asOf, helper.BucketMonth)
+	if err != nil {
+		return entity.MonthForecast{}, err
+	}
+	today, _, _ := helper.BucketBounds(asOf, helper.BucketDay)
+
+	from := today.AddDate(0, 0, -helper.ForecastHistoryDays)
+	if monthStart.Before(from) {
+		from = monthStart
+	}
+	readings, err := s.readingRepo.FindByUser(userID, from, today)
+	if err != nil {
+		return entity.MonthForecast{}, err
+	}
+	return helper.ForecastMonth(readings, tariff, asOf)
+}
@@ -1,14 +1,20 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 
+	"smart-home-energy-management-server/internal/entity"
 	"smart-home-energy-management-server/internal/repository"
+
+	"github.com/go-redis/redis/v8"
 )
 
+// RecommendationService menyimpan rekomendasi terstruktur per kelompok (monthly/daily).
+// Setiap generate menimpa rekomendasi kelompok yang sama.
 type RecommendationService interface {
-	SaveRecommendation(recommendation []string) error
-	GetRecommendation() ([]string, error)
+	SaveRecommendation(userID uint, scope string, recommendations []entity.Recommendation) error
+	GetRecommendation(userID uint) ([]entity.Recommendation, error)
 }
 
 type recommendationService struct {
@@ -19,14 +25,41 @@ func NewRecommendationService(redisRepository repository.RedisRepository) Recomm
 	return &recommendationService{redisRepository}
 }
 
-func (s *recommendationService) SaveRecommendation(recommendation []string) error {
-	if recommendation == nil {
+var recommendationScopes = []string{entity.RecommendationScopeMonthly, entity.RecommendationScopeDaily}
+
+func recommendationKey(scope string) string {
+	return "recommendations:" + scope
+}
+
+func (s *recommendationService) SaveRecommendation(userID uint, scope string, recommendations []entity.Recommendation) error {
+	if recommendations == nil {
 		return errors.New("recommendation is empty")
 	}
 
-	return s.RedisRepository.SaveList("recommendation", recommendation)
+	data, err := json.Marshal(recommendations)
+	if err != nil {
+		return err
+	}
+	return s.RedisRepository.Save(userID, recommendationKey(scope), string(data))
 }
 
-func (s *recommendationService) GetRecommendation() ([]string, error) {
-	return s.RedisRepository.GetList("recommendation")
+// GetRecommendation menggabungkan rekomendasi tersimpan dari semua kelompok
+func (s *recommendationService) GetRecommendation(userID uint) ([]entity.Recommendation, error) {
+	result := []entity.Recommendation{}
+	for _, scope := range recommendationScopes {
+		data, err := s.RedisRepository.Get(userID, recommendationKey(scope))
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var recommendations []entity.Recommendation
+		if err := json.Unmarshal([]byte(data), &recommendations); err != nil {
+			return nil, err
+		}
+		result = append(result, recommendations...)
+	}
+	return result, nil
 }
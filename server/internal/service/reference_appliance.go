@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+var ErrReferenceApplianceNotFound = errors.New("reference appliance not found")
+
+// replacementUsageWindow adalah rentang reading yang dipakai untuk menghitung jam pemakaian harian
+const replacementUsageWindow = 90 * 24 * time.Hour
+
+// ReferenceApplianceService mengelola katalog produk acuan dan menyarankan pengganti yang lebih hemat
+type ReferenceApplianceService interface {
+	// GetReferenceAppliances mengembalikan katalog; applianceType kosong berarti semua jenis
+	GetReferenceAppliances(applianceType string) ([]entity.ReferenceApplianceResponse, error)
+	CreateReferenceAppliance(req entity.ReferenceApplianceRequest) (entity.ReferenceApplianceResponse, error)
+	UpdateReferenceAppliance(id uint, req entity.ReferenceApplianceRequest) (entity.ReferenceApplianceResponse, error)
+	DeleteReferenceAppliance(id uint) error
+	// ImportCSV menambah produk baru dan memperbarui produk dengan type, brand, dan model yang sama
+	ImportCSV(r io.Reader) (entity.CatalogueImportResult, error)
+	// GetReplacements menghitung penghematan tahunan dan balik modal untuk setiap appliance user
+	GetReplacements(userID uint, tariff helper.TariffSchedule) ([]entity.ApplianceReplacement, error)
+}
+
+type referenceApplianceService struct {
+	referenceRepo    repository.ReferenceApplianceRepository
+	applianceService ApplianceService
+	readingRepo      repository.ReadingRepository
+}
+
+func NewReferenceApplianceService(referenceRepo repository.ReferenceApplianceRepository, applianceService ApplianceService, readingRepo repository.ReadingRepository) ReferenceApplianceService {
+	return &referenceApplianceService{referenceRepo: referenceRepo, applianceService: applianceService, readingRepo: readingRepo}
+}
+
+func toReferenceApplianceResponse(reference entity.ReferenceAppliance) entity.ReferenceApplianceResponse {
+	return entity.ReferenceApplianceResponse{
+		ID:         reference.ID,
+		Type:       reference.Type,
+		Brand:      reference.Brand,
+		Model:      reference.ModelName,
+		Power:      reference.Power,
+		StarRating: reference.StarRating,
+		Price:      reference.Price,
+	}
+}
+
+func applyReferenceApplianceRequest(reference *entity.ReferenceAppliance, req entity.ReferenceApplianceRequest) {
+	reference.Type = strings.TrimSpace(req.Type)
+	reference.Brand = strings.TrimSpace(req.Brand)
+	reference.ModelName = strings.TrimSpace(req.Model)
+	reference.Power = req.Power
+	reference.StarRating = req.StarRating
+	reference.Price = req.Price
+}
+
+// sameProduct bernilai true jika dua produk katalog memiliki type, brand, dan model yang sama
+func sameProduct(reference entity.ReferenceAppliance, req entity.ReferenceApplianceRequest) bool {
+	return strings.EqualFold(reference.Type, strings.TrimSpace(req.Type)) &&
+		strings.EqualFold(reference.Brand, strings.TrimSpace(req.Brand)) &&
+		strings.EqualFold(reference.ModelName, strings.TrimSpace(req.Model))
+}
+
+func (s *referenceApplianceService) GetReferenceAppliances(applianceType string) ([]entity.ReferenceApplianceResponse, error) {
+	references, err := s.referenceRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	applianceType = strings.TrimSpace(applianceType)
+	result := []entity.ReferenceApplianceResponse{}
+	for _, reference := range references {
+		if applianceType == "" || strings.EqualFold(reference.Type, applianceType) {
+			result = append(result, toReferenceApplianceResponse(reference))
+		}
+	}
+	return result, nil
+}
+
+func (s *referenceApplianceService) CreateReferenceAppliance(req entity.ReferenceApplianceRequest) (entity.ReferenceApplianceResponse, error) {
+	if err := helper.ValidateReferenceAppliance(req); err != nil {
+		return entity.ReferenceApplianceResponse{}, err
+	}
+
+	var reference entity.ReferenceAppliance
+	applyReferenceApplianceRequest(&reference, req)
+	created, err := s.referenceRepo.Create(&reference)
+	if err != nil {
+		return entity.ReferenceApplianceResponse{}, err
+	}
+	return toReferenceApplianceResponse(*created), nil
+}
+
+func (s *referenceApplianceService) UpdateReferenceAppliance(id uint, req entity.ReferenceApplianceRequest) (entity.ReferenceApplianceResponse, error) {
+	reference, err := s.referenceRepo.FindByID(id)
+	if err != nil {
+		return entity.ReferenceApplianceResponse{}, ErrReferenceApplianceNotFound
+	}
+	if err := helper.ValidateReferenceAppliance(req); err != nil {
+		return entity.ReferenceApplianceResponse{}, err
+	}
+
+	applyReferenceApplianceRequest(reference, req)
+	reference, err = s.referenceRepo.Update(reference)
+	if err != nil {
+		return entity.ReferenceApplianceResponse{}, err
+	}
+	return toReferenceApplianceResponse(*reference), nil
+}
+
+func (s *referenceApplianceService) DeleteReferenceAppliance(id uint) error {
+	if _, err := s.referenceRepo.FindByID(id); err != nil {
+		return ErrReferenceApplianceNotFound
+	}
+	return s.referenceRepo.DeleteByID(id)
+}
+
+func (s *referenceApplianceService) ImportCSV(r io.Reader) (entity.CatalogueImportResult, error) {
+	file, err := helper.ReadCSVFile(r)
+	if err != nil {
+		return entity.CatalogueImportResult{}, err
+	}
+	catalogue, rowErrors, err := helper.ParseReferenceCatalogue(file)
+	if err != nil {
+		return entity.CatalogueImportResult{}, err
+	}
+
+	existing, err := s.referenceRepo.FindAll()
+	if err != nil {
+		return entity.CatalogueImportResult{}, err
+	}
+
+	result := entity.CatalogueImportResult{RowsRead: len(file.Records), Errors: rowErrors}
+	for _, req := range catalogue {
+		matched := -1
+		for i := range existing {
+			if sameProduct(existing[i], req) {
+				matched = i
+				break
+			}
+		}
+
+		if matched >= 0 {
+			applyReferenceApplianceRequest(&existing[matched], req)
+			if _, err := s.referenceRepo.Update(&existing[matched]); err != nil {
+				return result, err
+			}
+			result.Updated++
+			continue
+		}
+
+		var reference entity.ReferenceAppliance
+		applyReferenceApplianceRequest(&reference, req)
+		created, err := s.referenceRepo.Create(&reference)
+		if err != nil {
+			return result, err
+		}
+		// Baris berikutnya dengan produk yang sama memperbarui produk yang baru dibuat
+		existing = append(existing, *created)
+		result.Created++
+	}
+	return result, nil
+}
+
+// GetReplacements memakai rata-rata jam pemakaian per hari tercatat dari reading 90 hari terakhir (lihat
+// readingHoursPerDay); appliance yang jamnya tidak dapat dihitung dari reading memakai AverageUsage yang diisi user
+func (s *referenceApplianceService) GetReplacements(userID uint, tariff helper.TariffSchedule) ([]entity.ApplianceReplacement, error) {
+	appliances, err := s.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return nil, err
+	}
+	catalogue, err := s.GetReferenceAppliances("")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	readings, err := s.readingRepo.FindByUser(userID, now.Add(-replacementUsageWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	byAppliance := make(map[uint][]entity.Reading)
+	for _, reading := range readings {
+		byAppliance[reading.ApplianceID] = append(byAppliance[reading.ApplianceID], reading)
+	}
+
+	pricePerKWh := tariff.RateAt(now)
+	result := []entity.ApplianceReplacement{}
+	for _, appliance := range appliances {
+		replacement := entity.ApplianceReplacement{
+			ApplianceID: appliance.ID,
+			Name:        appliance.Name,
+			Type:        appliance.Type,
+			Power:       appliance.Power,
+			HoursPerDay: appliance.AverageUsage,
+			UsageSource: entity.UsageSourceAverageUsage,
+		}
+		if hours, ok := readingHoursPerDay(byAppliance[appliance.ID], appliance.Power); ok {
+			replacement.HoursPerDay, replacement.UsageSource = hours, entity.UsageSourceReadings
+		}
+		if replacement.HoursPerDay > 24 {
+			replacement.HoursPerDay = 24
+		}
+		replacement.AnnualKWh = replacement.HoursPerDay * float64(appliance.Power) / 1000 * 365
+		replacement.AnnualCost = replacement.AnnualKWh * pricePerKWh
+		replacement.Options = helper.ReplacementOptions(appliance, replacement.HoursPerDay, pricePerKWh, catalogue)
+		result = append(result, replacement)
+	}
+	return result, nil
+}
+
+// readingHoursPerDay menghitung rata-rata jam pemakaian per hari tercatat. Reading tanpa durasi (hanya energi)
+// dihitung sebagai energi dibagi daya appliance; ok false jika tidak ada reading yang jamnya dapat dihitung.
+func readingHoursPerDay(readings []entity.Reading, power int) (float64, bool) {
+	days := make(map[time.Time]bool)
+	var hours float64
+	known := false
+	for _, reading := range readings {
+		day, _, _ := helper.BucketBounds(reading.Timestamp, helper.BucketDay)
+		days[day] = true
+		switch {
+		case reading.Duration > 0:
+			hours += reading.Duration
+			known = true
+		case reading.Energy > 0 && power > 0:
+			hours += reading.Energy / (float64(power) / 1000)
+			known = true
+		}
+	}
+	if !known {
+		return 0, false
+	}
+	return hours / float64(len(days)), true
+}
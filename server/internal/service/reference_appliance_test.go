@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+type memoryReferenceApplianceRepository struct {
+	references []entity.ReferenceAppliance
+}
+
+func (r *memoryReferenceApplianceRepository) Create(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error) {
+	reference.ID = uint(len(r.references) + 1)
+	r.references = append(r.references, *reference)
+	return reference, nil
+}
+
+func (r *memoryReferenceApplianceRepository) FindAll() ([]entity.ReferenceAppliance, error) {
+	return append([]entity.ReferenceAppliance{}, r.references...), nil
+}
+
+func (r *memoryReferenceApplianceRepository) FindByID(id uint) (*entity.ReferenceAppliance, error) {
+	for _, reference := range r.references {
+		if reference.ID == id {
+			return &reference, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryReferenceApplianceRepository) Update(reference *entity.ReferenceAppliance) (*entity.ReferenceAppliance, error) {
+	for i := range r.references {
+		if r.references[i].ID == reference.ID {
+			r.references[i] = *reference
+		}
+	}
+	return reference, nil
+}
+
+func (r *memoryReferenceApplianceRepository) DeleteByID(id uint) error {
+	for i := range r.references {
+		if r.references[i].ID == id {
+			r.references = append(r.references[:i], r.references[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestReferenceApplianceService_ImportUpserts(t *testing.T) {
+	repo := &memoryReferenceApplianceRepository{}
+	service := NewReferenceApplianceService(repo, nil, nil)
+	if _, err := service.CreateReferenceAppliance(entity.ReferenceApplianceRequest{Type: "AC", Brand: "Daikin", Model: "FTKQ25", Power: 700, StarRating: 4, Price: 4000000}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := "type,brand,model,power,star_rating,price\n" +
+		"ac,daikin,ftkq25,650,5,4250000\n" +
+		"Kulkas,LG,GN-B,90,4,2500000\n" +
+		"Kulkas,LG,GN-B,85,5,2600000\n" +
+		"TV,Sharp,X,-5,3,100\n"
+	result, err := service.ImportCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RowsRead != 4 || result.Created != 1 || result.Updated != 2 || len(result.Errors) != 1 {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+
+	acs, _ := service.GetReferenceAppliances("ac")
+	if len(acs) != 1 || acs[0].Power != 650 || acs[0].StarRating != 5 {
+		t.Fatalf("expected existing AC to be updated, got %+v", acs)
+	}
+	fridges, _ := service.GetReferenceAppliances("kulkas")
+	if len(fridges) != 1 || fridges[0].Power != 85 {
+		t.Fatalf("expected duplicate rows to update the same product, got %+v", fridges)
+	}
+
+	if err := service.DeleteReferenceAppliance(99); !errors.Is(err, ErrReferenceApplianceNotFound) {
+		t.Fatalf("expected ErrReferenceApplianceNotFound, got %v", err)
+	}
+}
+
+type replacementApplianceService struct {
+	ApplianceService
+	appliances []entity.ApplianceResponse
+}
+
+func (s replacementApplianceService) GetAllAppliances(userID uint) ([]entity.ApplianceResponse, error) {
+	return s.appliances, nil
+}
+
+type memoryReadingRepository struct {
+	repository.ReadingRepository
+	readings []entity.Reading
+}
+
+func (r memoryReadingRepository) FindByUser(userID uint, from, to time.Time) ([]entity.Reading, error) {
+	return r.readings, nil
+}
+
+func TestReferenceApplianceService_ReplacementHours(t *testing.T) {
+	day := time.Now().Add(-48 * time.Hour)
+	appliances := replacementApplianceService{appliances: []entity.ApplianceResponse{
+		{ID: 1, Name: "AC", Power: 1000, AverageUsage: 8},
+		{ID: 2, Name: "TV", Power: 100, AverageUsage: 5},
+		{ID: 3, Name: "Kulkas", Power: 100, AverageUsage: 24},
+		{ID: 4, Name: "Pompa", AverageUsage: 1},
+	}}
+	readings := memoryReadingRepository{readings: []entity.Reading{
+		// Reading hanya energi: 2 kWh dan 4 kWh pada AC 1.000 W selama dua hari = rata-rata 3 jam/hari
+		{ApplianceID: 1, Timestamp: day, Energy: 2},
+		{ApplianceID: 1, Timestamp: day.Add(24 * time.Hour), Energy: 4},
+		{ApplianceID: 3, Timestamp: day, Energy: 1.2, Duration: 12},
+		{ApplianceID: 4, Timestamp: day, Energy: 0.5},
+	}}
+	service := NewReferenceApplianceService(&memoryReferenceApplianceRepository{}, appliances, readings)
+
+	replacements, err := service.GetReplacements(1, helper.FlatTariff(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		hours  float64
+		source string
+	}{
+		{3, entity.UsageSourceReadings},
+		{5, entity.UsageSourceAverageUsage},
+		{12, entity.UsageSourceReadings},
+		// Tanpa daya, jam tidak dapat dihitung dari energi
+		{1, entity.UsageSourceAverageUsage},
+	}
+	for i, w := range want {
+		if replacements[i].HoursPerDay != w.hours || replacements[i].UsageSource != w.source {
+			t.Errorf("%s: expected %v hours from %s, got %v from %s", replacements[i].Name, w.hours, w.source, replacements[i].HoursPerDay, replacements[i].UsageSource)
+		}
+	}
+}
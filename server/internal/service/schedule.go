@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+)
+
+// ValidationError menandai isi request yang tidak valid sehingga handler membalas 400, bukan 500
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string { return e.Err.Error() }
+
+func (e ValidationError) Unwrap() error { return e.Err }
+
+// invalidRequest membuat ValidationError dengan format seperti fmt.Errorf
+func invalidRequest(format string, args ...any) error {
+	return ValidationError{Err: fmt.Errorf(format, args...)}
+}
+
+// ScheduleService menyusun jadwal pemakaian harian appliance user terhadap harga tarif dan daya tersambung
+type ScheduleService interface {
+	GenerateSchedule(userID uint, req entity.ScheduleRequest, tariff helper.TariffSchedule) (entity.DaySchedule, error)
+}
+
+type scheduleService struct {
+	applianceService ApplianceService
+}
+
+func NewScheduleService(applianceService ApplianceService) ScheduleService {
+	return &scheduleService{applianceService: applianceService}
+}
+
+// GenerateSchedule menjadwalkan seluruh appliance user. Appliance tanpa batasan di request memakai
+// AverageUsage sebagai durasi dan boleh menyala kapan saja.
+func (s *scheduleService) GenerateSchedule(userID uint, req entity.ScheduleRequest, tariff helper.TariffSchedule) (entity.DaySchedule, error) {
+	day := time.Now()
+	if req.Date != "" {
+		parsed, err := helper.ParseTimestamp(req.Date)
+		if err != nil {
+			return entity.DaySchedule{}, invalidRequest("invalid date: %w", err)
+		}
+		day = parsed
+	}
+
+	options := helper.ScheduleOptions{
+		ResolutionMinutes: req.ResolutionMinutes,
+		VALimit:           req.VALimit,
+		PowerFactor:       req.PowerFactor,
+	}
+	if options.ResolutionMinutes == 0 {
+		options.ResolutionMinutes = helper.DefaultScheduleResolution
+	}
+	if options.VALimit == 0 {
+		options.VALimit = helper.ContractedVA(tariff.VersionAt(day))
+	}
+	switch {
+	case options.ResolutionMinutes != 15 && options.ResolutionMinutes != 30 && options.ResolutionMinutes != 60:
+		return entity.DaySchedule{}, invalidRequest("resolution_minutes must be 15, 30, or 60")
+	case options.VALimit < 0:
+		return entity.DaySchedule{}, invalidRequest("va_limit cannot be negative")
+	case options.PowerFactor < 0 || options.PowerFactor > 1:
+		return entity.DaySchedule{}, invalidRequest("power_factor must be between 0 and 1")
+	}
+
+	constraints := make(map[uint]entity.ScheduleApplianceRequest, len(req.Appliances))
+	for _, constraint := range req.Appliances {
+		if constraint.Hours != nil && (*constraint.Hours < 0 || *constraint.Hours > 24) {
+			return entity.DaySchedule{}, invalidRequest("hours for appliance %d must be between 0 and 24", constraint.ApplianceID)
+		}
+		if constraint.MinRunMinutes < 0 {
+			return entity.DaySchedule{}, invalidRequest("min_run_minutes for appliance %d cannot be negative", constraint.ApplianceID)
+		}
+		for _, window := range constraint.AllowedHours {
+			if window.StartHour < 0 || window.StartHour > 23 || window.EndHour < 0 || window.EndHour > 24 || window.StartHour == window.EndHour {
+				return entity.DaySchedule{}, invalidRequest("allowed_hours for appliance %d must be within 0-24 and start must differ from end", constraint.ApplianceID)
+			}
+		}
+		constraints[constraint.ApplianceID] = constraint
+	}
+
+	appliances, err := s.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return entity.DaySchedule{}, err
+	}
+
+	tasks := make([]helper.ScheduleTask, 0, len(appliances))
+	owned := make(map[uint]bool, len(appliances))
+	for _, appliance := range appliances {
+		owned[appliance.ID] = true
+		task := helper.ScheduleTask{Appliance: appliance, Hours: appliance.AverageUsage}
+		if constraint, ok := constraints[appliance.ID]; ok {
+			if constraint.Hours != nil {
+				task.Hours = *constraint.Hours
+			}
+			task.Continuous = constraint.Continuous
+			task.MinRunMinutes = constraint.MinRunMinutes
+			task.AllowedHours = constraint.AllowedHours
+		}
+		tasks = append(tasks, task)
+	}
+	for _, constraint := range req.Appliances {
+		if !owned[constraint.ApplianceID] {
+			return entity.DaySchedule{}, invalidRequest("appliance %d not found", constraint.ApplianceID)
+		}
+	}
+
+	return helper.BuildDaySchedule(day, tasks, tariff, options), nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+)
+
+type failingApplianceService struct {
+	ApplianceService
+	err error
+}
+
+func (s failingApplianceService) GetAllAppliances(userID uint) ([]entity.ApplianceResponse, error) {
+	return nil, s.err
+}
+
+func TestGenerateSchedule_SeparatesValidationErrors(t *testing.T) {
+	tariff := helper.TariffSchedule{}
+	service := NewScheduleService(stubApplianceService{})
+
+	var validation ValidationError
+	_, err := service.GenerateSchedule(1, entity.ScheduleRequest{ResolutionMinutes: 20}, tariff)
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected validation error for resolution, got %v", err)
+	}
+	_, err = service.GenerateSchedule(1, entity.ScheduleRequest{Appliances: []entity.ScheduleApplianceRequest{{ApplianceID: 7}}}, tariff)
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected validation error for unknown appliance, got %v", err)
+	}
+
+	dbErr := errors.New("connection refused")
+	service = NewScheduleService(failingApplianceService{err: dbErr})
+	_, err = service.GenerateSchedule(1, entity.ScheduleRequest{}, tariff)
+	if !errors.Is(err, dbErr) || errors.As(err, &validation) {
+		t.Fatalf("expected database error without validation marker, got %v", err)
+	}
+}
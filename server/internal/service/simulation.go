@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+// SimulationService memproyeksikan biaya listrik jika appliance user diubah, dihapus, atau ditambah,
+// serta jika rumah memakai baterai/UPS
+type SimulationService interface {
+	Simulate(userID uint, req entity.SimulationRequest, tariff helper.TariffSchedule) (entity.SimulationResult, error)
+	SimulateBattery(userID uint, req entity.BatteryRequest, tariff helper.TariffSchedule) (entity.BatteryResult, error)
+}
+
+type simulationService struct {
+	applianceService ApplianceService
+	readingRepo      repository.ReadingRepository
+}
+
+func NewSimulationService(applianceService ApplianceService, readingRepo repository.ReadingRepository) SimulationService {
+	return &simulationService{applianceService: applianceService, readingRepo: readingRepo}
+}
+
+// Simulate tidak mengubah appliance yang tersimpan; perubahan hanya diterapkan pada salinan untuk proyeksi
+func (s *simulationService) Simulate(userID uint, req entity.SimulationRequest, tariff helper.TariffSchedule) (entity.SimulationResult, error) {
+	month := time.Now()
+	if req.Tanggal != "" {
+		parsed, err := helper.ParseTimestamp(req.Tanggal)
+		if err != nil {
+			return entity.SimulationResult{}, fmt.Errorf("invalid tanggal: %w", err)
+		}
+		month = parsed
+	}
+
+	appliances, err := s.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return entity.SimulationResult{}, err
+	}
+	return helper.SimulateChanges(appliances, req.Changes, tariff, month)
+}
+
+// SimulateBattery memakai profil beban per jam dari hourlyLoadProfile dan harga per jam tarif pada hari Tanggal
+func (s *simulationService) SimulateBattery(userID uint, req entity.BatteryRequest, tariff helper.TariffSchedule) (entity.BatteryResult, error) {
+	day := time.Now()
+	if req.Tanggal != "" {
+		parsed, err := helper.ParseTimestamp(req.Tanggal)
+		if err != nil {
+			return entity.BatteryResult{}, fmt.Errorf("invalid tanggal: %w", err)
+		}
+		day = parsed
+	}
+
+	options := helper.BatteryOptions{
+		CapacityKWh:         req.CapacityKWh,
+		MaxChargeKW:         req.MaxChargeKW,
+		MaxDischargeKW:      req.MaxDischargeKW,
+		RoundTripEfficiency: req.RoundTripEfficiency,
+		ReserveSoC:          helper.DefaultReserveSoC,
+		PeakLimitKW:         req.PeakLimitKW,
+	}
+	if req.ReserveSoC != nil {
+		options.ReserveSoC = *req.ReserveSoC
+	}
+	if options.RoundTripEfficiency == 0 {
+		options.RoundTripEfficiency = helper.DefaultRoundTripEfficiency
+	}
+	if options.PeakLimitKW == 0 {
+		options.PeakLimitKW = float64(helper.ContractedVA(tariff.VersionAt(day))) * helper.DefaultPowerFactor / 1000
+	}
+
+	load, source, err := hourlyLoadProfile(userID, s.readingRepo, s.applianceService)
+	if err != nil {
+		return entity.BatteryResult{}, err
+	}
+	appliances, err := s.applianceService.GetAllAppliances(userID)
+	if err != nil {
+		return entity.BatteryResult{}, err
+	}
+
+	result, err := helper.SimulateBattery(load, appliances, tariff, day, options)
+	if err != nil {
+		return entity.BatteryResult{}, err
+	}
+	result.ConsumptionSource = source
+	return result, nil
+}
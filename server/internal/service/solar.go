@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	irradianceKey = "solar:irradiance"
+	// loadProfileDays adalah rentang reading (sebelum reading terakhir) untuk profil konsumsi per jam
+	loadProfileDays = 30
+)
+
+// SolarService memperkirakan manfaat PLTS atap dari pola konsumsi per jam user dan profil iradiasi
+type SolarService interface {
+	Estimate(userID uint, req entity.SolarRequest, tariff helper.TariffSchedule) (entity.SolarSizing, error)
+	// GetIrradiance mengembalikan profil unggahan user, atau profil bawaan jika belum ada
+	GetIrradiance(userID uint) (entity.IrradianceProfile, error)
+	SaveIrradiance(userID uint, r io.Reader) (entity.IrradianceProfile, error)
+	// ResetIrradiance menghapus profil unggahan sehingga profil bawaan dipakai kembali
+	ResetIrradiance(userID uint) error
+}
+
+type solarService struct {
+	applianceService ApplianceService
+	readingRepo      repository.ReadingRepository
+	redisRepo        repository.RedisRepository
+}
+
+func NewSolarService(applianceService ApplianceService, readingRepo repository.ReadingRepository, redisRepo repository.RedisRepository) SolarService {
+	return &solarService{applianceService: applianceService, readingRepo: readingRepo, redisRepo: redisRepo}
+}
+
+func (s *solarService) GetIrradiance(userID uint) (entity.IrradianceProfile, error) {
+	data, err := s.redisRepo.Get(userID, irradianceKey)
+	if errors.Is(err, redis.Nil) {
+		return helper.NewIrradianceProfile(entity.IrradianceDefault, helper.DefaultIrradiance), nil
+	}
+	if err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+
+	var hours [24]float64
+	if err := json.Unmarshal([]byte(data), &hours); err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+	return helper.NewIrradianceProfile(entity.IrradianceUploaded, hours), nil
+}
+
+func (s *solarService) SaveIrradiance(userID uint, r io.Reader) (entity.IrradianceProfile, error) {
+	file, err := helper.ReadCSVFile(r)
+	if err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+	hours, err := helper.ParseIrradianceProfile(file)
+	if err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+	if err := s.redisRepo.Save(userID, irradianceKey, string(data)); err != nil {
+		return entity.IrradianceProfile{}, err
+	}
+	return helper.NewIrradianceProfile(entity.IrradianceUploaded, hours), nil
+}
+
+func (s *solarService) ResetIrradiance(userID uint) error {
+	return s.redisRepo.Delete(userID, irradianceKey)
+}
+
+// hourlyLoadProfile mengembalikan rata-rata konsumsi per jam dari reading 30 hari terakhir; tanpa reading,
+// konsumsi harian dari AverageUsage appliance dibagi rata ke 24 jam
+func hourlyLoadProfile(userID uint, readingRepo repository.ReadingRepository, applianceService ApplianceService) ([24]float64, string, error) {
+	latest, err := readingRepo.LatestTimestamp(userID)
+	if err != nil {
+		return [24]float64{}, "", err
+	}
+	if latest.IsZero() {
+		appliances, err := applianceService.GetAllAppliances(userID)
+		if err != nil {
+			return [24]float64{}, "", err
+		}
+		return helper.AverageConsumptionProfile(appliances), entity.ConsumptionAverageUsage, nil
+	}
+
+	_, to, _ := helper.BucketBounds(latest, helper.BucketDay)
+	readings, err := readingRepo.FindByUser(userID, to.AddDate(0, 0, -loadProfileDays), to)
+	if err != nil {
+		return [24]float64{}, "", err
+	}
+	profile, _ := helper.HourlyConsumptionProfile(readings)
+	return profile, entity.ConsumptionReadings, nil
+}
+
+// Estimate memakai profil konsumsi per jam dari hourlyLoadProfile
+func (s *solarService) Estimate(userID uint, req entity.SolarRequest, tariff helper.TariffSchedule) (entity.SolarSizing, error) {
+	month := time.Now()
+	if req.Tanggal != "" {
+		parsed, err := helper.ParseTimestamp(req.Tanggal)
+		if err != nil {
+			return entity.SolarSizing{}, fmt.Errorf("invalid tanggal: %w", err)
+		}
+		month = parsed
+	}
+
+	options := helper.SolarOptions{
+		KWp:              req.KWp,
+		VALimit:          req.VALimit,
+		CostPerKWp:       helper.DefaultCostPerKWp,
+		PerformanceRatio: req.PerformanceRatio,
+	}
+	if req.CostPerKWp != nil {
+		options.CostPerKWp = *req.CostPerKWp
+	}
+	if req.ExportCredit != nil {
+		options.ExportCredit = *req.ExportCredit
+	}
+	if options.PerformanceRatio == 0 {
+		options.PerformanceRatio = helper.DefaultPerformanceRatio
+	}
+	if options.VALimit == 0 {
+		options.VALimit = helper.ContractedVA(tariff.VersionAt(month))
+	}
+
+	irradiance, err := s.GetIrradiance(userID)
+	if err != nil {
+		return entity.SolarSizing{}, err
+	}
+
+	consumption, source, err := hourlyLoadProfile(userID, s.readingRepo, s.applianceService)
+	if err != nil {
+		return entity.SolarSizing{}, err
+	}
+
+	sizing, err := helper.SizeSolar(consumption, irradiance.Hours, tariff, month, options)
+	if err != nil {
+		return entity.SolarSizing{}, err
+	}
+	sizing.IrradianceSource, sizing.ConsumptionSource = irradiance.Source, source
+	return sizing, nil
+}
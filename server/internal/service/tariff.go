@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/helper"
+	"smart-home-energy-management-server/internal/repository"
+)
+
+var (
+	ErrTariffNotFound = errors.New("tariff not found")
+	ErrTariffExists   = errors.New("tariff code already exists for an overlapping period")
+)
+
+// TariffService mengelola katalog tarif dan mencari tarif yang berlaku untuk perhitungan biaya
+type TariffService interface {
+	// GetTariffs mengembalikan tarif yang berlaku pada at; at kosong berarti seluruh tarif
+	GetTariffs(at time.Time) ([]entity.TariffResponse, error)
+	CreateTariff(req entity.TariffRequest) (entity.TariffResponse, error)
+	UpdateTariff(id uint, req entity.TariffRequest) (entity.TariffResponse, error)
+	DeleteTariff(id uint) error
+	// GetSchedule mengembalikan seluruh versi tarif untuk kode atau nama golongan
+	GetSchedule(code string) (helper.TariffSchedule, error)
+	// Seed mengisi katalog bawaan jika belum ada tarif sama sekali
+	Seed() error
+}
+
+type tariffService struct {
+	tariffRepo repository.TariffRepository
+}
+
+func NewTariffService(tariffRepo repository.TariffRepository) TariffService {
+	return &tariffService{tariffRepo: tariffRepo}
+}
+
+func toTariffResponse(tariff entity.Tariff) entity.TariffResponse {
+	return entity.TariffResponse{
+		ID:            tariff.ID,
+		Code:          tariff.Code,
+		Name:          tariff.Name,
+		MinVA:         tariff.MinVA,
+		MaxVA:         tariff.MaxVA,
+		PricePerKWh:   tariff.PricePerKWh,
+		Currency:      tariff.Currency,
+		Model:         tariff.PricingModel,
+		Windows:       tariff.Windows,
+		Blocks:        tariff.Blocks,
+		EffectiveFrom: tariff.EffectiveFrom,
+		EffectiveTo:   tariff.EffectiveTo,
+	}
+}
+
+// activeAt bernilai true jika tarif berlaku pada waktu at (EffectiveTo bersifat eksklusif)
+func activeAt(tariff entity.Tariff, at time.Time) bool {
+	if at.Before(tariff.EffectiveFrom) {
+		return false
+	}
+	return tariff.EffectiveTo == nil || at.Before(*tariff.EffectiveTo)
+}
+
+// overlaps bernilai true jika dua periode berlaku saling beririsan
+func overlaps(a, b entity.Tariff) bool {
+	aEndsAfterB := a.EffectiveTo == nil || a.EffectiveTo.After(b.EffectiveFrom)
+	bEndsAfterA := b.EffectiveTo == nil || b.EffectiveTo.After(a.EffectiveFrom)
+	return aEndsAfterB && bEndsAfterA
+}
+
+func (s *tariffService) GetTariffs(at time.Time) ([]entity.TariffResponse, error) {
+	tariffs, err := s.tariffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entity.TariffResponse{}
+	for _, tariff := range tariffs {
+		if at.IsZero() || activeAt(tariff, at) {
+			result = append(result, toTariffResponse(tariff))
+		}
+	}
+	return result, nil
+}
+
+func (s *tariffService) validate(id uint, tariff entity.Tariff) error {
+	switch {
+	case tariff.Code == "":
+		return errors.New("code is required")
+	case tariff.Name == "":
+		return errors.New("name is required")
+	case tariff.PricePerKWh <= 0:
+		return errors.New("price_per_kwh must be greater than 0")
+	case tariff.MinVA < 0 || tariff.MaxVA < 0:
+		return errors.New("min_va and max_va cannot be negative")
+	case tariff.MaxVA != 0 && tariff.MaxVA < tariff.MinVA:
+		return errors.New("max_va must be 0 (no limit) or at least min_va")
+	case len(tariff.Currency) != 3:
+		return errors.New("currency must be a 3 letter ISO 4217 code")
+	case tariff.EffectiveFrom.IsZero():
+		return errors.New("effective_from is required")
+	case tariff.EffectiveTo != nil && !tariff.EffectiveTo.After(tariff.EffectiveFrom):
+		return errors.New("effective_to must be after effective_from")
+	}
+	if err := helper.ValidateTariffModel(tariff.PricingModel, tariff.Windows, tariff.Blocks); err != nil {
+		return err
+	}
+
+	tariffs, err := s.tariffRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	for _, existing := range tariffs {
+		if existing.ID != id && strings.EqualFold(existing.Code, tariff.Code) && overlaps(existing, tariff) {
+			return ErrTariffExists
+		}
+	}
+	return nil
+}
+
+// applyTariffRequest menyalin request ke entity dengan kode, mata uang, dan model yang dinormalisasi
+func applyTariffRequest(tariff *entity.Tariff, req entity.TariffRequest) {
+	tariff.Code = strings.TrimSpace(req.Code)
+	tariff.Name = strings.TrimSpace(req.Name)
+	tariff.MinVA = req.MinVA
+	tariff.MaxVA = req.MaxVA
+	tariff.PricePerKWh = req.PricePerKWh
+	tariff.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if tariff.Currency == "" {
+		tariff.Currency = helper.CurrencyIDR
+	}
+	tariff.PricingModel = strings.ToLower(strings.TrimSpace(req.Model))
+	if tariff.PricingModel == "" {
+		tariff.PricingModel = entity.TariffModelFlat
+	}
+	tariff.Windows = req.Windows
+	tariff.Blocks = req.Blocks
+	tariff.EffectiveFrom = req.EffectiveFrom
+	tariff.EffectiveTo = req.EffectiveTo
+}
+
+func (s *tariffService) CreateTariff(req entity.TariffRequest) (entity.TariffResponse, error) {
+	var tariff entity.Tariff
+	applyTariffRequest(&tariff, req)
+	if err := s.validate(0, tariff); err != nil {
+		return entity.TariffResponse{}, err
+	}
+
+	created, err := s.tariffRepo.Create(&tariff)
+	if err != nil {
+		return entity.TariffResponse{}, err
+	}
+	return toTariffResponse(*created), nil
+}
+
+func (s *tariffService) UpdateTariff(id uint, req entity.TariffRequest) (entity.TariffResponse, error) {
+	tariff, err := s.tariffRepo.FindByID(id)
+	if err != nil {
+		return entity.TariffResponse{}, ErrTariffNotFound
+	}
+	applyTariffRequest(tariff, req)
+	if err := s.validate(id, *tariff); err != nil {
+		return entity.TariffResponse{}, err
+	}
+
+	tariff, err = s.tariffRepo.Update(tariff)
+	if err != nil {
+		return entity.TariffResponse{}, err
+	}
+	return toTariffResponse(*tariff), nil
+}
+
+func (s *tariffService) DeleteTariff(id uint) error {
+	if _, err := s.tariffRepo.FindByID(id); err != nil {
+		return ErrTariffNotFound
+	}
+	return s.tariffRepo.DeleteByID(id)
+}
+
+// GetSchedule mencocokkan kode atau nama golongan, lalu mengumpulkan semua versi dengan kode yang sama
+func (s *tariffService) GetSchedule(code string) (helper.TariffSchedule, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrTariffNotFound
+	}
+
+	tariffs, err := s.tariffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := ""
+	for _, tariff := range tariffs {
+		if strings.EqualFold(tariff.Code, code) || strings.EqualFold(tariff.Name, code) {
+			matched = tariff.Code
+			break
+		}
+	}
+	if matched == "" {
+		return nil, ErrTariffNotFound
+	}
+
+	var versions []entity.TariffResponse
+	for _, tariff := range tariffs {
+		if strings.EqualFold(tariff.Code, matched) {
+			versions = append(versions, toTariffResponse(tariff))
+		}
+	}
+	return helper.NewTariffSchedule(versions), nil
+}
+
+func (s *tariffService) Seed() error {
+	count, err := s.tariffRepo.Count()
+	if err != nil || count > 0 {
+		return err
+	}
+
+	for _, req := range helper.DefaultTariffs() {
+		if _, err := s.CreateTariff(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"smart-home-energy-management-server/internal/entity"
+)
+
+type memoryTariffRepository struct {
+	tariffs []entity.Tariff
+}
+
+func (r *memoryTariffRepository) Create(tariff *entity.Tariff) (*entity.Tariff, error) {
+	tariff.ID = uint(len(r.tariffs) + 1)
+	r.tariffs = append(r.tariffs, *tariff)
+	return tariff, nil
+}
+
+func (r *memoryTariffRepository) FindAll() ([]entity.Tariff, error) {
+	return r.tariffs, nil
+}
+
+func (r *memoryTariffRepository) FindByID(id uint) (*entity.Tariff, error) {
+	for _, tariff := range r.tariffs {
+		if tariff.ID == id {
+			return &tariff, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryTariffRepository) Update(tariff *entity.Tariff) (*entity.Tariff, error) {
+	for i := range r.tariffs {
+		if r.tariffs[i].ID == tariff.ID {
+			r.tariffs[i] = *tariff
+		}
+	}
+	return tariff, nil
+}
+
+func (r *memoryTariffRepository) DeleteByID(id uint) error {
+	for i := range r.tariffs {
+		if r.tariffs[i].ID == id {
+			r.tariffs = append(r.tariffs[:i], r.tariffs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *memoryTariffRepository) Count() (int64, error) {
+	return int64(len(r.tariffs)), nil
+}
+
+func TestTariffService_SeedAndResolve(t *testing.T) {
+	service := NewTariffService(&memoryTariffRepository{})
+	if err := service.Seed(); err != nil {
+		t.Fatalf("Seed returned error: %v", err)
+	}
+
+	now := time.Now()
+	byCode, err := service.GetSchedule("r-1/1300")
+	if err != nil || byCode.RateAt(now) != 1444.70 {
+		t.Fatalf("expected R-1/1300 at 1444.70, got %+v (%v)", byCode, err)
+	}
+	// Nama golongan lama tetap dikenali
+	byName, err := service.GetSchedule("R-1/TR daya 1300 VA")
+	if err != nil || len(byName) != 1 || byName[0].ID != byCode[0].ID {
+		t.Fatalf("expected golongan name to resolve to %d, got %+v (%v)", byCode[0].ID, byName, err)
+	}
+	if _, err := service.GetSchedule("R-1/TR daya 1330 VA"); !errors.Is(err, ErrTariffNotFound) {
+		t.Fatalf("expected ErrTariffNotFound for typo, got %v", err)
+	}
+}
+
+func TestTariffService_RejectsOverlappingCode(t *testing.T) {
+	service := NewTariffService(&memoryTariffRepository{})
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	req := entity.TariffRequest{Code: "R-1/1300", Name: "R-1/TR daya 1300 VA", MinVA: 1300, MaxVA: 1300, PricePerKWh: 1444.70, EffectiveFrom: from, EffectiveTo: &to}
+	created, err := service.CreateTariff(req)
+	if err != nil {
+		t.Fatalf("CreateTariff returned error: %v", err)
+	}
+	if created.Currency != "IDR" {
+		t.Fatalf("expected default currency IDR, got %q", created.Currency)
+	}
+
+	req.EffectiveFrom, req.EffectiveTo = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil
+	if _, err := service.CreateTariff(req); !errors.Is(err, ErrTariffExists) {
+		t.Fatalf("expected ErrTariffExists for overlapping period, got %v", err)
+	}
+
+	req.EffectiveFrom, req.PricePerKWh = to, 1500
+	if _, err := service.CreateTariff(req); err != nil {
+		t.Fatalf("expected adjacent period to be accepted, got %v", err)
+	}
+
+	req.PricePerKWh = 0
+	if _, err := service.CreateTariff(req); err == nil {
+		t.Fatal("expected error for zero price")
+	}
+
+	// Kedua versi masuk ke jadwal yang sama dan dipilih menurut tanggal
+	schedule, err := service.GetSchedule("R-1/1300")
+	if err != nil || len(schedule) != 2 {
+		t.Fatalf("expected 2 versions, got %+v (%v)", schedule, err)
+	}
+	if rate := schedule.RateAt(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)); rate != 1444.70 {
+		t.Fatalf("expected first version rate in February, got %v", rate)
+	}
+	if rate := schedule.RateAt(to); rate != 1500 {
+		t.Fatalf("expected second version rate from April, got %v", rate)
+	}
+}
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"smart-home-energy-management-server/internal/entity"
 	"smart-home-energy-management-server/internal/helper"
 	"smart-home-energy-management-server/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 type UsersService interface {
@@ -94,7 +98,7 @@ func (user_serv *usersService) Login(user entity.UsersRequest) (*string, error)
 		return nil, errors.New("password is incorrect")
 	}
 
-	token, err := helper.GenerateToken(userExist.Name, userExist.Email, userExist.Premium)
+	token, err := helper.GenerateToken(userExist.ID, userExist.Name, userExist.Email, userExist.Premium, userExist.Admin)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +107,34 @@ func (user_serv *usersService) Login(user entity.UsersRequest) (*string, error)
 }
 
 func (user_serv *usersService) OAuthLogin(name string, email string, premium bool) (*string, error) {
-	token, err := helper.GenerateToken(name, email, premium)
+	// MENGAMBIL USER YANG SUDAH ADA ATAU MEMBUAT USER BARU AGAR TOKEN MEMILIKI ID
+	user, err := user_serv.userRepository.GetUserByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		// PASSWORD ACAK, USER OAUTH TIDAK LOGIN MENGGUNAKAN PASSWORD
+		randomPassword := make([]byte, 32)
+		if _, err := rand.Read(randomPassword); err != nil {
+			return nil, err
+		}
+		hashedPassword, err := helper.PasswordHashing(hex.EncodeToString(randomPassword))
+		if err != nil {
+			return nil, err
+		}
+
+		user, err = user_serv.userRepository.CreateUser(entity.Users{
+			Name:     name,
+			Email:    email,
+			Password: hashedPassword,
+			Premium:  premium,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := helper.GenerateToken(user.ID, user.Name, user.Email, user.Premium, user.Admin)
 	if err != nil {
 		return nil, err
 	}
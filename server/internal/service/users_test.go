@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"smart-home-energy-management-server/internal/entity"
+	"smart-home-energy-management-server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type oauthUsersRepository struct {
+	repository.UsersRepository
+	findErr error
+	created []entity.Users
+}
+
+func (r *oauthUsersRepository) GetUserByEmail(email string) (entity.Users, error) {
+	return entity.Users{}, r.findErr
+}
+
+func (r *oauthUsersRepository) CreateUser(user entity.Users) (entity.Users, error) {
+	user.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, user)
+	return user, nil
+}
+
+func TestOAuthLogin_CreatesUserOnlyWhenNotFound(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	tests := []struct {
+		name        string
+		findErr     error
+		wantErr     bool
+		wantCreated int
+	}{
+		{"not found creates user", fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound), false, 1},
+		{"database error is returned", errors.New("connection refused"), true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &oauthUsersRepository{findErr: tt.findErr}
+			token, err := NewUsersService(repo).OAuthLogin("Budi", "budi@example.com", false)
+			if tt.wantErr {
+				if !errors.Is(err, tt.findErr) || token != nil {
+					t.Fatalf("expected lookup error %v, got token %v err %v", tt.findErr, token, err)
+				}
+			} else if err != nil || token == nil {
+				t.Fatalf("expected token, got err %v", err)
+			}
+			if len(repo.created) != tt.wantCreated {
+				t.Fatalf("expected %d created users, got %d", tt.wantCreated, len(repo.created))
+			}
+		})
+	}
+}